@@ -1,19 +1,42 @@
 package main
 
 import (
+	"context"
 	"log"
+	"math/rand"
+	"time"
 
 	"avito-intern-test/internal/core"
+	akh "avito-intern-test/internal/handler/apikey"
+	ah "avito-intern-test/internal/handler/audit"
+	mw "avito-intern-test/internal/handler/middleware"
 	prh "avito-intern-test/internal/handler/pullrequest"
+	sh "avito-intern-test/internal/handler/stats"
 	th "avito-intern-test/internal/handler/team"
 	uh "avito-intern-test/internal/handler/user"
+	wh "avito-intern-test/internal/handler/webhook"
+	"avito-intern-test/internal/metrics"
+	apikeymodel "avito-intern-test/internal/model/apikey"
+	apikeyrepo "avito-intern-test/internal/repository/apikey"
+	auditrepo "avito-intern-test/internal/repository/audit"
+	idemrepo "avito-intern-test/internal/repository/idempotency"
+	outboxrepo "avito-intern-test/internal/repository/outbox"
 	prrepo "avito-intern-test/internal/repository/pullrequest"
+	statsrepo "avito-intern-test/internal/repository/stats"
 	teamrepo "avito-intern-test/internal/repository/team"
 	userrepo "avito-intern-test/internal/repository/user"
+	webhookrepo "avito-intern-test/internal/repository/webhook"
 	"avito-intern-test/internal/routing"
+	apikeysvc "avito-intern-test/internal/service/apikey"
+	auditsvc "avito-intern-test/internal/service/audit"
+	idemsvc "avito-intern-test/internal/service/idempotency"
+	outboxsvc "avito-intern-test/internal/service/outbox"
 	prsvc "avito-intern-test/internal/service/pullrequest"
+	statssvc "avito-intern-test/internal/service/stats"
 	teamsvc "avito-intern-test/internal/service/team"
 	usersvc "avito-intern-test/internal/service/user"
+	webhooksvc "avito-intern-test/internal/service/webhook"
+	"avito-intern-test/internal/tracing"
 )
 
 func main() {
@@ -22,29 +45,105 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.TracesExporter)
+	if err != nil {
+		log.Fatalf("Failed to init tracing: %v", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Printf("Error flushing traces: %v", err)
+		}
+	}()
+
 	dbPool := core.MustInitPool()
+	txManager := core.NewTxManager(dbPool)
 
 	teamRepo := teamrepo.NewTeamRepository(dbPool)
 	userRepo := userrepo.NewUserRepository(dbPool)
 	pullRequestRepo := prrepo.NewPullRequestRepository(dbPool)
+	statsRepo := statsrepo.NewStatsRepository(dbPool)
+	idempotencyRepo := idemrepo.NewIdempotencyRepository(dbPool)
+	webhookRepo := webhookrepo.NewWebhookRepository(dbPool)
+	outboxRepo := outboxrepo.NewOutboxRepository(dbPool)
+	auditRepo := auditrepo.NewAuditRepository(dbPool)
+	apiKeyRepo := apikeyrepo.NewAPIKeyRepository(dbPool)
+
+	metrics.RegisterPool(dbPool)
+	metrics.RegisterPullRequests(statsRepo)
+
+	apiKeyService := apikeysvc.NewAPIKeyService(txManager, apiKeyRepo, teamRepo, auditRepo)
+	if cfg.AdminAPIKey != "" {
+		bootstrap := apikeymodel.Key{Name: "bootstrap-admin", Role: core.RoleAdmin}
+		if err := apiKeyService.EnsureKey(context.Background(), bootstrap, cfg.AdminAPIKey); err != nil {
+			log.Fatalf("Failed to register admin API key: %v", err)
+		}
+	}
+
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+
+	idempotencyService := idemsvc.NewIdempotencyService(idempotencyRepo, idemsvc.DefaultConfig)
+	go idempotencyService.Run(relayCtx)
+
+	dispatcher := webhooksvc.NewDispatcher(webhookRepo, nil, webhooksvc.DefaultDispatcherConfig)
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		outboxsvc.NewRelay(txManager, outboxRepo, dispatcher, outboxsvc.DefaultRelayConfig).Run(relayCtx)
+	}()
+	stopBackground := func() {
+		stopRelay()
+		<-relayDone
+		dispatcher.Wait()
+	}
+
+	prService := prsvc.NewPRService(
+		txManager,
+		userRepo,
+		teamRepo,
+		pullRequestRepo,
+		prsvc.NewReviewerSelectors(rand.New(rand.NewSource(time.Now().UnixNano()))),
+		outboxRepo,
+		auditRepo,
+	)
 
 	core.StartServer(
 		dbPool,
 		cfg.Port,
 		routing.Router(
-			prh.NewPullRequestHandler(prsvc.NewPRService(
-				userRepo,
-				teamRepo,
-				pullRequestRepo,
-			)),
+			prh.NewPullRequestHandler(prService),
 			th.NewTeamHandler(teamsvc.NewTeamService(
+				txManager,
 				teamRepo,
 				userRepo,
+				prService,
+				outboxRepo,
+				auditRepo,
 			)),
 			uh.NewUserHandler(usersvc.NewUserService(
+				txManager,
 				userRepo,
 				pullRequestRepo,
+				teamRepo,
+				prService,
+				outboxRepo,
+				auditRepo,
+			)),
+			sh.NewStatsHandler(statssvc.NewStatsService(
+				statsRepo,
+				teamRepo,
+			)),
+			wh.NewWebhookHandler(webhooksvc.NewWebhookService(
+				txManager,
+				webhookRepo,
+				auditRepo,
 			)),
+			ah.NewAuditHandler(auditsvc.NewAuditService(auditRepo)),
+			akh.NewAPIKeyHandler(apiKeyService),
+			mw.NewAuth(apiKeyService),
+			mw.NewIdempotency(idempotencyService),
+			metrics.Handler(),
 		),
+		stopBackground,
 	)
 }
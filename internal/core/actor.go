@@ -0,0 +1,19 @@
+package core
+
+import "context"
+
+// AnonymousActor is recorded when a request does not identify its caller.
+const AnonymousActor = "anonymous"
+
+type actorKey struct{}
+
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"context"
+	"strconv"
+)
+
+type Role string
+
+const (
+	RoleAdmin    Role = "admin"
+	RoleTeamLead Role = "team_lead"
+	RoleBot      Role = "bot"
+	RoleReader   Role = "reader"
+)
+
+func (r Role) Valid() bool {
+	switch r {
+	case RoleAdmin, RoleTeamLead, RoleBot, RoleReader:
+		return true
+	}
+	return false
+}
+
+// Principal is the authenticated caller. TeamName is set for team leads
+// only and limits them to their own team.
+type Principal struct {
+	KeyID    int64
+	Name     string
+	Role     Role
+	TeamName string
+}
+
+// HasRole reports whether the principal may act as any of roles. Admins
+// may act as everyone.
+func (p Principal) HasRole(roles ...Role) bool {
+	if p.Role == RoleAdmin {
+		return true
+	}
+	for _, r := range roles {
+		if p.Role == r {
+			return true
+		}
+	}
+	return false
+}
+
+// Actor names the principal in audit entries. Key names are not unique, so
+// the key ID is included.
+func (p Principal) Actor() string {
+	return p.Name + "#" + strconv.FormatInt(p.KeyID, 10)
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// AuthorizeTeam rejects team leads acting on a team other than their own.
+// Other principals, and calls without one, are checked by route roles only.
+func AuthorizeTeam(ctx context.Context, teamName string) error {
+	p, ok := PrincipalFrom(ctx)
+	if !ok || p.Role != RoleTeamLead || p.TeamName == teamName {
+		return nil
+	}
+	return NewDomainError(ErrorForbidden, "team leads may only manage their own team").
+		WithDetails(map[string]any{"team_name": teamName})
+}
@@ -15,6 +15,13 @@ type Config struct {
 	DBPassword string
 	DBName     string
 	DBSSLMode  string
+
+	// AdminAPIKey, when set, is registered as an admin key on startup so a
+	// fresh deployment can issue the rest of its keys.
+	AdminAPIKey string
+
+	// TracesExporter is one of otlp, console or none (the default).
+	TracesExporter string
 }
 
 func LoadConfig() (*Config, error) {
@@ -29,6 +36,8 @@ func LoadConfig() (*Config, error) {
 	cfg.DBPassword = os.Getenv("POSTGRES_PASSWORD")
 	cfg.DBName = os.Getenv("POSTGRES_DB")
 	cfg.DBSSLMode = os.Getenv("POSTGRES_SSLMODE")
+	cfg.AdminAPIKey = os.Getenv("ADMIN_API_KEY")
+	cfg.TracesExporter = os.Getenv("OTEL_TRACES_EXPORTER")
 
 	return cfg, nil
 }
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"avito-intern-test/internal/tracing"
 )
 
 func MustInitPool() *pgxpool.Pool {
@@ -19,6 +21,7 @@ func MustInitPool() *pgxpool.Pool {
 	cfg.MinConns = 2
 	cfg.MaxConnLifetime = time.Hour
 	cfg.MaxConnIdleTime = time.Minute * 30
+	cfg.ConnConfig.Tracer = tracing.QueryTracer{}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
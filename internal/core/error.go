@@ -1,6 +1,10 @@
 package core
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
 
 const (
 	ErrorTeamExists  string = "TEAM_EXISTS"
@@ -10,8 +14,75 @@ const (
 	ErrorNoCandidate string = "NO_CANDIDATE"
 	ErrorNotFound    string = "NOT_FOUND"
 	ErrorUserExists  string = "USER_EXISTS"
+
+	ErrorInvalidTransition string = "INVALID_TRANSITION"
+	ErrorPRNotOpen         string = "PR_NOT_OPEN"
+	ErrorNotApproved       string = "NOT_APPROVED"
+
+	ErrorIdempotencyMismatch   string = "IDEMPOTENCY_KEY_MISMATCH"
+	ErrorIdempotencyInProgress string = "IDEMPOTENCY_KEY_IN_PROGRESS"
+
+	ErrorUnauthorized string = "UNAUTHORIZED"
+	ErrorForbidden    string = "FORBIDDEN"
 )
 
+var httpStatusByCode = map[string]int{
+	ErrorTeamExists:  http.StatusBadRequest,
+	ErrorPRExists:    http.StatusConflict,
+	ErrorPRMerged:    http.StatusConflict,
+	ErrorNotAssigned: http.StatusConflict,
+	ErrorNoCandidate: http.StatusConflict,
+	ErrorNotFound:    http.StatusNotFound,
+	ErrorUserExists:  http.StatusConflict,
+
+	ErrorInvalidTransition: http.StatusConflict,
+	ErrorPRNotOpen:         http.StatusConflict,
+	ErrorNotApproved:       http.StatusConflict,
+
+	ErrorIdempotencyMismatch:   http.StatusUnprocessableEntity,
+	ErrorIdempotencyInProgress: http.StatusConflict,
+
+	ErrorUnauthorized: http.StatusUnauthorized,
+	ErrorForbidden:    http.StatusForbidden,
+}
+
+type DomainError struct {
+	Code    string
+	Message string
+	Details map[string]any
+}
+
+func (e *DomainError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func (e *DomainError) HTTPStatus() int {
+	if status, ok := httpStatusByCode[e.Code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+func (e *DomainError) WithDetails(details map[string]any) *DomainError {
+	return &DomainError{
+		Code:    e.Code,
+		Message: e.Message,
+		Details: details,
+	}
+}
+
+func NewDomainError(code string, msg string) *DomainError {
+	return &DomainError{Code: code, Message: msg}
+}
+
 func Throw(code string, msg string) error {
-	return fmt.Errorf("%s: %s", code, msg)
+	return NewDomainError(code, msg)
+}
+
+func AsDomainError(err error) (*DomainError, bool) {
+	var domainErr *DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr, true
+	}
+	return nil, false
 }
@@ -14,7 +14,9 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func StartServer(dbPool *pgxpool.Pool, port string, appRoutes *chi.Mux) {
+// StartServer serves until SIGINT or SIGTERM. The onShutdown hooks run after
+// the HTTP server has stopped and before the pool is closed.
+func StartServer(dbPool *pgxpool.Pool, port string, appRoutes *chi.Mux, onShutdown ...func()) {
 	srv := &http.Server{
 		Addr:    ":" + port,
 		Handler: appRoutes,
@@ -29,12 +31,12 @@ func StartServer(dbPool *pgxpool.Pool, port string, appRoutes *chi.Mux) {
 		}
 	}()
 
-	waitGracefulShutdown(srv, dbPool, serverErr)
+	waitGracefulShutdown(srv, dbPool, serverErr, onShutdown)
 
 	log.Println("Shutting down...")
 }
 
-func waitGracefulShutdown(srv *http.Server, dbPool *pgxpool.Pool, serverErr <-chan error) {
+func waitGracefulShutdown(srv *http.Server, dbPool *pgxpool.Pool, serverErr <-chan error, onShutdown []func()) {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
@@ -57,6 +59,9 @@ func waitGracefulShutdown(srv *http.Server, dbPool *pgxpool.Pool, serverErr <-ch
 	} else {
 		log.Println("Server shutdown")
 	}
+	for _, hook := range onShutdown {
+		hook()
+	}
 	dbPool.Close()
 	log.Println("Database connection pool closed")
 	log.Println("Service shudown")
@@ -0,0 +1,13 @@
+package handler
+
+import (
+	"context"
+
+	apikeymodel "avito-intern-test/internal/model/apikey"
+)
+
+type apiKeyService interface {
+	IssueKey(ctx context.Context, key apikeymodel.Key) (apikeymodel.Key, string, error)
+	ListKeys(ctx context.Context) ([]apikeymodel.Key, error)
+	RevokeKey(ctx context.Context, keyID int64) (apikeymodel.Key, error)
+}
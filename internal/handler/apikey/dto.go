@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"time"
+
+	apikeymodel "avito-intern-test/internal/model/apikey"
+)
+
+type CreateAPIKeyRequest struct {
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+	TeamName string `json:"team_name,omitempty"`
+}
+
+type RevokeAPIKeyRequest struct {
+	KeyID int64 `json:"key_id"`
+}
+
+type APIKeyDTO struct {
+	KeyID     int64      `json:"key_id"`
+	Name      string     `json:"name"`
+	Role      string     `json:"role"`
+	TeamName  string     `json:"team_name,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+	Key       string     `json:"key,omitempty"`
+}
+
+type APIKeyResponse struct {
+	APIKey APIKeyDTO `json:"api_key"`
+}
+
+type ListAPIKeysResponse struct {
+	APIKeys []APIKeyDTO `json:"api_keys"`
+}
+
+func keyToDTO(k apikeymodel.Key) APIKeyDTO {
+	return APIKeyDTO{
+		KeyID:     k.ID,
+		Name:      k.Name,
+		Role:      string(k.Role),
+		TeamName:  k.TeamName,
+		CreatedAt: k.CreatedAt,
+		RevokedAt: k.RevokedAt,
+	}
+}
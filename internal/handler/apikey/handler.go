@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"avito-intern-test/internal/core"
+	"avito-intern-test/internal/handler/common"
+	apikeymodel "avito-intern-test/internal/model/apikey"
+)
+
+const maxKeyNameLength = 128
+
+type APIKeyHandler struct {
+	service apiKeyService
+}
+
+func NewAPIKeyHandler(service apiKeyService) *APIKeyHandler {
+	return &APIKeyHandler{service: service}
+}
+
+// CreateAPIKey issues a key. The key itself is only ever returned here.
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "invalid json body")
+		return
+	}
+	if msg := validateCreate(&req); msg != "" {
+		common.RespondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	key, token, err := h.service.IssueKey(ctx, apikeymodel.Key{
+		Name:     req.Name,
+		Role:     core.Role(req.Role),
+		TeamName: req.TeamName,
+	})
+	if err != nil {
+		common.RespondError(w, err)
+	} else {
+		dto := keyToDTO(key)
+		dto.Key = token
+		common.RespondWithJSON(w, http.StatusCreated, APIKeyResponse{APIKey: dto})
+	}
+}
+
+func (h *APIKeyHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.service.ListKeys(r.Context())
+	if err != nil {
+		common.RespondError(w, err)
+	} else {
+		items := make([]APIKeyDTO, 0, len(keys))
+		for _, k := range keys {
+			items = append(items, keyToDTO(k))
+		}
+		common.RespondWithJSON(w, http.StatusOK, ListAPIKeysResponse{APIKeys: items})
+	}
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req RevokeAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "invalid json body")
+	} else if req.KeyID <= 0 {
+		common.RespondWithError(w, http.StatusBadRequest, "key_id is required")
+	} else if key, err := h.service.RevokeKey(ctx, req.KeyID); err != nil {
+		common.RespondError(w, err)
+	} else {
+		common.RespondWithJSON(w, http.StatusOK, APIKeyResponse{APIKey: keyToDTO(key)})
+	}
+}
+
+func validateCreate(req *CreateAPIKeyRequest) string {
+	req.Name = strings.TrimSpace(req.Name)
+	switch {
+	case req.Name == "":
+		return "name is required"
+	case len(req.Name) > maxKeyNameLength:
+		return "name is too long"
+	case !core.Role(req.Role).Valid():
+		return "role must be one of admin, team_lead, bot, reader"
+	case core.Role(req.Role) == core.RoleTeamLead && req.TeamName == "":
+		return "team_name is required for team_lead keys"
+	case core.Role(req.Role) != core.RoleTeamLead && req.TeamName != "":
+		return "team_name is only allowed for team_lead keys"
+	}
+	return ""
+}
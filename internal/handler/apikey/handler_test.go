@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"avito-intern-test/internal/core"
+	apikeymodel "avito-intern-test/internal/model/apikey"
+)
+
+type apiKeyServiceMock struct {
+	issued *apikeymodel.Key
+}
+
+func (m *apiKeyServiceMock) IssueKey(_ context.Context, key apikeymodel.Key) (apikeymodel.Key, string, error) {
+	m.issued = &key
+	key.ID = 1
+	return key, "ak_token", nil
+}
+
+func (m *apiKeyServiceMock) ListKeys(context.Context) ([]apikeymodel.Key, error) {
+	return []apikeymodel.Key{{ID: 1, Name: "ci", Role: core.RoleBot}}, nil
+}
+
+func (m *apiKeyServiceMock) RevokeKey(_ context.Context, keyID int64) (apikeymodel.Key, error) {
+	if keyID != 1 {
+		return apikeymodel.Key{}, core.Throw(core.ErrorNotFound, "api key not found")
+	}
+	return apikeymodel.Key{ID: 1}, nil
+}
+
+func TestAPIKeyHandler_Create(t *testing.T) {
+	m := &apiKeyServiceMock{}
+	h := NewAPIKeyHandler(m)
+
+	body := `{"name":" lead ","role":"team_lead","team_name":"backend"}`
+	rec := httptest.NewRecorder()
+	h.CreateAPIKey(rec, httptest.NewRequest(http.MethodPost, "/admin/apiKeys/create", bytes.NewBufferString(body)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var resp APIKeyResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.APIKey.Key != "ak_token" {
+		t.Fatalf("key = %q, want token in create response", resp.APIKey.Key)
+	}
+	if m.issued.Name != "lead" || m.issued.Role != core.RoleTeamLead || m.issued.TeamName != "backend" {
+		t.Fatalf("issued = %+v", m.issued)
+	}
+}
+
+func TestAPIKeyHandler_CreateValidation(t *testing.T) {
+	cases := map[string]string{
+		"no name":        `{"role":"bot"}`,
+		"bad role":       `{"name":"x","role":"root"}`,
+		"lead no team":   `{"name":"x","role":"team_lead"}`,
+		"team for a bot": `{"name":"x","role":"bot","team_name":"backend"}`,
+	}
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &apiKeyServiceMock{}
+			rec := httptest.NewRecorder()
+			NewAPIKeyHandler(m).CreateAPIKey(rec, httptest.NewRequest(http.MethodPost, "/admin/apiKeys/create", bytes.NewBufferString(body)))
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", rec.Code)
+			}
+			if m.issued != nil {
+				t.Fatal("service called for invalid request")
+			}
+		})
+	}
+}
+
+func TestAPIKeyHandler_ListHidesKeys(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewAPIKeyHandler(&apiKeyServiceMock{}).ListAPIKeys(rec, httptest.NewRequest(http.MethodGet, "/admin/apiKeys/list", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if bytes.Contains(rec.Body.Bytes(), []byte(`"key"`)) {
+		t.Fatalf("list leaked key: %s", rec.Body)
+	}
+}
+
+func TestAPIKeyHandler_Revoke(t *testing.T) {
+	h := NewAPIKeyHandler(&apiKeyServiceMock{})
+	cases := map[string]int{
+		`{"key_id":1}`: http.StatusOK,
+		`{"key_id":2}`: http.StatusNotFound,
+		`{}`:           http.StatusBadRequest,
+	}
+	for body, want := range cases {
+		rec := httptest.NewRecorder()
+		h.RevokeAPIKey(rec, httptest.NewRequest(http.MethodPost, "/admin/apiKeys/revoke", bytes.NewBufferString(body)))
+		if rec.Code != want {
+			t.Fatalf("%s: status = %d, want %d", body, rec.Code, want)
+		}
+	}
+}
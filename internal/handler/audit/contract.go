@@ -0,0 +1,11 @@
+package handler
+
+import (
+	"context"
+
+	auditmodel "avito-intern-test/internal/model/audit"
+)
+
+type auditService interface {
+	ListEntries(ctx context.Context, filter auditmodel.ListFilter) (auditmodel.Page, error)
+}
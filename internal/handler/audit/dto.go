@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"time"
+
+	auditmodel "avito-intern-test/internal/model/audit"
+)
+
+type AuditEntryDTO struct {
+	AuditID    int64     `json:"audit_id"`
+	Actor      string    `json:"actor"`
+	Action     string    `json:"action"`
+	EntityType string    `json:"entity_type"`
+	EntityID   string    `json:"entity_id"`
+	Before     any       `json:"before"`
+	After      any       `json:"after"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type ListAuditResponse struct {
+	Entries    []AuditEntryDTO `json:"entries"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+func entryToDTO(e auditmodel.Entry) AuditEntryDTO {
+	return AuditEntryDTO{
+		AuditID:    e.ID,
+		Actor:      e.Actor,
+		Action:     string(e.Action),
+		EntityType: string(e.EntityType),
+		EntityID:   e.EntityID,
+		Before:     e.Before,
+		After:      e.After,
+		CreatedAt:  e.CreatedAt,
+	}
+}
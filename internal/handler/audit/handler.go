@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"avito-intern-test/internal/handler/common"
+	auditmodel "avito-intern-test/internal/model/audit"
+)
+
+type AuditHandler struct {
+	service auditService
+}
+
+func NewAuditHandler(service auditService) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+func (h *AuditHandler) ListAudit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	filter, msg := parseAuditFilter(r)
+	if msg != "" {
+		common.RespondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	page, err := h.service.ListEntries(ctx, filter)
+	if err != nil {
+		common.RespondError(w, err)
+	} else {
+		items := make([]AuditEntryDTO, 0, len(page.Entries))
+		for _, e := range page.Entries {
+			items = append(items, entryToDTO(e))
+		}
+		resp := ListAuditResponse{Entries: items}
+		if page.Next != 0 {
+			resp.NextCursor = auditmodel.EncodeCursor(page.Next)
+		}
+		common.RespondWithJSON(w, http.StatusOK, resp)
+	}
+}
+
+func parseAuditFilter(r *http.Request) (auditmodel.ListFilter, string) {
+	q := r.URL.Query()
+	filter := auditmodel.ListFilter{
+		Actor:      q.Get("actor"),
+		Action:     auditmodel.Action(q.Get("action")),
+		EntityType: auditmodel.EntityType(q.Get("entity_type")),
+		EntityID:   q.Get("entity_id"),
+		Limit:      auditmodel.DefaultListLimit,
+	}
+	if filter.EntityType != "" && !filter.EntityType.Valid() {
+		return filter, "entity_type must be one of pull_request, team, user, webhook, api_key"
+	}
+	if raw := q.Get("since"); raw != "" {
+		since, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return filter, "since must be an RFC3339 timestamp"
+		}
+		filter.Since = &since
+	}
+	if raw := q.Get("until"); raw != "" {
+		until, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return filter, "until must be an RFC3339 timestamp"
+		}
+		filter.Until = &until
+	}
+	if raw := q.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > auditmodel.MaxListLimit {
+			return filter, fmt.Sprintf("limit must be between 1 and %d", auditmodel.MaxListLimit)
+		}
+		filter.Limit = limit
+	}
+	if raw := q.Get("cursor"); raw != "" {
+		after, err := auditmodel.DecodeCursor(raw)
+		if err != nil {
+			return filter, "cursor is invalid"
+		}
+		filter.After = after
+	}
+	return filter, ""
+}
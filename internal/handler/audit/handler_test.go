@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	auditmodel "avito-intern-test/internal/model/audit"
+)
+
+type auditServiceMock struct {
+	filter auditmodel.ListFilter
+}
+
+func (m *auditServiceMock) ListEntries(_ context.Context, filter auditmodel.ListFilter) (auditmodel.Page, error) {
+	m.filter = filter
+	return auditmodel.Page{
+		Entries: []auditmodel.Entry{{
+			ID:         9,
+			Actor:      "alice",
+			Action:     auditmodel.ActionUserSetIsActive,
+			EntityType: auditmodel.EntityUser,
+			EntityID:   "u1",
+			Before:     json.RawMessage(`{"is_active":true}`),
+			After:      json.RawMessage(`{"is_active":false}`),
+		}},
+		Next: 9,
+	}, nil
+}
+
+func TestAuditHandler_ListAudit(t *testing.T) {
+	m := &auditServiceMock{}
+	h := NewAuditHandler(m)
+	req := httptest.NewRequest(http.MethodGet,
+		"/audit/list?actor=alice&entity_type=user&entity_id=u1&since=2026-10-01T00:00:00Z&limit=1&cursor="+
+			auditmodel.EncodeCursor(20), nil)
+	w := httptest.NewRecorder()
+	h.ListAudit(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body=%s", w.Code, w.Body.String())
+	}
+	if m.filter.Actor != "alice" || m.filter.EntityType != auditmodel.EntityUser || m.filter.EntityID != "u1" ||
+		m.filter.Since == nil || m.filter.Limit != 1 || m.filter.After != 20 {
+		t.Fatalf("unexpected filter: %+v", m.filter)
+	}
+
+	var resp struct {
+		Entries []struct {
+			Before map[string]bool `json:"before"`
+			After  map[string]bool `json:"after"`
+		} `json:"entries"`
+		NextCursor string `json:"next_cursor"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Entries) != 1 || !resp.Entries[0].Before["is_active"] || resp.Entries[0].After["is_active"] ||
+		resp.NextCursor != auditmodel.EncodeCursor(9) {
+		t.Fatalf("unexpected response: %s", w.Body.String())
+	}
+}
+
+func TestAuditHandler_ListAudit_BadParams(t *testing.T) {
+	h := NewAuditHandler(&auditServiceMock{})
+	for _, q := range []string{"entity_type=repo", "since=yesterday", "until=soon", "limit=0", "cursor=bm9wZQ"} {
+		req := httptest.NewRequest(http.MethodGet, "/audit/list?"+q, nil)
+		w := httptest.NewRecorder()
+		h.ListAudit(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", q, w.Code)
+		}
+	}
+}
@@ -3,7 +3,8 @@ package common
 import (
 	"encoding/json"
 	"net/http"
-	"strings"
+
+	"avito-intern-test/internal/core"
 )
 
 func RespondWithJSON(w http.ResponseWriter, httpStatus int, data interface{}) {
@@ -20,18 +21,35 @@ func RespondWithError(w http.ResponseWriter, httpStatus int, message string) {
 
 type apiErrorBody struct {
 	Error struct {
-		Code    string `json:"code"`
-		Message string `json:"message"`
+		Code    string         `json:"code"`
+		Message string         `json:"message"`
+		Details map[string]any `json:"details,omitempty"`
 	} `json:"error"`
 }
 
 func RespondAPIError(w http.ResponseWriter, httpStatus int, code, message string) {
+	respondAPIError(w, httpStatus, code, message, nil)
+}
+
+// RespondError is the single place where service errors are turned into
+// HTTP responses: domain errors anywhere in the chain are reported with their
+// own code and status, everything else is an internal error.
+func RespondError(w http.ResponseWriter, err error) {
+	if domainErr, ok := core.AsDomainError(err); ok {
+		respondAPIError(w, domainErr.HTTPStatus(), domainErr.Code, domainErr.Message, domainErr.Details)
+		return
+	}
+	RespondWithError(w, http.StatusInternalServerError, err.Error())
+}
+
+func respondAPIError(w http.ResponseWriter, httpStatus int, code, message string, details map[string]any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(httpStatus)
 	var body apiErrorBody
 	body.Reset()
 	body.Error.Code = code
 	body.Error.Message = message
+	body.Error.Details = details
 	if err := json.NewEncoder(w).Encode(body); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
@@ -40,21 +58,5 @@ func RespondAPIError(w http.ResponseWriter, httpStatus int, code, message string
 func (b *apiErrorBody) Reset() {
 	b.Error.Code = ""
 	b.Error.Message = ""
-}
-
-func ParseCodeMessage(err error) (string, string, bool) {
-	if err == nil {
-		return "", "", false
-	}
-	msg := err.Error()
-	parts := strings.SplitN(msg, ":", 2)
-	if len(parts) != 2 {
-		return "", "", false
-	}
-	code := strings.TrimSpace(parts[0])
-	detail := strings.TrimSpace(parts[1])
-	if code == "" || detail == "" {
-		return "", "", false
-	}
-	return code, detail, true
+	b.Error.Details = nil
 }
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"avito-intern-test/internal/core"
+)
+
+const (
+	HeaderActor = "X-Actor"
+
+	maxActorLength = 128
+)
+
+// Actor puts the caller named by the X-Actor header into the request context.
+func Actor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor := strings.TrimSpace(r.Header.Get(HeaderActor))
+		if len(actor) > maxActorLength {
+			actor = actor[:maxActorLength]
+		}
+		if actor != "" {
+			r = r.WithContext(core.WithActor(r.Context(), actor))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"avito-intern-test/internal/core"
+)
+
+func TestActor(t *testing.T) {
+	var got string
+	h := Actor(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = core.ActorFrom(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/team/add", nil)
+	req.Header.Set(HeaderActor, "  alice ")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if got != "alice" {
+		t.Fatalf("expected alice, got %q", got)
+	}
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/team/add", nil))
+	if got != core.AnonymousActor {
+		t.Fatalf("expected anonymous actor, got %q", got)
+	}
+}
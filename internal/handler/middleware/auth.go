@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"avito-intern-test/internal/core"
+	common "avito-intern-test/internal/handler/common"
+)
+
+const HeaderAPIKey = "X-API-Key"
+
+type Auth struct {
+	service authService
+}
+
+func NewAuth(service authService) *Auth {
+	return &Auth{service: service}
+}
+
+// Handle authenticates the API key sent as a bearer token or in X-API-Key.
+// The key's name and ID replace any X-Actor header, so audit entries cannot
+// be attributed to someone else.
+func (m *Auth) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := apiKey(r)
+		if token == "" {
+			common.RespondAPIError(w, http.StatusUnauthorized, core.ErrorUnauthorized, "API key required")
+			return
+		}
+		principal, err := m.service.Authenticate(r.Context(), token)
+		if err != nil {
+			common.RespondError(w, err)
+			return
+		}
+		ctx := core.WithPrincipal(r.Context(), principal)
+		ctx = core.WithActor(ctx, principal.Actor())
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequireRole lets through principals holding any of roles; admins always pass.
+func RequireRole(roles ...core.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := core.PrincipalFrom(r.Context())
+			if !ok {
+				common.RespondAPIError(w, http.StatusUnauthorized, core.ErrorUnauthorized, "API key required")
+				return
+			}
+			if !principal.HasRole(roles...) {
+				common.RespondAPIError(w, http.StatusForbidden, core.ErrorForbidden, "insufficient role")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func apiKey(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		scheme, token, ok := strings.Cut(auth, " ")
+		if ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+		return ""
+	}
+	return strings.TrimSpace(r.Header.Get(HeaderAPIKey))
+}
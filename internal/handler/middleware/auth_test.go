@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"avito-intern-test/internal/core"
+)
+
+type authServiceMock struct {
+	keys map[string]core.Principal
+}
+
+func (m authServiceMock) Authenticate(_ context.Context, token string) (core.Principal, error) {
+	p, ok := m.keys[token]
+	if !ok {
+		return core.Principal{}, core.Throw(core.ErrorUnauthorized, "invalid API key")
+	}
+	return p, nil
+}
+
+func newTestAuth() *Auth {
+	return NewAuth(authServiceMock{keys: map[string]core.Principal{
+		"admin-key": {KeyID: 1, Name: "root", Role: core.RoleAdmin},
+		"bot-key":   {KeyID: 2, Name: "ci", Role: core.RoleBot},
+	}})
+}
+
+func TestAuth_Handle(t *testing.T) {
+	var actor string
+	h := newTestAuth().Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor = core.ActorFrom(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	cases := []struct {
+		name   string
+		header string
+		value  string
+		status int
+	}{
+		{"missing", "", "", http.StatusUnauthorized},
+		{"invalid", "Authorization", "Bearer nope", http.StatusUnauthorized},
+		{"wrong scheme", "Authorization", "Basic bot-key", http.StatusUnauthorized},
+		{"bearer", "Authorization", "Bearer bot-key", http.StatusNoContent},
+		{"header", HeaderAPIKey, "bot-key", http.StatusNoContent},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/team/get", nil)
+			req.Header.Set(HeaderActor, "mallory")
+			if tc.header != "" {
+				req.Header.Set(tc.header, tc.value)
+			}
+			rec := httptest.NewRecorder()
+			Actor(h).ServeHTTP(rec, req)
+			if rec.Code != tc.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tc.status)
+			}
+			if tc.status == http.StatusNoContent && actor != "ci#2" {
+				t.Fatalf("actor = %q, want key name and ID", actor)
+			}
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	h := newTestAuth().Handle(RequireRole(core.RoleTeamLead)(ok))
+
+	cases := map[string]int{
+		"admin-key": http.StatusNoContent,
+		"bot-key":   http.StatusForbidden,
+	}
+	for key, want := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/team/settings", nil)
+		req.Header.Set(HeaderAPIKey, key)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Fatalf("%s: status = %d, want %d", key, rec.Code, want)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	RequireRole(core.RoleReader)(ok).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/team/get", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("no principal: status = %d, want 401", rec.Code)
+	}
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"context"
+
+	"avito-intern-test/internal/core"
+	idemmodel "avito-intern-test/internal/model/idempotency"
+)
+
+type idempotencyService interface {
+	Begin(ctx context.Context, key, route, requestHash string) (string, *idemmodel.Record, error)
+	Complete(ctx context.Context, rec idemmodel.Record) error
+	Abandon(ctx context.Context, key, route, lease string) error
+}
+
+type authService interface {
+	Authenticate(ctx context.Context, token string) (core.Principal, error)
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"avito-intern-test/internal/core"
+	common "avito-intern-test/internal/handler/common"
+	idemmodel "avito-intern-test/internal/model/idempotency"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+type Idempotency struct {
+	service idempotencyService
+}
+
+func NewIdempotency(service idempotencyService) *Idempotency {
+	return &Idempotency{service: service}
+}
+
+// Handle replays the stored response for POST requests that repeat an
+// Idempotency-Key. Requests without the header pass through untouched.
+func (m *Idempotency) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if r.Method != http.MethodPost || key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			common.RespondWithError(w, http.StatusBadRequest, "Idempotency-Key is too long")
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			common.RespondWithError(w, http.StatusBadRequest, "failed to read body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		route := r.Method + " " + r.URL.Path
+		// Keys are scoped to the caller, so nobody can replay a response
+		// that was issued to another API key.
+		if p, ok := core.PrincipalFrom(r.Context()); ok {
+			route += " key:" + strconv.FormatInt(p.KeyID, 10)
+		}
+		ctx := context.WithoutCancel(r.Context())
+
+		lease, rec, err := m.service.Begin(ctx, key, route, requestHash(r, body))
+		if err != nil {
+			common.RespondError(w, err)
+			return
+		}
+		if rec != nil {
+			replay(w, rec)
+			return
+		}
+
+		rw := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			if p := recover(); p != nil {
+				_ = m.service.Abandon(ctx, key, route, lease)
+				panic(p)
+			}
+		}()
+		next.ServeHTTP(rw, r)
+
+		if rw.status >= http.StatusInternalServerError {
+			if err := m.service.Abandon(ctx, key, route, lease); err != nil {
+				log.Printf("abandon idempotency key %q: %v", key, err)
+			}
+			return
+		}
+		if err := m.service.Complete(ctx, idemmodel.Record{
+			Key:         key,
+			Route:       route,
+			Lease:       lease,
+			StatusCode:  rw.status,
+			ContentType: rw.Header().Get("Content-Type"),
+			Body:        rw.body.Bytes(),
+		}); err != nil {
+			log.Printf("complete idempotency key %q: %v", key, err)
+		}
+	})
+}
+
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.URL.RawQuery))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func replay(w http.ResponseWriter, rec *idemmodel.Record) {
+	if rec.ContentType != "" {
+		w.Header().Set("Content-Type", rec.ContentType)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(rec.StatusCode)
+	_, _ = w.Write(rec.Body)
+}
+
+type recordingWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (w *recordingWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"avito-intern-test/internal/core"
+	idemmodel "avito-intern-test/internal/model/idempotency"
+)
+
+type idempotencyServiceMock struct {
+	records   map[string]idemmodel.Record
+	abandoned []string
+}
+
+func (m *idempotencyServiceMock) Begin(_ context.Context, key, route, hash string) (string, *idemmodel.Record, error) {
+	if m.records == nil {
+		m.records = map[string]idemmodel.Record{}
+	}
+	rec, ok := m.records[key+route]
+	if !ok {
+		m.records[key+route] = idemmodel.Record{Key: key, Route: route, RequestHash: hash, Lease: "lease"}
+		return "lease", nil, nil
+	}
+	if rec.RequestHash != hash {
+		return "", nil, core.Throw(core.ErrorIdempotencyMismatch, "mismatch")
+	}
+	return "", &rec, nil
+}
+
+func (m *idempotencyServiceMock) Complete(_ context.Context, rec idemmodel.Record) error {
+	rec.RequestHash = m.records[rec.Key+rec.Route].RequestHash
+	m.records[rec.Key+rec.Route] = rec
+	return nil
+}
+
+func (m *idempotencyServiceMock) Abandon(_ context.Context, key, route, _ string) error {
+	m.abandoned = append(m.abandoned, key)
+	delete(m.records, key+route)
+	return nil
+}
+
+func send(h http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/team/add", bytes.NewBufferString(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency_ReplaysStoredResponse(t *testing.T) {
+	calls := 0
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"team":"backend"}`))
+	})
+	h := NewIdempotency(&idempotencyServiceMock{}).Handle(next)
+
+	first := send(h, "k1", `{"team_name":"backend"}`)
+	second := send(h, "k1", `{"team_name":"backend"}`)
+	if calls != 1 {
+		t.Fatalf("expected handler to run once, ran %d times", calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Fatalf("expected replay of %d %q, got %d %q", first.Code, first.Body.String(), second.Code, second.Body.String())
+	}
+	if second.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Fatalf("expected replay header")
+	}
+
+	mismatch := send(h, "k1", `{"team_name":"other"}`)
+	if mismatch.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", mismatch.Code)
+	}
+
+	send(h, "", `{"team_name":"backend"}`)
+	if calls != 2 {
+		t.Fatalf("requests without a key must pass through")
+	}
+}
+
+func TestIdempotency_ServerErrorIsNotStored(t *testing.T) {
+	calls := 0
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	svc := &idempotencyServiceMock{}
+	h := NewIdempotency(svc).Handle(next)
+
+	send(h, "k1", `{}`)
+	send(h, "k1", `{}`)
+	if calls != 2 || len(svc.abandoned) != 2 {
+		t.Fatalf("expected failed requests to be retried, calls=%d abandoned=%v", calls, svc.abandoned)
+	}
+}
+
+func TestIdempotency_ScopedToCaller(t *testing.T) {
+	calls := 0
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusCreated)
+	})
+	h := NewIdempotency(&idempotencyServiceMock{}).Handle(next)
+
+	sendAs := func(keyID int64) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/team/add", bytes.NewBufferString(`{}`))
+		req.Header.Set(IdempotencyKeyHeader, "k1")
+		req = req.WithContext(core.WithPrincipal(req.Context(), core.Principal{KeyID: keyID}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	sendAs(1)
+	if w := sendAs(2); w.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Fatalf("another caller must not get a replay")
+	}
+	if w := sendAs(1); w.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Fatalf("expected the same caller to get a replay")
+	}
+	if calls != 2 {
+		t.Fatalf("expected handler to run once per caller, ran %d times", calls)
+	}
+}
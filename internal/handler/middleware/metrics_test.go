@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"avito-intern-test/internal/metrics"
+)
+
+func TestMetrics_LabelsByRoutePattern(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(Metrics)
+	r.Route("/team", func(r chi.Router) {
+		r.Get("/get", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNotFound) })
+	})
+
+	ok := metrics.HTTPRequests.WithLabelValues(http.MethodGet, "/team/get", "404")
+	unmatched := metrics.HTTPRequests.WithLabelValues(http.MethodGet, unmatchedRoute, "404")
+	before, beforeUnmatched := testutil.ToFloat64(ok), testutil.ToFloat64(unmatched)
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/team/get?team_name=a", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/team/get?team_name=b", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nope/123", nil))
+
+	if got := testutil.ToFloat64(ok) - before; got != 2 {
+		t.Fatalf("/team/get requests = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(unmatched) - beforeUnmatched; got != 1 {
+		t.Fatalf("unmatched requests = %v, want 1", got)
+	}
+}
@@ -7,7 +7,14 @@ import (
 )
 
 type pullReqeustService interface {
-	CreatePR(ctx context.Context, id, name, authorID string) (*prmodel.PullRequest, error)
+	CreatePR(ctx context.Context, id, name, authorID string, draft bool) (*prmodel.PullRequest, error)
 	MergePR(ctx context.Context, id string) (*prmodel.PullRequest, error)
+	MarkReady(ctx context.Context, id string) (*prmodel.PullRequest, error)
+	ClosePR(ctx context.Context, id string) (*prmodel.PullRequest, error)
+	ReopenPR(ctx context.Context, id string) (*prmodel.PullRequest, error)
 	ReassignReviewer(ctx context.Context, prID, oldUserID string) (*prmodel.PullRequest, string, error)
+	SubmitReview(ctx context.Context, prID, reviewerID string, verdict prmodel.ReviewVerdict) (*prmodel.PullRequest, error)
+	GetPR(ctx context.Context, prID string) (prmodel.PullRequestDetails, error)
+	GetHistory(ctx context.Context, prID string) ([]prmodel.ReviewerAssignment, error)
+	ListPullRequests(ctx context.Context, filter prmodel.ListFilter) (prmodel.ListPage, error)
 }
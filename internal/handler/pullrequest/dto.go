@@ -10,12 +10,23 @@ type CreatePRRequest struct {
 	PullRequestID   string `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name"`
 	AuthorID        string `json:"author_id"`
+	Draft           bool   `json:"draft,omitempty"`
 }
 
 type MergePRRequest struct {
 	PullRequestID string `json:"pull_request_id"`
 }
 
+type ReviewPRRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReviewerID    string `json:"reviewer_id"`
+	Verdict       string `json:"verdict"`
+}
+
+type ChangeStatusRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+}
+
 type ReassignPRRequest struct {
 	PullRequestID string `json:"pull_request_id"`
 	OldReviewerID string `json:"old_reviewer_id,omitempty"`
@@ -29,6 +40,10 @@ type PullRequestDTO struct {
 	AssignedReviewers []string   `json:"assigned_reviewers"`
 	CreatedAt         *time.Time `json:"createdAt,omitempty"`
 	MergedAt          *time.Time `json:"mergedAt,omitempty"`
+	FallbackTeams     []string   `json:"fallback_teams,omitempty"`
+
+	AuthorTeam string                  `json:"author_team,omitempty"`
+	Reviewers  []ReviewerAssignmentDTO `json:"reviewers,omitempty"`
 }
 
 type CreatePRResponse struct {
@@ -39,11 +54,65 @@ type MergePRResponse struct {
 	PR PullRequestDTO `json:"pr"`
 }
 
+type ChangeStatusResponse struct {
+	PR PullRequestDTO `json:"pr"`
+}
+
+type ReviewPRResponse struct {
+	PR         PullRequestDTO `json:"pr"`
+	ReviewerID string         `json:"reviewer_id"`
+	Verdict    string         `json:"verdict"`
+}
+
 type ReassignPRResponse struct {
 	PR         PullRequestDTO `json:"pr"`
 	ReplacedBy string         `json:"replaced_by"`
 }
 
+type GetPRResponse struct {
+	PR PullRequestDTO `json:"pr"`
+}
+
+type ListPRResponse struct {
+	PullRequests []PullRequestDTO `json:"pull_requests"`
+	NextCursor   string           `json:"next_cursor,omitempty"`
+}
+
+type ReviewerAssignmentDTO struct {
+	UserID     string     `json:"user_id"`
+	AssignedAt time.Time  `json:"assignedAt"`
+	ReplacedAt *time.Time `json:"replacedAt,omitempty"`
+	ReplacedBy *string    `json:"replaced_by,omitempty"`
+	Verdict    *string    `json:"verdict,omitempty"`
+	ReviewedAt *time.Time `json:"reviewedAt,omitempty"`
+}
+
+type HistoryResponse struct {
+	PullRequestID string                  `json:"pull_request_id"`
+	History       []ReviewerAssignmentDTO `json:"history"`
+}
+
+func assignmentToDTO(a prmodel.ReviewerAssignment) ReviewerAssignmentDTO {
+	dto := ReviewerAssignmentDTO{
+		UserID:     a.UserID,
+		AssignedAt: a.AssignedAt.UTC(),
+		ReplacedBy: a.ReplacedBy,
+	}
+	if a.ReplacedAt != nil {
+		t := a.ReplacedAt.UTC()
+		dto.ReplacedAt = &t
+	}
+	if a.Verdict != nil {
+		v := string(*a.Verdict)
+		dto.Verdict = &v
+	}
+	if a.ReviewedAt != nil {
+		t := a.ReviewedAt.UTC()
+		dto.ReviewedAt = &t
+	}
+	return dto
+}
+
 func prModelToDTO(m prmodel.PullRequest) PullRequestDTO {
 	dto := PullRequestDTO{
 		PullRequestID:     m.PullRequestID,
@@ -51,6 +120,7 @@ func prModelToDTO(m prmodel.PullRequest) PullRequestDTO {
 		AuthorID:          m.AuthorID,
 		Status:            string(m.Status),
 		AssignedReviewers: append([]string(nil), m.AssignedReviewers...),
+		FallbackTeams:     m.FallbackTeams,
 	}
 	if !m.CreatedAt.IsZero() {
 		t := m.CreatedAt.UTC()
@@ -62,3 +132,16 @@ func prModelToDTO(m prmodel.PullRequest) PullRequestDTO {
 	}
 	return dto
 }
+
+func prDetailsToDTO(d prmodel.PullRequestDetails) PullRequestDTO {
+	dto := prModelToDTO(d.PullRequest)
+	if dto.AssignedReviewers == nil {
+		dto.AssignedReviewers = []string{}
+	}
+	dto.AuthorTeam = d.AuthorTeam
+	dto.Reviewers = make([]ReviewerAssignmentDTO, 0, len(d.Reviewers))
+	for _, a := range d.Reviewers {
+		dto.Reviewers = append(dto.Reviewers, assignmentToDTO(a))
+	}
+	return dto
+}
@@ -1,10 +1,15 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"avito-intern-test/internal/handler/common"
+	prmodel "avito-intern-test/internal/model/pullrequest"
 )
 
 type PullRequestHandler struct {
@@ -23,13 +28,9 @@ func (h *PullRequestHandler) CreatePullRequest(w http.ResponseWriter, r *http.Re
 	} else if req.PullRequestID == "" || req.PullRequestName == "" || req.AuthorID == "" {
 		common.RespondWithError(w, http.StatusBadRequest, "missing required fields")
 	} else {
-		pr, err := h.service.CreatePR(ctx, req.PullRequestID, req.PullRequestName, req.AuthorID)
+		pr, err := h.service.CreatePR(ctx, req.PullRequestID, req.PullRequestName, req.AuthorID, req.Draft)
 		if err != nil {
-			if code, msg, ok := common.ParseCodeMessage(err); ok {
-				handleCreatePullRequestError(w, code, msg, err)
-			} else {
-				common.RespondWithError(w, http.StatusInternalServerError, err.Error())
-			}
+			common.RespondError(w, err)
 		} else {
 			common.RespondWithJSON(w, http.StatusCreated, CreatePRResponse{PR: prModelToDTO(*pr)})
 		}
@@ -46,17 +47,69 @@ func (h *PullRequestHandler) MergePullRequest(w http.ResponseWriter, r *http.Req
 	} else {
 		pr, err := h.service.MergePR(ctx, req.PullRequestID)
 		if err != nil {
-			if code, msg, ok := common.ParseCodeMessage(err); ok {
-				handleMergePullRequestError(w, code, msg, err)
-			} else {
-				common.RespondWithError(w, http.StatusInternalServerError, err.Error())
-			}
+			common.RespondError(w, err)
 		} else {
 			common.RespondWithJSON(w, http.StatusOK, MergePRResponse{PR: prModelToDTO(*pr)})
 		}
 	}
 }
 
+func (h *PullRequestHandler) ReviewPullRequest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req ReviewPRRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "invalid json body")
+	} else if req.PullRequestID == "" || req.ReviewerID == "" || req.Verdict == "" {
+		common.RespondWithError(w, http.StatusBadRequest, "missing required fields")
+	} else if verdict := prmodel.ReviewVerdict(req.Verdict); !verdict.Valid() {
+		common.RespondWithError(w, http.StatusBadRequest, "unknown verdict")
+	} else {
+		pr, err := h.service.SubmitReview(ctx, req.PullRequestID, req.ReviewerID, verdict)
+		if err != nil {
+			common.RespondError(w, err)
+		} else {
+			common.RespondWithJSON(w, http.StatusOK, ReviewPRResponse{
+				PR:         prModelToDTO(*pr),
+				ReviewerID: req.ReviewerID,
+				Verdict:    string(verdict),
+			})
+		}
+	}
+}
+
+func (h *PullRequestHandler) MarkReady(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.service.MarkReady)
+}
+
+func (h *PullRequestHandler) ClosePullRequest(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.service.ClosePR)
+}
+
+func (h *PullRequestHandler) ReopenPullRequest(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.service.ReopenPR)
+}
+
+func (h *PullRequestHandler) changeStatus(
+	w http.ResponseWriter,
+	r *http.Request,
+	change func(ctx context.Context, id string) (*prmodel.PullRequest, error),
+) {
+	ctx := r.Context()
+	var req ChangeStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "invalid json body")
+	} else if req.PullRequestID == "" {
+		common.RespondWithError(w, http.StatusBadRequest, "missing required fields")
+	} else {
+		pr, err := change(ctx, req.PullRequestID)
+		if err != nil {
+			common.RespondError(w, err)
+		} else {
+			common.RespondWithJSON(w, http.StatusOK, ChangeStatusResponse{PR: prModelToDTO(*pr)})
+		}
+	}
+}
+
 func (h *PullRequestHandler) ReassignPullRequest(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	var req ReassignPRRequest
@@ -67,11 +120,7 @@ func (h *PullRequestHandler) ReassignPullRequest(w http.ResponseWriter, r *http.
 	} else {
 		pr, replacedBy, err := h.service.ReassignReviewer(ctx, req.PullRequestID, req.OldReviewerID)
 		if err != nil {
-			if code, msg, ok := common.ParseCodeMessage(err); ok {
-				handleReassignPullRequestError(w, code, msg, err)
-			} else {
-				common.RespondWithError(w, http.StatusInternalServerError, err.Error())
-			}
+			common.RespondError(w, err)
 		} else {
 			common.RespondWithJSON(w, http.StatusOK, ReassignPRResponse{
 				PR:         prModelToDTO(*pr),
@@ -80,3 +129,136 @@ func (h *PullRequestHandler) ReassignPullRequest(w http.ResponseWriter, r *http.
 		}
 	}
 }
+
+func (h *PullRequestHandler) GetPullRequest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		common.RespondWithError(w, http.StatusBadRequest, "pull_request_id is required")
+	} else {
+		pr, err := h.service.GetPR(ctx, prID)
+		if err != nil {
+			common.RespondError(w, err)
+		} else {
+			common.RespondWithETag(w, r, GetPRResponse{PR: prDetailsToDTO(pr)})
+		}
+	}
+}
+
+func (h *PullRequestHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		common.RespondWithError(w, http.StatusBadRequest, "pull_request_id is required")
+	} else {
+		history, err := h.service.GetHistory(ctx, prID)
+		if err != nil {
+			common.RespondError(w, err)
+		} else {
+			items := make([]ReviewerAssignmentDTO, 0, len(history))
+			for _, a := range history {
+				items = append(items, assignmentToDTO(a))
+			}
+			common.RespondWithJSON(w, http.StatusOK, HistoryResponse{
+				PullRequestID: prID,
+				History:       items,
+			})
+		}
+	}
+}
+
+func (h *PullRequestHandler) ListPullRequests(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	filter, msg := parseListFilter(r)
+	if msg != "" {
+		common.RespondWithError(w, http.StatusBadRequest, msg)
+	} else {
+		page, err := h.service.ListPullRequests(ctx, filter)
+		if err != nil {
+			common.RespondError(w, err)
+		} else {
+			items := make([]PullRequestDTO, 0, len(page.PullRequests))
+			for _, p := range page.PullRequests {
+				dto := prModelToDTO(p)
+				if dto.AssignedReviewers == nil {
+					dto.AssignedReviewers = []string{}
+				}
+				items = append(items, dto)
+			}
+			resp := ListPRResponse{PullRequests: items}
+			if page.Next != nil {
+				resp.NextCursor = page.Next.Encode()
+			}
+			common.RespondWithJSON(w, http.StatusOK, resp)
+		}
+	}
+}
+
+func parseListFilter(r *http.Request) (prmodel.ListFilter, string) {
+	q := r.URL.Query()
+	filter := prmodel.ListFilter{
+		AuthorID:     q.Get("author_id"),
+		TeamName:     q.Get("team_name"),
+		Status:       prmodel.PullRequestStatus(q.Get("status")),
+		ReviewerID:   q.Get("reviewer_id"),
+		NameContains: q.Get("name"),
+		Sort:         prmodel.ListSort(q.Get("sort")),
+		Limit:        prmodel.DefaultReviewPageLimit,
+	}
+	if filter.Status != "" && !filter.Status.Valid() {
+		return filter, "status must be one of DRAFT, OPEN, MERGED, CLOSED"
+	}
+	if filter.Sort == "" {
+		filter.Sort = prmodel.ListSortCreatedDesc
+	} else if !filter.Sort.Valid() {
+		return filter, "sort must be one of created_at_desc, created_at_asc, name_asc, name_desc"
+	}
+
+	if raw := q.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > prmodel.MaxReviewPageLimit {
+			return filter, fmt.Sprintf("limit must be between 1 and %d", prmodel.MaxReviewPageLimit)
+		}
+		filter.Limit = limit
+	}
+
+	bounds := []struct {
+		key string
+		dst **time.Time
+	}{
+		{"created_after", &filter.CreatedAfter},
+		{"created_before", &filter.CreatedBefore},
+	}
+	for _, b := range bounds {
+		raw := q.Get(b.key)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return filter, b.key + " must be an RFC3339 timestamp"
+		}
+		t = t.UTC()
+		*b.dst = &t
+	}
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && !filter.CreatedAfter.Before(*filter.CreatedBefore) {
+		return filter, "created_after must be before created_before"
+	}
+
+	if raw := q.Get("cursor"); raw != "" {
+		cursor, err := prmodel.DecodeListCursor(raw)
+		if err != nil {
+			return filter, "cursor is invalid"
+		}
+		if cursor.Sort != filter.Sort {
+			return filter, "cursor was issued for a different sort"
+		}
+		if filter.Sort == prmodel.ListSortCreatedAsc || filter.Sort == prmodel.ListSortCreatedDesc {
+			if _, err := cursor.CreatedAt(); err != nil {
+				return filter, "cursor is invalid"
+			}
+		}
+		filter.After = &cursor
+	}
+	return filter, ""
+}
@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"avito-intern-test/internal/core"
 	prmodel "avito-intern-test/internal/model/pullrequest"
 )
 
@@ -19,9 +22,19 @@ type prServiceMock struct {
 	reResp     *prmodel.PullRequest
 	reUser     string
 	reErr      error
+	history    []prmodel.ReviewerAssignment
+	historyErr error
+	statusResp *prmodel.PullRequest
+	statusErr  error
+
+	createDraft bool
+	listPage    prmodel.ListPage
+	listFilter  prmodel.ListFilter
+	details     prmodel.PullRequestDetails
 }
 
-func (m *prServiceMock) CreatePR(_ context.Context, id, name, authorID string) (*prmodel.PullRequest, error) {
+func (m *prServiceMock) CreatePR(_ context.Context, id, name, authorID string, draft bool) (*prmodel.PullRequest, error) {
+	m.createDraft = draft
 	return m.createResp, m.createErr
 }
 func (m *prServiceMock) MergePR(_ context.Context, id string) (*prmodel.PullRequest, error) {
@@ -31,6 +44,34 @@ func (m *prServiceMock) ReassignReviewer(_ context.Context, prID, oldUserID stri
 	return m.reResp, m.reUser, m.reErr
 }
 
+func (m *prServiceMock) MarkReady(_ context.Context, id string) (*prmodel.PullRequest, error) {
+	return m.statusResp, m.statusErr
+}
+func (m *prServiceMock) ClosePR(_ context.Context, id string) (*prmodel.PullRequest, error) {
+	return m.statusResp, m.statusErr
+}
+func (m *prServiceMock) ReopenPR(_ context.Context, id string) (*prmodel.PullRequest, error) {
+	return m.statusResp, m.statusErr
+}
+func (m *prServiceMock) SubmitReview(_ context.Context, prID, reviewerID string, verdict prmodel.ReviewVerdict) (*prmodel.PullRequest, error) {
+	return m.statusResp, m.statusErr
+}
+func (m *prServiceMock) GetHistory(_ context.Context, prID string) ([]prmodel.ReviewerAssignment, error) {
+	return m.history, m.historyErr
+}
+
+func (m *prServiceMock) ListPullRequests(_ context.Context, filter prmodel.ListFilter) (prmodel.ListPage, error) {
+	m.listFilter = filter
+	return m.listPage, nil
+}
+
+func (m *prServiceMock) GetPR(_ context.Context, prID string) (prmodel.PullRequestDetails, error) {
+	if m.details.PullRequestID != prID {
+		return prmodel.PullRequestDetails{}, core.Throw(core.ErrorNotFound, "pr not found")
+	}
+	return m.details, nil
+}
+
 func TestPRHandler_Create_BadJSON(t *testing.T) {
 	h := NewPullRequestHandler(&prServiceMock{})
 	req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", bytes.NewBufferString("{"))
@@ -80,3 +121,273 @@ func TestPRHandler_Reassign_AcceptsOldReviewerID(t *testing.T) {
 		t.Fatalf("expected 200, got %d; body=%s", w.Code, w.Body.String())
 	}
 }
+
+func TestPRHandler_History(t *testing.T) {
+	replacedAt := time.Now().UTC()
+	replacedBy := "u3"
+	h := NewPullRequestHandler(&prServiceMock{
+		history: []prmodel.ReviewerAssignment{
+			{UserID: "u2", AssignedAt: replacedAt.Add(-time.Hour), ReplacedAt: &replacedAt, ReplacedBy: &replacedBy},
+			{UserID: "u3", AssignedAt: replacedAt},
+		},
+	})
+	req := httptest.NewRequest(http.MethodGet, "/pullRequest/history?pull_request_id=pr-1", nil)
+	w := httptest.NewRecorder()
+	h.GetHistory(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body=%s", w.Code, w.Body.String())
+	}
+	var resp HistoryResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.History) != 2 || resp.History[0].ReplacedBy == nil || *resp.History[0].ReplacedBy != "u3" {
+		t.Fatalf("unexpected history: %+v", resp.History)
+	}
+}
+
+func TestPRHandler_History_NotFound(t *testing.T) {
+	h := NewPullRequestHandler(&prServiceMock{historyErr: core.Throw(core.ErrorNotFound, "pr not found")})
+	req := httptest.NewRequest(http.MethodGet, "/pullRequest/history?pull_request_id=pr-1", nil)
+	w := httptest.NewRecorder()
+	h.GetHistory(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestPRHandler_Reassign_WrappedNotFound(t *testing.T) {
+	h := NewPullRequestHandler(&prServiceMock{
+		reErr: fmt.Errorf("get PR: %w", core.Throw(core.ErrorNotFound, "pr not found")),
+	})
+	b := []byte(`{"pull_request_id":"pr-404","old_reviewer_id":"u2"}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/reassign", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.ReassignPullRequest(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d; body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Error.Code != core.ErrorNotFound {
+		t.Fatalf("expected NOT_FOUND code, got %q", resp.Error.Code)
+	}
+}
+
+func TestPRHandler_Reassign_NoCandidateDetails(t *testing.T) {
+	h := NewPullRequestHandler(&prServiceMock{
+		reErr: core.NewDomainError(core.ErrorNoCandidate, "no active replacement candidate in team").
+			WithDetails(map[string]any{"team_name": "backend"}),
+	})
+	b := []byte(`{"pull_request_id":"pr-1","old_reviewer_id":"u2"}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/reassign", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.ReassignPullRequest(w, req)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+	var resp struct {
+		Error struct {
+			Details map[string]any `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Error.Details["team_name"] != "backend" {
+		t.Fatalf("expected details to be returned, got %+v", resp.Error.Details)
+	}
+}
+
+func TestPRHandler_Merge_InternalError(t *testing.T) {
+	h := NewPullRequestHandler(&prServiceMock{mergeErr: fmt.Errorf("update PR: connection reset")})
+	b := []byte(`{"pull_request_id":"pr-1"}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.MergePullRequest(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", w.Code)
+	}
+}
+
+func TestPRHandler_Create_Draft(t *testing.T) {
+	svc := &prServiceMock{
+		createResp: &prmodel.PullRequest{PullRequestID: "pr-1", Status: prmodel.PullRequestStatusDraft},
+	}
+	h := NewPullRequestHandler(svc)
+	b := []byte(`{"pull_request_id":"pr-1","pull_request_name":"Add","author_id":"u1","draft":true}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.CreatePullRequest(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d; body=%s", w.Code, w.Body.String())
+	}
+	if !svc.createDraft {
+		t.Fatalf("expected draft flag to be passed")
+	}
+}
+
+func TestPRHandler_Close_InvalidTransition(t *testing.T) {
+	h := NewPullRequestHandler(&prServiceMock{
+		statusErr: core.Throw(core.ErrorInvalidTransition, "cannot move PR from MERGED to CLOSED"),
+	})
+	b := []byte(`{"pull_request_id":"pr-1"}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/close", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.ClosePullRequest(w, req)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+}
+
+func TestPRHandler_Ready_MissingID(t *testing.T) {
+	h := NewPullRequestHandler(&prServiceMock{})
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/ready", bytes.NewBufferString(`{}`))
+	w := httptest.NewRecorder()
+	h.MarkReady(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestPRHandler_Review_UnknownVerdict(t *testing.T) {
+	h := NewPullRequestHandler(&prServiceMock{})
+	b := []byte(`{"pull_request_id":"pr-1","reviewer_id":"u2","verdict":"LGTM"}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/review", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.ReviewPullRequest(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestPRHandler_Review_OK(t *testing.T) {
+	h := NewPullRequestHandler(&prServiceMock{
+		statusResp: &prmodel.PullRequest{PullRequestID: "pr-1", Status: prmodel.PullRequestStatusOpen},
+	})
+	b := []byte(`{"pull_request_id":"pr-1","reviewer_id":"u2","verdict":"APPROVED"}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/review", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.ReviewPullRequest(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestPRHandler_Merge_NotApproved(t *testing.T) {
+	h := NewPullRequestHandler(&prServiceMock{mergeErr: core.Throw(core.ErrorNotApproved, "PR does not have enough approvals")})
+	b := []byte(`{"pull_request_id":"pr-1"}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.MergePullRequest(w, req)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+}
+
+func TestPRHandler_ListPullRequests(t *testing.T) {
+	next := prmodel.ListCursor{Sort: prmodel.ListSortNameAsc, Key: "b", PullRequestID: "pr-2"}
+	m := &prServiceMock{listPage: prmodel.ListPage{
+		PullRequests: []prmodel.PullRequest{{PullRequestID: "pr-2", PullRequestName: "b", Status: prmodel.PullRequestStatusOpen}},
+		Next:         &next,
+	}}
+	h := NewPullRequestHandler(m)
+
+	url := "/pullRequest/list?author_id=u1&team_name=backend&status=OPEN&reviewer_id=u2&name=fix&sort=name_asc&limit=1&cursor=" + next.Encode()
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	w := httptest.NewRecorder()
+	h.ListPullRequests(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body=%s", w.Code, w.Body.String())
+	}
+	f := m.listFilter
+	if f.AuthorID != "u1" || f.TeamName != "backend" || f.ReviewerID != "u2" || f.NameContains != "fix" ||
+		f.Sort != prmodel.ListSortNameAsc || f.Limit != 1 || f.After == nil || f.After.PullRequestID != "pr-2" {
+		t.Fatalf("unexpected filter: %+v", f)
+	}
+	var resp ListPRResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.PullRequests) != 1 || resp.NextCursor != next.Encode() || resp.PullRequests[0].AssignedReviewers == nil {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestPRHandler_ListPullRequests_BadParams(t *testing.T) {
+	h := NewPullRequestHandler(&prServiceMock{})
+	nameCursor := prmodel.ListCursor{Sort: prmodel.ListSortNameAsc, Key: "b", PullRequestID: "pr-2"}.Encode()
+	for _, q := range []string{"status=WIP", "sort=random", "limit=0", "cursor=" + nameCursor, "created_before=soon"} {
+		req := httptest.NewRequest(http.MethodGet, "/pullRequest/list?"+q, nil)
+		w := httptest.NewRecorder()
+		h.ListPullRequests(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", q, w.Code)
+		}
+	}
+}
+
+func TestPRHandler_GetPullRequest_ETag(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	m := &prServiceMock{details: prmodel.PullRequestDetails{
+		PullRequest: prmodel.PullRequest{
+			PullRequestID:     "pr-1",
+			AuthorID:          "a1",
+			Status:            prmodel.PullRequestStatusOpen,
+			AssignedReviewers: []string{"r1"},
+			CreatedAt:         now,
+		},
+		AuthorTeam: "backend",
+		Reviewers:  []prmodel.ReviewerAssignment{{UserID: "r1", AssignedAt: now}},
+	}}
+	h := NewPullRequestHandler(m)
+
+	req := httptest.NewRequest(http.MethodGet, "/pullRequest/get?pull_request_id=pr-1", nil)
+	w := httptest.NewRecorder()
+	h.GetPullRequest(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body=%s", w.Code, w.Body.String())
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatalf("expected ETag header")
+	}
+	var resp GetPRResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.PR.AuthorTeam != "backend" || len(resp.PR.Reviewers) != 1 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/pullRequest/get?pull_request_id=pr-1", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	h.GetPullRequest(w, req)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Fatalf("expected empty 304, got %d %q", w.Code, w.Body.String())
+	}
+
+	m.details.Status = prmodel.PullRequestStatusMerged
+	w = httptest.NewRecorder()
+	h.GetPullRequest(w, req)
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Fatalf("expected a fresh body after a change, got %d", w.Code)
+	}
+}
+
+func TestPRHandler_GetPullRequest_NotFound(t *testing.T) {
+	h := NewPullRequestHandler(&prServiceMock{})
+	req := httptest.NewRequest(http.MethodGet, "/pullRequest/get?pull_request_id=nope", nil)
+	w := httptest.NewRecorder()
+	h.GetPullRequest(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
@@ -0,0 +1,11 @@
+package handler
+
+import (
+	"context"
+
+	statsmodel "avito-intern-test/internal/model/stats"
+)
+
+type statsService interface {
+	GetStats(ctx context.Context, filter statsmodel.Filter) (statsmodel.Stats, error)
+}
@@ -0,0 +1,59 @@
+package handler
+
+import statsmodel "avito-intern-test/internal/model/stats"
+
+type UserStatsDTO struct {
+	UserID            string `json:"user_id"`
+	Username          string `json:"username"`
+	TeamName          string `json:"team_name"`
+	IsActive          bool   `json:"is_active"`
+	TotalAssignments  int    `json:"total_assignments"`
+	OpenAssignments   int    `json:"open_assignments"`
+	MergedAssignments int    `json:"merged_assignments"`
+}
+
+type PullRequestStatsDTO struct {
+	PullRequestID    string `json:"pull_request_id"`
+	PullRequestName  string `json:"pull_request_name"`
+	AuthorID         string `json:"author_id"`
+	TeamName         string `json:"team_name"`
+	Status           string `json:"status"`
+	Reviewers        int    `json:"reviewers"`
+	TotalAssignments int    `json:"total_assignments"`
+}
+
+type TeamStatsDTO struct {
+	TeamName           string `json:"team_name"`
+	Members            int    `json:"members"`
+	ActiveMembers      int    `json:"active_members"`
+	TotalAssignments   int    `json:"total_assignments"`
+	OpenAssignments    int    `json:"open_assignments"`
+	MergedAssignments  int    `json:"merged_assignments"`
+	PullRequests       int    `json:"pull_requests"`
+	OpenPullRequests   int    `json:"open_pull_requests"`
+	MergedPullRequests int    `json:"merged_pull_requests"`
+}
+
+type StatsResponse struct {
+	Users        []UserStatsDTO        `json:"users"`
+	PullRequests []PullRequestStatsDTO `json:"pull_requests"`
+	Teams        []TeamStatsDTO        `json:"teams"`
+}
+
+func statsToDTO(s statsmodel.Stats) StatsResponse {
+	resp := StatsResponse{
+		Users:        make([]UserStatsDTO, 0, len(s.Users)),
+		PullRequests: make([]PullRequestStatsDTO, 0, len(s.PullRequests)),
+		Teams:        make([]TeamStatsDTO, 0, len(s.Teams)),
+	}
+	for _, u := range s.Users {
+		resp.Users = append(resp.Users, UserStatsDTO(u))
+	}
+	for _, pr := range s.PullRequests {
+		resp.PullRequests = append(resp.PullRequests, PullRequestStatsDTO(pr))
+	}
+	for _, t := range s.Teams {
+		resp.Teams = append(resp.Teams, TeamStatsDTO(t))
+	}
+	return resp
+}
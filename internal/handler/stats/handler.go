@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"avito-intern-test/internal/handler/common"
+	statsmodel "avito-intern-test/internal/model/stats"
+)
+
+type StatsHandler struct {
+	service statsService
+}
+
+func NewStatsHandler(service statsService) *StatsHandler {
+	return &StatsHandler{service: service}
+}
+
+func (h *StatsHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	filter, msg := parseFilter(r)
+	if msg != "" {
+		common.RespondWithError(w, http.StatusBadRequest, msg)
+	} else {
+		stats, err := h.service.GetStats(ctx, filter)
+		if err != nil {
+			common.RespondError(w, err)
+		} else {
+			common.RespondWithJSON(w, http.StatusOK, statsToDTO(stats))
+		}
+	}
+}
+
+func parseFilter(r *http.Request) (statsmodel.Filter, string) {
+	q := r.URL.Query()
+	filter := statsmodel.Filter{TeamName: q.Get("team_name")}
+
+	bounds := []struct {
+		key string
+		dst **time.Time
+	}{
+		{"created_after", &filter.CreatedAfter},
+		{"created_before", &filter.CreatedBefore},
+		{"merged_after", &filter.MergedAfter},
+		{"merged_before", &filter.MergedBefore},
+	}
+	for _, b := range bounds {
+		raw := q.Get(b.key)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return filter, b.key + " must be an RFC3339 timestamp"
+		}
+		t = t.UTC()
+		*b.dst = &t
+	}
+
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && !filter.CreatedAfter.Before(*filter.CreatedBefore) {
+		return filter, "created_after must be before created_before"
+	}
+	if filter.MergedAfter != nil && filter.MergedBefore != nil && !filter.MergedAfter.Before(*filter.MergedBefore) {
+		return filter, "merged_after must be before merged_before"
+	}
+	return filter, ""
+}
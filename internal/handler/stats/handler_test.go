@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	statsmodel "avito-intern-test/internal/model/stats"
+)
+
+type statsServiceMock struct {
+	stats  statsmodel.Stats
+	filter statsmodel.Filter
+}
+
+func (m *statsServiceMock) GetStats(_ context.Context, filter statsmodel.Filter) (statsmodel.Stats, error) {
+	m.filter = filter
+	return m.stats, nil
+}
+
+func TestStatsHandler_GetStats_ParsesFilter(t *testing.T) {
+	m := &statsServiceMock{stats: statsmodel.Stats{
+		Users: []statsmodel.UserStats{{UserID: "u1", TeamName: "backend", OpenAssignments: 2}},
+	}}
+	h := NewStatsHandler(m)
+	req := httptest.NewRequest(http.MethodGet,
+		"/stats?team_name=backend&created_after=2025-01-01T00:00:00Z&merged_before=2025-02-01T00:00:00%2B03:00", nil)
+	w := httptest.NewRecorder()
+	h.GetStats(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body=%s", w.Code, w.Body.String())
+	}
+	if m.filter.TeamName != "backend" || m.filter.CreatedAfter == nil || m.filter.MergedBefore == nil {
+		t.Fatalf("unexpected filter: %+v", m.filter)
+	}
+	if m.filter.MergedBefore.Hour() != 21 {
+		t.Fatalf("expected UTC normalised bound, got %v", m.filter.MergedBefore)
+	}
+	var resp StatsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Users) != 1 || resp.Users[0].OpenAssignments != 2 || resp.Teams == nil {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestStatsHandler_GetStats_BadRange(t *testing.T) {
+	h := NewStatsHandler(&statsServiceMock{})
+	req := httptest.NewRequest(http.MethodGet,
+		"/stats?created_after=2025-02-01T00:00:00Z&created_before=2025-01-01T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+	h.GetStats(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestStatsHandler_GetStats_BadTimestamp(t *testing.T) {
+	h := NewStatsHandler(&statsServiceMock{})
+	req := httptest.NewRequest(http.MethodGet, "/stats?merged_after=yesterday", nil)
+	w := httptest.NewRecorder()
+	h.GetStats(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
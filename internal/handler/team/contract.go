@@ -3,11 +3,22 @@ package handler
 import (
 	"context"
 
+	prmodel "avito-intern-test/internal/model/pullrequest"
 	teammodel "avito-intern-test/internal/model/team"
 	usermodel "avito-intern-test/internal/model/user"
 )
 
 type teamService interface {
 	GetTeamMembers(ctx context.Context, name string) ([]usermodel.User, error)
-	CreateWithMembers(ctx context.Context, name string, members []usermodel.User) (*teammodel.Team, error)
+	CreateWithMembers(
+		ctx context.Context,
+		name string,
+		members []usermodel.User,
+		update teammodel.SettingsUpdate,
+	) (*teammodel.Team, error)
+	GetSettings(ctx context.Context, name string) (teammodel.Settings, error)
+	UpdateSettings(ctx context.Context, name string, update teammodel.SettingsUpdate) (teammodel.Settings, error)
+	DeactivateUsers(ctx context.Context, name string, userIDs []string) ([]usermodel.User, prmodel.RebalanceReport, error)
+	RenameTeam(ctx context.Context, name, newName string) error
+	DeleteTeam(ctx context.Context, name, targetTeam string) ([]usermodel.User, prmodel.RebalanceReport, error)
 }
@@ -1,12 +1,22 @@
 package handler
 
 import (
+	prmodel "avito-intern-test/internal/model/pullrequest"
+	teammodel "avito-intern-test/internal/model/team"
 	usermodel "avito-intern-test/internal/model/user"
 )
 
+type SettingsFields struct {
+	ReviewerStrategy  *string   `json:"reviewer_strategy,omitempty"`
+	RequiredReviewers *int      `json:"required_reviewers,omitempty"`
+	RequiredApprovals *int      `json:"required_approvals,omitempty"`
+	FallbackTeams     *[]string `json:"fallback_teams,omitempty"`
+}
+
 type CreateWithMembersRequest struct {
 	TeamName string           `json:"team_name"`
 	Members  []usermodel.User `json:"members"`
+	SettingsFields
 }
 
 type TeamMemberDTO struct {
@@ -21,5 +31,180 @@ type TeamDTO struct {
 }
 
 type CreateTeamResponse struct {
-	Team TeamDTO `json:"team"`
+	Team     TeamDTO         `json:"team"`
+	Settings TeamSettingsDTO `json:"settings"`
+}
+
+type UpdateTeamSettingsRequest struct {
+	TeamName string `json:"team_name"`
+	SettingsFields
+}
+
+type TeamSettingsDTO struct {
+	TeamName          string   `json:"team_name"`
+	ReviewerStrategy  string   `json:"reviewer_strategy"`
+	RequiredReviewers int      `json:"required_reviewers"`
+	RequiredApprovals int      `json:"required_approvals"`
+	FallbackTeams     []string `json:"fallback_teams"`
+}
+
+func settingsToDTO(teamName string, s teammodel.Settings) TeamSettingsDTO {
+	return TeamSettingsDTO{
+		TeamName:          teamName,
+		ReviewerStrategy:  string(s.ReviewerStrategy),
+		RequiredReviewers: s.RequiredReviewers,
+		RequiredApprovals: s.RequiredApprovals,
+		FallbackTeams:     append([]string{}, s.FallbackTeams...),
+	}
+}
+
+func parseSettingsUpdate(teamName string, f SettingsFields) (teammodel.SettingsUpdate, string) {
+	var update teammodel.SettingsUpdate
+	if f.ReviewerStrategy != nil {
+		s := teammodel.ReviewerStrategy(*f.ReviewerStrategy)
+		if !s.Valid() {
+			return update, "unknown reviewer_strategy"
+		}
+		update.ReviewerStrategy = &s
+	}
+	if f.RequiredReviewers != nil {
+		if *f.RequiredReviewers <= 0 {
+			return update, "required_reviewers must be positive"
+		}
+		update.RequiredReviewers = f.RequiredReviewers
+	}
+	if f.RequiredApprovals != nil {
+		if *f.RequiredApprovals < 0 {
+			return update, "required_approvals must not be negative"
+		}
+		update.RequiredApprovals = f.RequiredApprovals
+	}
+	if f.FallbackTeams != nil {
+		seen := make(map[string]struct{}, len(*f.FallbackTeams))
+		for _, team := range *f.FallbackTeams {
+			if team == "" || team == teamName {
+				return update, "fallback_teams must name other teams"
+			}
+			if _, ok := seen[team]; ok {
+				return update, "fallback_teams must not repeat"
+			}
+			seen[team] = struct{}{}
+		}
+		update.FallbackTeams = f.FallbackTeams
+	}
+	return update, ""
+}
+
+type DeactivateUsersRequest struct {
+	TeamName string   `json:"team_name"`
+	UserIDs  []string `json:"user_ids"`
+}
+
+type DeactivatedUserDTO struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+}
+
+type ReviewerMoveDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldUserID     string `json:"old_user_id"`
+	NewUserID     string `json:"new_user_id"`
+}
+
+type UnassignedReviewDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+}
+
+type DeactivateUsersResponse struct {
+	TeamName   string                `json:"team_name"`
+	Users      []DeactivatedUserDTO  `json:"users"`
+	Moved      []ReviewerMoveDTO     `json:"moved"`
+	Unassigned []UnassignedReviewDTO `json:"unassigned"`
+}
+
+type RenameTeamRequest struct {
+	TeamName string `json:"team_name"`
+	NewName  string `json:"new_name"`
+}
+
+type RenameTeamResponse struct {
+	TeamName     string `json:"team_name"`
+	PreviousName string `json:"previous_name"`
+}
+
+type DeleteTeamRequest struct {
+	TeamName          string `json:"team_name"`
+	TargetTeam        string `json:"target_team,omitempty"`
+	DeactivateMembers bool   `json:"deactivate_members,omitempty"`
+}
+
+type DeleteTeamResponse struct {
+	TeamName   string                `json:"team_name"`
+	TargetTeam string                `json:"target_team,omitempty"`
+	Members    []TeamMemberDTO       `json:"members"`
+	Moved      []ReviewerMoveDTO     `json:"moved"`
+	Unassigned []UnassignedReviewDTO `json:"unassigned"`
+}
+
+func deactivationToDTO(
+	teamName string,
+	users []usermodel.User,
+	report prmodel.RebalanceReport,
+) DeactivateUsersResponse {
+	resp := DeactivateUsersResponse{
+		TeamName: teamName,
+		Users:    make([]DeactivatedUserDTO, 0, len(users)),
+	}
+	for _, u := range users {
+		resp.Users = append(resp.Users, DeactivatedUserDTO{
+			UserID:   u.UserID,
+			Username: u.Username,
+			IsActive: u.IsActive,
+		})
+	}
+	resp.Moved, resp.Unassigned = reportToDTO(report)
+	return resp
+}
+
+func deletionToDTO(
+	teamName string,
+	targetTeam string,
+	users []usermodel.User,
+	report prmodel.RebalanceReport,
+) DeleteTeamResponse {
+	resp := DeleteTeamResponse{
+		TeamName:   teamName,
+		TargetTeam: targetTeam,
+		Members:    make([]TeamMemberDTO, 0, len(users)),
+	}
+	for _, u := range users {
+		resp.Members = append(resp.Members, TeamMemberDTO{
+			UserID:   u.UserID,
+			Username: u.Username,
+			IsActive: u.IsActive,
+		})
+	}
+	resp.Moved, resp.Unassigned = reportToDTO(report)
+	return resp
+}
+
+func reportToDTO(report prmodel.RebalanceReport) ([]ReviewerMoveDTO, []UnassignedReviewDTO) {
+	moved := make([]ReviewerMoveDTO, 0, len(report.Moved))
+	for _, m := range report.Moved {
+		moved = append(moved, ReviewerMoveDTO{
+			PullRequestID: m.PullRequestID,
+			OldUserID:     m.FromUserID,
+			NewUserID:     m.ToUserID,
+		})
+	}
+	unassigned := make([]UnassignedReviewDTO, 0, len(report.Unassigned))
+	for _, u := range report.Unassigned {
+		unassigned = append(unassigned, UnassignedReviewDTO{
+			PullRequestID: u.PullRequestID,
+			UserID:        u.UserID,
+		})
+	}
+	return moved, unassigned
 }
@@ -2,12 +2,9 @@ package handler
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 
-	"avito-intern-test/internal/core"
 	"avito-intern-test/internal/handler/common"
-	teamerr "avito-intern-test/internal/service/team"
 )
 
 type TeamHandler struct {
@@ -23,16 +20,12 @@ func (h *TeamHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 	var req CreateWithMembersRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		common.RespondWithError(w, http.StatusBadRequest, "invalid json body")
+	} else if update, msg := parseSettingsUpdate(req.TeamName, req.SettingsFields); msg != "" {
+		common.RespondWithError(w, http.StatusBadRequest, msg)
 	} else {
-		team, err := h.service.CreateWithMembers(ctx, req.TeamName, req.Members)
+		team, err := h.service.CreateWithMembers(ctx, req.TeamName, req.Members, update)
 		if err != nil {
-			if errors.Is(err, teamerr.ErrTeamAlreadyExists) {
-				common.RespondAPIError(w, http.StatusBadRequest, "TEAM_EXISTS", "team_name already exists")
-			} else if code, msg, ok := common.ParseCodeMessage(err); ok && code == core.ErrorUserExists {
-				common.RespondAPIError(w, http.StatusConflict, code, msg)
-			} else {
-				common.RespondWithError(w, http.StatusInternalServerError, err.Error())
-			}
+			common.RespondError(w, err)
 		} else {
 			members := make([]TeamMemberDTO, 0, len(req.Members))
 			for _, m := range req.Members {
@@ -47,6 +40,7 @@ func (h *TeamHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 					TeamName: team.Name,
 					Members:  members,
 				},
+				Settings: settingsToDTO(team.Name, team.Settings),
 			}
 			common.RespondWithJSON(w, http.StatusCreated, resp)
 		}
@@ -60,10 +54,8 @@ func (h *TeamHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
 		common.RespondWithError(w, http.StatusBadRequest, "team_name is required")
 	} else {
 		members, err := h.service.GetTeamMembers(ctx, teamName)
-		if errors.Is(err, teamerr.ErrTeamNotFound) {
-			common.RespondAPIError(w, http.StatusNotFound, "NOT_FOUND", "resource not found")
-		} else if err != nil {
-			common.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		if err != nil {
+			common.RespondError(w, err)
 		} else {
 			items := make([]TeamMemberDTO, 0, len(members))
 			for _, m := range members {
@@ -81,3 +73,102 @@ func (h *TeamHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 }
+
+func (h *TeamHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		common.RespondWithError(w, http.StatusBadRequest, "team_name is required")
+	} else {
+		settings, err := h.service.GetSettings(ctx, teamName)
+		if err != nil {
+			common.RespondError(w, err)
+		} else {
+			common.RespondWithJSON(w, http.StatusOK, settingsToDTO(teamName, settings))
+		}
+	}
+}
+
+func (h *TeamHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req UpdateTeamSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "invalid json body")
+		return
+	}
+	if req.TeamName == "" {
+		common.RespondWithError(w, http.StatusBadRequest, "team_name is required")
+		return
+	}
+	update, msg := parseSettingsUpdate(req.TeamName, req.SettingsFields)
+	if msg != "" {
+		common.RespondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	settings, err := h.service.UpdateSettings(ctx, req.TeamName, update)
+	if err != nil {
+		common.RespondError(w, err)
+	} else {
+		common.RespondWithJSON(w, http.StatusOK, settingsToDTO(req.TeamName, settings))
+	}
+}
+
+func (h *TeamHandler) DeactivateUsers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req DeactivateUsersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "invalid json body")
+	} else if req.TeamName == "" || len(req.UserIDs) == 0 {
+		common.RespondWithError(w, http.StatusBadRequest, "team_name and user_ids are required")
+	} else {
+		users, report, err := h.service.DeactivateUsers(ctx, req.TeamName, req.UserIDs)
+		if err != nil {
+			common.RespondError(w, err)
+		} else {
+			common.RespondWithJSON(w, http.StatusOK, deactivationToDTO(req.TeamName, users, report))
+		}
+	}
+}
+
+func (h *TeamHandler) RenameTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req RenameTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "invalid json body")
+	} else if req.TeamName == "" || req.NewName == "" {
+		common.RespondWithError(w, http.StatusBadRequest, "team_name and new_name are required")
+	} else if req.TeamName == req.NewName {
+		common.RespondWithError(w, http.StatusBadRequest, "new_name must differ from team_name")
+	} else {
+		if err := h.service.RenameTeam(ctx, req.TeamName, req.NewName); err != nil {
+			common.RespondError(w, err)
+		} else {
+			common.RespondWithJSON(w, http.StatusOK, RenameTeamResponse{
+				TeamName:     req.NewName,
+				PreviousName: req.TeamName,
+			})
+		}
+	}
+}
+
+func (h *TeamHandler) DeleteTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req DeleteTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "invalid json body")
+	} else if req.TeamName == "" {
+		common.RespondWithError(w, http.StatusBadRequest, "team_name is required")
+	} else if (req.TargetTeam == "") == !req.DeactivateMembers {
+		common.RespondWithError(w, http.StatusBadRequest, "exactly one of target_team or deactivate_members is required")
+	} else if req.TargetTeam == req.TeamName {
+		common.RespondWithError(w, http.StatusBadRequest, "target_team must differ from team_name")
+	} else {
+		users, report, err := h.service.DeleteTeam(ctx, req.TeamName, req.TargetTeam)
+		if err != nil {
+			common.RespondError(w, err)
+		} else {
+			common.RespondWithJSON(w, http.StatusOK, deletionToDTO(req.TeamName, req.TargetTeam, users, report))
+		}
+	}
+}
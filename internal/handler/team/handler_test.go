@@ -8,24 +8,69 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"avito-intern-test/internal/core"
+	prmodel "avito-intern-test/internal/model/pullrequest"
 	teammodel "avito-intern-test/internal/model/team"
 	usermodel "avito-intern-test/internal/model/user"
 )
 
 type teamServiceMock struct {
-	createResp *teammodel.Team
-	createErr  error
-	members    []usermodel.User
-	getErr     error
+	createResp  *teammodel.Team
+	createErr   error
+	members     []usermodel.User
+	getErr      error
+	settings    teammodel.Settings
+	settingsErr error
+	update      teammodel.SettingsUpdate
+	report      prmodel.RebalanceReport
+	deactErr    error
+	renameErr   error
 }
 
 func (m *teamServiceMock) GetTeamMembers(_ context.Context, name string) ([]usermodel.User, error) {
 	return m.members, m.getErr
 }
-func (m *teamServiceMock) CreateWithMembers(_ context.Context, name string, members []usermodel.User) (*teammodel.Team, error) {
+func (m *teamServiceMock) CreateWithMembers(
+	_ context.Context,
+	name string,
+	members []usermodel.User,
+	update teammodel.SettingsUpdate,
+) (*teammodel.Team, error) {
+	m.update = update
 	return m.createResp, m.createErr
 }
 
+func (m *teamServiceMock) GetSettings(_ context.Context, name string) (teammodel.Settings, error) {
+	return m.settings, m.settingsErr
+}
+func (m *teamServiceMock) UpdateSettings(_ context.Context, name string, update teammodel.SettingsUpdate) (teammodel.Settings, error) {
+	m.update = update
+	return m.settings.Apply(update), m.settingsErr
+}
+
+func (m *teamServiceMock) DeactivateUsers(
+	_ context.Context,
+	name string,
+	userIDs []string,
+) ([]usermodel.User, prmodel.RebalanceReport, error) {
+	users := make([]usermodel.User, 0, len(userIDs))
+	for _, id := range userIDs {
+		users = append(users, usermodel.User{UserID: id, TeamName: name})
+	}
+	return users, m.report, m.deactErr
+}
+
+func (m *teamServiceMock) RenameTeam(_ context.Context, name, newName string) error {
+	return m.renameErr
+}
+
+func (m *teamServiceMock) DeleteTeam(
+	_ context.Context,
+	name, targetTeam string,
+) ([]usermodel.User, prmodel.RebalanceReport, error) {
+	return m.members, m.report, m.deactErr
+}
+
 func TestTeamHandler_CreateTeam_Created(t *testing.T) {
 	h := NewTeamHandler(&teamServiceMock{
 		createResp: &teammodel.Team{Name: "backend"},
@@ -55,3 +100,173 @@ func TestTeamHandler_GetTeam_MissingQuery(t *testing.T) {
 		t.Fatalf("expected 400, got %d", w.Code)
 	}
 }
+
+func TestTeamHandler_UpdateSettings_UnknownStrategy(t *testing.T) {
+	h := NewTeamHandler(&teamServiceMock{})
+	body := map[string]string{"team_name": "backend", "reviewer_strategy": "ALPHABETICAL"}
+	b, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/team/settings", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.UpdateSettings(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestTeamHandler_UpdateSettings_OK(t *testing.T) {
+	svc := &teamServiceMock{}
+	h := NewTeamHandler(svc)
+	body := map[string]string{"team_name": "backend", "reviewer_strategy": "ROUND_ROBIN"}
+	b, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/team/settings", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.UpdateSettings(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body=%s", w.Code, w.Body.String())
+	}
+	var resp TeamSettingsDTO
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.ReviewerStrategy != "ROUND_ROBIN" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestTeamHandler_CreateTeam_InvalidRequiredReviewers(t *testing.T) {
+	h := NewTeamHandler(&teamServiceMock{createResp: &teammodel.Team{Name: "backend"}})
+	b := []byte(`{"team_name":"backend","members":[],"required_reviewers":0}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/add", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.CreateTeam(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestTeamHandler_CreateTeam_PassesSettings(t *testing.T) {
+	svc := &teamServiceMock{createResp: &teammodel.Team{Name: "security"}}
+	h := NewTeamHandler(svc)
+	b := []byte(`{"team_name":"security","members":[],"required_reviewers":3}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/add", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.CreateTeam(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d; body=%s", w.Code, w.Body.String())
+	}
+	if svc.update.RequiredReviewers == nil || *svc.update.RequiredReviewers != 3 {
+		t.Fatalf("expected required_reviewers to be passed, got %+v", svc.update)
+	}
+}
+
+func TestTeamHandler_CreateTeam_UserExists(t *testing.T) {
+	h := NewTeamHandler(&teamServiceMock{
+		createErr: core.Throw(core.ErrorUserExists, "user already in another team"),
+	})
+	b := []byte(`{"team_name":"backend","members":[{"user_id":"u1","username":"a","is_active":true}]}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/add", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.CreateTeam(w, req)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d; body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestTeamHandler_DeactivateUsers_OK(t *testing.T) {
+	h := NewTeamHandler(&teamServiceMock{report: prmodel.RebalanceReport{
+		Moved: []prmodel.ReviewerMove{{PullRequestID: "pr-1", FromUserID: "u1", ToUserID: "u3"}},
+	}})
+	b := []byte(`{"team_name":"backend","user_ids":["u1","u2"]}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/deactivateUsers", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.DeactivateUsers(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body=%s", w.Code, w.Body.String())
+	}
+	var resp DeactivateUsersResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Users) != 2 || len(resp.Moved) != 1 || resp.Unassigned == nil {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestTeamHandler_DeactivateUsers_EmptyList(t *testing.T) {
+	h := NewTeamHandler(&teamServiceMock{})
+	b := []byte(`{"team_name":"backend","user_ids":[]}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/deactivateUsers", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.DeactivateUsers(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestTeamHandler_UpdateSettings_RejectsSelfFallback(t *testing.T) {
+	h := NewTeamHandler(&teamServiceMock{})
+	b := []byte(`{"team_name":"backend","fallback_teams":["platform","backend"]}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/settings", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.UpdateSettings(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestTeamHandler_DeleteTeam_Validation(t *testing.T) {
+	h := NewTeamHandler(&teamServiceMock{})
+	cases := []string{
+		`{"team_name":"backend"}`,
+		`{"team_name":"backend","target_team":"infra","deactivate_members":true}`,
+		`{"team_name":"backend","target_team":"backend"}`,
+	}
+	for _, body := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/team/delete", bytes.NewReader([]byte(body)))
+		w := httptest.NewRecorder()
+		h.DeleteTeam(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", body, w.Code)
+		}
+	}
+}
+
+func TestTeamHandler_DeleteTeam_OK(t *testing.T) {
+	h := NewTeamHandler(&teamServiceMock{members: []usermodel.User{{UserID: "u1", TeamName: "infra", IsActive: true}}})
+	b := []byte(`{"team_name":"backend","target_team":"infra"}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/delete", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.DeleteTeam(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestTeamHandler_RenameTeam(t *testing.T) {
+	h := NewTeamHandler(&teamServiceMock{})
+	b := []byte(`{"team_name":"backend","new_name":"core"}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/rename", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.RenameTeam(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body=%s", w.Code, w.Body.String())
+	}
+	var resp RenameTeamResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.TeamName != "core" || resp.PreviousName != "backend" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestTeamHandler_RenameTeam_Taken(t *testing.T) {
+	h := NewTeamHandler(&teamServiceMock{renameErr: core.Throw(core.ErrorTeamExists, "team_name already exists")})
+	b := []byte(`{"team_name":"backend","new_name":"core"}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/rename", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.RenameTeam(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
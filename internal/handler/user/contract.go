@@ -8,6 +8,9 @@ import (
 )
 
 type userService interface {
-	SetIsActive(ctx context.Context, userID string, flag bool) (usermodel.User, error)
-	GetReviewerPRs(ctx context.Context, ReviewerID string) ([]prmodel.PullRequest, error)
+	SetIsActive(ctx context.Context, userID string, flag bool, reassign bool) (usermodel.User, *prmodel.RebalanceReport, error)
+	MoveTeam(ctx context.Context, userID, teamName string, reassign bool) (usermodel.User, *prmodel.RebalanceReport, error)
+	GetUser(ctx context.Context, userID string) (usermodel.Profile, error)
+	ListUsers(ctx context.Context, filter usermodel.ListFilter) (usermodel.Page, error)
+	GetReviewerPRs(ctx context.Context, filter prmodel.ReviewFilter) (prmodel.ReviewPage, error)
 }
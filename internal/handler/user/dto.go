@@ -1,22 +1,57 @@
 package handler
 
-import usermodel "avito-intern-test/internal/model/user"
+import (
+	"time"
+
+	prmodel "avito-intern-test/internal/model/pullrequest"
+	usermodel "avito-intern-test/internal/model/user"
+)
 
 type SetIsActiveRequest struct {
 	UserID   string `json:"user_id"`
 	IsActive bool   `json:"is_active"`
 }
 
+type MoveTeamRequest struct {
+	UserID          string `json:"user_id"`
+	TeamName        string `json:"team_name"`
+	ReassignReviews bool   `json:"reassign_reviews,omitempty"`
+}
+
+type UserWithReportResponse struct {
+	User         UserDTO             `json:"user"`
+	Reassignment *RebalanceReportDTO `json:"reassignment,omitempty"`
+}
+
+type ReviewerMoveDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldUserID     string `json:"old_user_id"`
+	NewUserID     string `json:"new_user_id"`
+}
+
+type UnassignedReviewDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+}
+
+type RebalanceReportDTO struct {
+	Moved      []ReviewerMoveDTO     `json:"moved"`
+	Unassigned []UnassignedReviewDTO `json:"unassigned"`
+}
+
 type PullRequestShortDTO struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
-	Status          string `json:"status"`
+	PullRequestID     string    `json:"pull_request_id"`
+	PullRequestName   string    `json:"pull_request_name"`
+	AuthorID          string    `json:"author_id"`
+	Status            string    `json:"status"`
+	AssignedReviewers []string  `json:"assigned_reviewers"`
+	CreatedAt         time.Time `json:"createdAt"`
 }
 
 type GetReviewResponse struct {
 	UserID       string                `json:"user_id"`
 	PullRequests []PullRequestShortDTO `json:"pull_requests"`
+	NextCursor   string                `json:"next_cursor,omitempty"`
 }
 
 type PullRequestItem struct {
@@ -26,6 +61,27 @@ type PullRequestItem struct {
 	Status          string `json:"status"`
 }
 
+type UserProfileDTO struct {
+	UserDTO
+	OpenReviews     int             `json:"open_reviews"`
+	AuthoredOpenPRs []AuthoredPRDTO `json:"authored_open_prs"`
+}
+
+type AuthoredPRDTO struct {
+	PullRequestID   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+	Status          string `json:"status"`
+}
+
+type GetUserResponse struct {
+	User UserProfileDTO `json:"user"`
+}
+
+type ListUsersResponse struct {
+	Users      []UserDTO `json:"users"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
 type UserDTO struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
@@ -41,3 +97,43 @@ func userToDTO(u usermodel.User) UserDTO {
 		IsActive: u.IsActive,
 	}
 }
+
+func reportToDTO(r *prmodel.RebalanceReport) *RebalanceReportDTO {
+	if r == nil {
+		return nil
+	}
+	dto := &RebalanceReportDTO{
+		Moved:      make([]ReviewerMoveDTO, 0, len(r.Moved)),
+		Unassigned: make([]UnassignedReviewDTO, 0, len(r.Unassigned)),
+	}
+	for _, m := range r.Moved {
+		dto.Moved = append(dto.Moved, ReviewerMoveDTO{
+			PullRequestID: m.PullRequestID,
+			OldUserID:     m.FromUserID,
+			NewUserID:     m.ToUserID,
+		})
+	}
+	for _, u := range r.Unassigned {
+		dto.Unassigned = append(dto.Unassigned, UnassignedReviewDTO{
+			PullRequestID: u.PullRequestID,
+			UserID:        u.UserID,
+		})
+	}
+	return dto
+}
+
+func profileToDTO(p usermodel.Profile) UserProfileDTO {
+	dto := UserProfileDTO{
+		UserDTO:         userToDTO(p.User),
+		OpenReviews:     p.OpenReviews,
+		AuthoredOpenPRs: make([]AuthoredPRDTO, 0, len(p.AuthoredOpenPRs)),
+	}
+	for _, pr := range p.AuthoredOpenPRs {
+		dto.AuthoredOpenPRs = append(dto.AuthoredOpenPRs, AuthoredPRDTO{
+			PullRequestID:   pr.PullRequestID,
+			PullRequestName: pr.PullRequestName,
+			Status:          string(pr.Status),
+		})
+	}
+	return dto
+}
@@ -2,10 +2,14 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
-	"avito-intern-test/internal/core"
 	"avito-intern-test/internal/handler/common"
+	prmodel "avito-intern-test/internal/model/pullrequest"
+	usermodel "avito-intern-test/internal/model/user"
 )
 
 type UserHandler struct {
@@ -19,48 +23,211 @@ func NewUserHandler(service userService) *UserHandler {
 func (h *UserHandler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	var req SetIsActiveRequest
+	reassign, ok := parseBoolQuery(r, "reassign")
+	if !ok {
+		common.RespondWithError(w, http.StatusBadRequest, "reassign must be a boolean")
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "invalid json body")
+	} else {
+		user, report, err := h.service.SetIsActive(ctx, req.UserID, req.IsActive, reassign)
+		if err != nil {
+			common.RespondError(w, err)
+		} else {
+			common.RespondWithJSON(w, http.StatusOK, UserWithReportResponse{
+				User:         userToDTO(user),
+				Reassignment: reportToDTO(report),
+			})
+		}
+	}
+}
+
+func (h *UserHandler) MoveTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req MoveTeamRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		common.RespondWithError(w, http.StatusBadRequest, "invalid json body")
+	} else if req.UserID == "" || req.TeamName == "" {
+		common.RespondWithError(w, http.StatusBadRequest, "user_id and team_name are required")
 	} else {
-		user, err := h.service.SetIsActive(ctx, req.UserID, req.IsActive)
+		user, report, err := h.service.MoveTeam(ctx, req.UserID, req.TeamName, req.ReassignReviews)
 		if err != nil {
-			common.RespondAPIError(w, http.StatusNotFound, "NOT_FOUND", "resource not found")
+			common.RespondError(w, err)
 		} else {
-			common.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
-				"user": userToDTO(user),
+			common.RespondWithJSON(w, http.StatusOK, UserWithReportResponse{
+				User:         userToDTO(user),
+				Reassignment: reportToDTO(report),
 			})
 		}
 	}
 }
 
-func (h *UserHandler) GetReview(w http.ResponseWriter, r *http.Request) {
+func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	userID := r.URL.Query().Get("user_id")
 	if userID == "" {
 		common.RespondWithError(w, http.StatusBadRequest, "user_id is required")
 	} else {
-		prs, err := h.service.GetReviewerPRs(ctx, userID)
+		profile, err := h.service.GetUser(ctx, userID)
 		if err != nil {
-			if code, msg, ok := common.ParseCodeMessage(err); ok && code == core.ErrorNotFound {
-				common.RespondAPIError(w, http.StatusNotFound, code, msg)
-			} else {
-				common.RespondWithError(w, http.StatusInternalServerError, err.Error())
+			common.RespondError(w, err)
+		} else {
+			common.RespondWithJSON(w, http.StatusOK, GetUserResponse{User: profileToDTO(profile)})
+		}
+	}
+}
+
+func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	filter, msg := parseUserListFilter(r)
+	if msg != "" {
+		common.RespondWithError(w, http.StatusBadRequest, msg)
+	} else {
+		page, err := h.service.ListUsers(ctx, filter)
+		if err != nil {
+			common.RespondError(w, err)
+		} else {
+			resp := ListUsersResponse{Users: make([]UserDTO, 0, len(page.Users))}
+			for _, u := range page.Users {
+				resp.Users = append(resp.Users, userToDTO(u))
+			}
+			if page.Next != "" {
+				resp.NextCursor = usermodel.EncodeCursor(page.Next)
 			}
+			common.RespondWithJSON(w, http.StatusOK, resp)
+		}
+	}
+}
+
+func parseUserListFilter(r *http.Request) (usermodel.ListFilter, string) {
+	q := r.URL.Query()
+	filter := usermodel.ListFilter{
+		TeamName: q.Get("team_name"),
+		Limit:    usermodel.DefaultListLimit,
+	}
+	if raw := q.Get("is_active"); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filter, "is_active must be a boolean"
+		}
+		filter.IsActive = &v
+	}
+	if raw := q.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > usermodel.MaxListLimit {
+			return filter, fmt.Sprintf("limit must be between 1 and %d", usermodel.MaxListLimit)
+		}
+		filter.Limit = limit
+	}
+	if raw := q.Get("cursor"); raw != "" {
+		after, err := usermodel.DecodeCursor(raw)
+		if err != nil {
+			return filter, "cursor is invalid"
+		}
+		filter.After = after
+	}
+	return filter, ""
+}
+
+func (h *UserHandler) GetReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	filter, msg := parseReviewFilter(r)
+	if msg != "" {
+		common.RespondWithError(w, http.StatusBadRequest, msg)
+	} else {
+		page, err := h.service.GetReviewerPRs(ctx, filter)
+		if err != nil {
+			common.RespondError(w, err)
 		} else {
-			items := make([]PullRequestShortDTO, 0, len(prs))
-			for _, p := range prs {
+			items := make([]PullRequestShortDTO, 0, len(page.PullRequests))
+			for _, p := range page.PullRequests {
+				reviewers := p.AssignedReviewers
+				if reviewers == nil {
+					reviewers = []string{}
+				}
 				items = append(items, PullRequestShortDTO{
-					PullRequestID:   p.PullRequestID,
-					PullRequestName: p.PullRequestName,
-					AuthorID:        p.AuthorID,
-					Status:          string(p.Status),
+					PullRequestID:     p.PullRequestID,
+					PullRequestName:   p.PullRequestName,
+					AuthorID:          p.AuthorID,
+					Status:            string(p.Status),
+					AssignedReviewers: reviewers,
+					CreatedAt:         p.CreatedAt,
 				})
 			}
 			resp := GetReviewResponse{
-				UserID:       userID,
+				UserID:       filter.ReviewerID,
 				PullRequests: items,
 			}
+			if page.Next != nil {
+				resp.NextCursor = page.Next.Encode()
+			}
 			common.RespondWithJSON(w, http.StatusOK, resp)
 		}
 	}
 }
+
+func parseReviewFilter(r *http.Request) (prmodel.ReviewFilter, string) {
+	q := r.URL.Query()
+	filter := prmodel.ReviewFilter{
+		ReviewerID: q.Get("user_id"),
+		Status:     prmodel.PullRequestStatus(q.Get("status")),
+		Limit:      prmodel.DefaultReviewPageLimit,
+	}
+	if filter.ReviewerID == "" {
+		return filter, "user_id is required"
+	}
+	if filter.Status != "" && !filter.Status.Valid() {
+		return filter, "status must be one of DRAFT, OPEN, MERGED, CLOSED"
+	}
+
+	if raw := q.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > prmodel.MaxReviewPageLimit {
+			return filter, fmt.Sprintf("limit must be between 1 and %d", prmodel.MaxReviewPageLimit)
+		}
+		filter.Limit = limit
+	}
+
+	bounds := []struct {
+		key string
+		dst **time.Time
+	}{
+		{"created_after", &filter.CreatedAfter},
+		{"created_before", &filter.CreatedBefore},
+	}
+	for _, b := range bounds {
+		raw := q.Get(b.key)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return filter, b.key + " must be an RFC3339 timestamp"
+		}
+		t = t.UTC()
+		*b.dst = &t
+	}
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && !filter.CreatedAfter.Before(*filter.CreatedBefore) {
+		return filter, "created_after must be before created_before"
+	}
+
+	if raw := q.Get("cursor"); raw != "" {
+		cursor, err := prmodel.DecodeReviewCursor(raw)
+		if err != nil {
+			return filter, "cursor is invalid"
+		}
+		filter.After = &cursor
+	}
+	return filter, ""
+}
+
+func parseBoolQuery(r *http.Request, key string) (bool, bool) {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return false, true
+	}
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, false
+	}
+	return v, true
+}
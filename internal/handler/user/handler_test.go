@@ -7,28 +7,66 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"avito-intern-test/internal/core"
 	prmodel "avito-intern-test/internal/model/pullrequest"
 	usermodel "avito-intern-test/internal/model/user"
 )
 
 type userServiceMock struct {
-	setErr error
-	prs    []prmodel.PullRequest
-	prErr  error
+	setErr   error
+	report   *prmodel.RebalanceReport
+	reassign bool
+	page     prmodel.ReviewPage
+	filter   prmodel.ReviewFilter
+	prErr    error
+	listed   usermodel.ListFilter
 }
 
-func (m *userServiceMock) SetIsActive(_ context.Context, userID string, flag bool) (usermodel.User, error) {
+func (m *userServiceMock) SetIsActive(
+	_ context.Context,
+	userID string,
+	flag bool,
+	reassign bool,
+) (usermodel.User, *prmodel.RebalanceReport, error) {
+	m.reassign = reassign
 	return usermodel.User{
 		UserID:   userID,
 		Username: "x",
 		TeamName: "t",
 		IsActive: flag,
-	}, m.setErr
+	}, m.report, m.setErr
 }
 
-func (m *userServiceMock) GetReviewerPRs(_ context.Context, reviewerID string) ([]prmodel.PullRequest, error) {
-	return m.prs, m.prErr
+func (m *userServiceMock) MoveTeam(
+	_ context.Context,
+	userID, teamName string,
+	reassign bool,
+) (usermodel.User, *prmodel.RebalanceReport, error) {
+	m.reassign = reassign
+	return usermodel.User{UserID: userID, TeamName: teamName, IsActive: true}, m.report, m.setErr
+}
+
+func (m *userServiceMock) GetReviewerPRs(_ context.Context, filter prmodel.ReviewFilter) (prmodel.ReviewPage, error) {
+	m.filter = filter
+	return m.page, m.prErr
+}
+
+func (m *userServiceMock) GetUser(_ context.Context, userID string) (usermodel.Profile, error) {
+	if userID != "u1" {
+		return usermodel.Profile{}, core.Throw(core.ErrorNotFound, "user not found")
+	}
+	return usermodel.Profile{
+		User:            usermodel.User{UserID: "u1", Username: "a", TeamName: "backend", IsActive: true},
+		OpenReviews:     3,
+		AuthoredOpenPRs: []prmodel.PullRequestShort{{PullRequestID: "pr-1", Status: prmodel.PullRequestStatusOpen}},
+	}, nil
+}
+
+func (m *userServiceMock) ListUsers(_ context.Context, filter usermodel.ListFilter) (usermodel.Page, error) {
+	m.listed = filter
+	return usermodel.Page{Users: []usermodel.User{{UserID: "u1"}, {UserID: "u2"}}, Next: "u2"}, nil
 }
 
 func TestUserHandler_SetIsActive_OK(t *testing.T) {
@@ -52,3 +90,165 @@ func TestUserHandler_GetReview(t *testing.T) {
 		t.Fatalf("expected 400, got %d", w.Code)
 	}
 }
+
+func TestUserHandler_GetReview_Page(t *testing.T) {
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	next := prmodel.ReviewCursor{CreatedAt: created, PullRequestID: "pr-1"}
+	m := &userServiceMock{page: prmodel.ReviewPage{
+		PullRequests: []prmodel.PullRequest{{
+			PullRequestID:     "pr-1",
+			Status:            prmodel.PullRequestStatusOpen,
+			AssignedReviewers: []string{"u1", "u2"},
+			CreatedAt:         created,
+		}},
+		Next: &next,
+	}}
+	h := NewUserHandler(m)
+
+	req := httptest.NewRequest(http.MethodGet,
+		"/users/getReview?user_id=u1&status=OPEN&limit=1&created_after=2026-09-01T00:00:00Z&cursor="+next.Encode(), nil)
+	w := httptest.NewRecorder()
+	h.GetReview(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body=%s", w.Code, w.Body.String())
+	}
+	if m.filter.Limit != 1 || m.filter.Status != prmodel.PullRequestStatusOpen ||
+		m.filter.CreatedAfter == nil || m.filter.After == nil || m.filter.After.PullRequestID != "pr-1" {
+		t.Fatalf("unexpected filter: %+v", m.filter)
+	}
+
+	var resp GetReviewResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.NextCursor != next.Encode() || len(resp.PullRequests) != 1 ||
+		len(resp.PullRequests[0].AssignedReviewers) != 2 || !resp.PullRequests[0].CreatedAt.Equal(created) {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestUserHandler_GetReview_BadParams(t *testing.T) {
+	h := NewUserHandler(&userServiceMock{})
+	for _, q := range []string{"status=WIP", "limit=0", "limit=abc", "cursor=bm90LWEtY3Vyc29y", "created_after=yesterday"} {
+		req := httptest.NewRequest(http.MethodGet, "/users/getReview?user_id=u1&"+q, nil)
+		w := httptest.NewRecorder()
+		h.GetReview(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", q, w.Code)
+		}
+	}
+}
+
+func TestUserHandler_SetIsActive_Reassign(t *testing.T) {
+	m := &userServiceMock{report: &prmodel.RebalanceReport{
+		Moved:      []prmodel.ReviewerMove{{PullRequestID: "pr-1", FromUserID: "u1", ToUserID: "u2"}},
+		Unassigned: []prmodel.UnassignedReview{{PullRequestID: "pr-2", UserID: "u1"}},
+	}}
+	h := NewUserHandler(m)
+	b := []byte(`{"user_id":"u1","is_active":false}`)
+	req := httptest.NewRequest(http.MethodPost, "/users/setIsActive?reassign=true", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.SetIsActive(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body=%s", w.Code, w.Body.String())
+	}
+	if !m.reassign {
+		t.Fatalf("expected reassign flag to reach the service")
+	}
+	var resp UserWithReportResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Reassignment == nil || len(resp.Reassignment.Moved) != 1 || len(resp.Reassignment.Unassigned) != 1 {
+		t.Fatalf("unexpected report: %+v", resp.Reassignment)
+	}
+}
+
+func TestUserHandler_SetIsActive_BadReassignFlag(t *testing.T) {
+	h := NewUserHandler(&userServiceMock{})
+	b := []byte(`{"user_id":"u1","is_active":false}`)
+	req := httptest.NewRequest(http.MethodPost, "/users/setIsActive?reassign=maybe", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.SetIsActive(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestUserHandler_MoveTeam(t *testing.T) {
+	m := &userServiceMock{}
+	h := NewUserHandler(m)
+	b := []byte(`{"user_id":"u1","team_name":"infra","reassign_reviews":true}`)
+	req := httptest.NewRequest(http.MethodPost, "/users/moveTeam", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.MoveTeam(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body=%s", w.Code, w.Body.String())
+	}
+	if !m.reassign {
+		t.Fatalf("expected reassign_reviews to reach the service")
+	}
+}
+
+func TestUserHandler_MoveTeam_MissingTeam(t *testing.T) {
+	h := NewUserHandler(&userServiceMock{})
+	b := []byte(`{"user_id":"u1"}`)
+	req := httptest.NewRequest(http.MethodPost, "/users/moveTeam", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.MoveTeam(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestUserHandler_GetUser(t *testing.T) {
+	h := NewUserHandler(&userServiceMock{})
+	req := httptest.NewRequest(http.MethodGet, "/users/get?user_id=u1", nil)
+	w := httptest.NewRecorder()
+	h.GetUser(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body=%s", w.Code, w.Body.String())
+	}
+	var resp GetUserResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.User.TeamName != "backend" || resp.User.OpenReviews != 3 || len(resp.User.AuthoredOpenPRs) != 1 {
+		t.Fatalf("unexpected profile: %+v", resp.User)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/users/get?user_id=ghost", nil)
+	w = httptest.NewRecorder()
+	h.GetUser(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestUserHandler_ListUsers(t *testing.T) {
+	m := &userServiceMock{}
+	h := NewUserHandler(m)
+	req := httptest.NewRequest(http.MethodGet, "/users/list?team_name=backend&is_active=false&limit=2&cursor="+usermodel.EncodeCursor("u0"), nil)
+	w := httptest.NewRecorder()
+	h.ListUsers(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body=%s", w.Code, w.Body.String())
+	}
+	if m.listed.TeamName != "backend" || m.listed.IsActive == nil || *m.listed.IsActive || m.listed.Limit != 2 || m.listed.After != "u0" {
+		t.Fatalf("unexpected filter: %+v", m.listed)
+	}
+	var resp ListUsersResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Users) != 2 || resp.NextCursor != usermodel.EncodeCursor("u2") {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/users/list?is_active=maybe", nil)
+	w = httptest.NewRecorder()
+	h.ListUsers(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"context"
+
+	webhookmodel "avito-intern-test/internal/model/webhook"
+)
+
+type webhookService interface {
+	CreateWebhook(ctx context.Context, wh webhookmodel.Webhook) (webhookmodel.Webhook, error)
+	GetWebhook(ctx context.Context, id int64) (webhookmodel.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]webhookmodel.Webhook, error)
+	UpdateWebhook(ctx context.Context, id int64, update webhookmodel.Update) (webhookmodel.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	Deliveries(ctx context.Context, id int64) ([]webhookmodel.Delivery, error)
+}
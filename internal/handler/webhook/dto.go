@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"time"
+
+	eventmodel "avito-intern-test/internal/model/event"
+	webhookmodel "avito-intern-test/internal/model/webhook"
+)
+
+type CreateWebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+type UpdateWebhookRequest struct {
+	WebhookID int64     `json:"webhook_id"`
+	URL       *string   `json:"url,omitempty"`
+	Events    *[]string `json:"events,omitempty"`
+	IsActive  *bool     `json:"is_active,omitempty"`
+}
+
+type DeleteWebhookRequest struct {
+	WebhookID int64 `json:"webhook_id"`
+}
+
+type WebhookDTO struct {
+	WebhookID int64     `json:"webhook_id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"createdAt"`
+	Secret    string    `json:"secret,omitempty"`
+}
+
+type WebhookResponse struct {
+	Webhook WebhookDTO `json:"webhook"`
+}
+
+type ListWebhooksResponse struct {
+	Webhooks []WebhookDTO `json:"webhooks"`
+}
+
+type DeliveryDTO struct {
+	DeliveryID int64     `json:"delivery_id"`
+	EventID    string    `json:"event_id"`
+	EventType  string    `json:"event_type"`
+	Attempt    int       `json:"attempt"`
+	StatusCode *int      `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Success    bool      `json:"success"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type DeliveriesResponse struct {
+	WebhookID  int64         `json:"webhook_id"`
+	Deliveries []DeliveryDTO `json:"deliveries"`
+}
+
+func webhookToDTO(wh webhookmodel.Webhook) WebhookDTO {
+	events := make([]string, 0, len(wh.Events))
+	for _, e := range wh.Events {
+		events = append(events, string(e))
+	}
+	return WebhookDTO{
+		WebhookID: wh.ID,
+		URL:       wh.URL,
+		Events:    events,
+		IsActive:  wh.IsActive,
+		CreatedAt: wh.CreatedAt,
+	}
+}
+
+func deliveryToDTO(d webhookmodel.Delivery) DeliveryDTO {
+	return DeliveryDTO{
+		DeliveryID: d.ID,
+		EventID:    d.EventID,
+		EventType:  string(d.EventType),
+		Attempt:    d.Attempt,
+		StatusCode: d.StatusCode,
+		Error:      d.Error,
+		Success:    d.Success,
+		CreatedAt:  d.CreatedAt,
+	}
+}
+
+func parseEvents(raw []string) ([]eventmodel.Type, string) {
+	if len(raw) == 0 {
+		return nil, "events must not be empty"
+	}
+	events := make([]eventmodel.Type, 0, len(raw))
+	for _, e := range raw {
+		t := eventmodel.Type(e)
+		if !t.Valid() {
+			return nil, "unknown event: " + e
+		}
+		events = append(events, t)
+	}
+	return events, ""
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"avito-intern-test/internal/handler/common"
+	webhookmodel "avito-intern-test/internal/model/webhook"
+)
+
+type WebhookHandler struct {
+	service webhookService
+}
+
+func NewWebhookHandler(service webhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "invalid json body")
+		return
+	}
+	if msg := validateURL(req.URL); msg != "" {
+		common.RespondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+	events, msg := parseEvents(req.Events)
+	if msg != "" {
+		common.RespondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	wh, err := h.service.CreateWebhook(ctx, webhookmodel.Webhook{URL: req.URL, Events: events})
+	if err != nil {
+		common.RespondError(w, err)
+	} else {
+		dto := webhookToDTO(wh)
+		dto.Secret = wh.Secret
+		common.RespondWithJSON(w, http.StatusCreated, WebhookResponse{Webhook: dto})
+	}
+}
+
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, ok := parseWebhookID(r.URL.Query().Get("webhook_id"))
+	if !ok {
+		common.RespondWithError(w, http.StatusBadRequest, "webhook_id must be a positive integer")
+	} else if wh, err := h.service.GetWebhook(ctx, id); err != nil {
+		common.RespondError(w, err)
+	} else {
+		common.RespondWithJSON(w, http.StatusOK, WebhookResponse{Webhook: webhookToDTO(wh)})
+	}
+}
+
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.service.ListWebhooks(r.Context())
+	if err != nil {
+		common.RespondError(w, err)
+	} else {
+		items := make([]WebhookDTO, 0, len(webhooks))
+		for _, wh := range webhooks {
+			items = append(items, webhookToDTO(wh))
+		}
+		common.RespondWithJSON(w, http.StatusOK, ListWebhooksResponse{Webhooks: items})
+	}
+}
+
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req UpdateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "invalid json body")
+		return
+	}
+	if req.WebhookID <= 0 {
+		common.RespondWithError(w, http.StatusBadRequest, "webhook_id is required")
+		return
+	}
+
+	update := webhookmodel.Update{URL: req.URL, IsActive: req.IsActive}
+	if req.URL != nil {
+		if msg := validateURL(*req.URL); msg != "" {
+			common.RespondWithError(w, http.StatusBadRequest, msg)
+			return
+		}
+	}
+	if req.Events != nil {
+		events, msg := parseEvents(*req.Events)
+		if msg != "" {
+			common.RespondWithError(w, http.StatusBadRequest, msg)
+			return
+		}
+		update.Events = &events
+	}
+
+	wh, err := h.service.UpdateWebhook(ctx, req.WebhookID, update)
+	if err != nil {
+		common.RespondError(w, err)
+	} else {
+		common.RespondWithJSON(w, http.StatusOK, WebhookResponse{Webhook: webhookToDTO(wh)})
+	}
+}
+
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req DeleteWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.RespondWithError(w, http.StatusBadRequest, "invalid json body")
+	} else if req.WebhookID <= 0 {
+		common.RespondWithError(w, http.StatusBadRequest, "webhook_id is required")
+	} else if err := h.service.DeleteWebhook(ctx, req.WebhookID); err != nil {
+		common.RespondError(w, err)
+	} else {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *WebhookHandler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, ok := parseWebhookID(r.URL.Query().Get("webhook_id"))
+	if !ok {
+		common.RespondWithError(w, http.StatusBadRequest, "webhook_id must be a positive integer")
+		return
+	}
+
+	deliveries, err := h.service.Deliveries(ctx, id)
+	if err != nil {
+		common.RespondError(w, err)
+	} else {
+		items := make([]DeliveryDTO, 0, len(deliveries))
+		for _, d := range deliveries {
+			items = append(items, deliveryToDTO(d))
+		}
+		common.RespondWithJSON(w, http.StatusOK, DeliveriesResponse{WebhookID: id, Deliveries: items})
+	}
+}
+
+func parseWebhookID(raw string) (int64, bool) {
+	id, err := strconv.ParseInt(raw, 10, 64)
+	return id, err == nil && id > 0
+}
+
+func validateURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "url must be an absolute http(s) URL"
+	}
+	return ""
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"avito-intern-test/internal/core"
+	eventmodel "avito-intern-test/internal/model/event"
+	webhookmodel "avito-intern-test/internal/model/webhook"
+)
+
+type webhookServiceMock struct {
+	created webhookmodel.Webhook
+	update  webhookmodel.Update
+}
+
+func (m *webhookServiceMock) CreateWebhook(_ context.Context, wh webhookmodel.Webhook) (webhookmodel.Webhook, error) {
+	m.created = wh
+	wh.ID = 1
+	wh.Secret = "secret"
+	wh.IsActive = true
+	return wh, nil
+}
+
+func (m *webhookServiceMock) GetWebhook(_ context.Context, id int64) (webhookmodel.Webhook, error) {
+	if id != 1 {
+		return webhookmodel.Webhook{}, core.Throw(core.ErrorNotFound, "webhook not found")
+	}
+	return webhookmodel.Webhook{ID: 1, URL: "http://example.test", Secret: "secret", IsActive: true}, nil
+}
+
+func (m *webhookServiceMock) ListWebhooks(context.Context) ([]webhookmodel.Webhook, error) {
+	return []webhookmodel.Webhook{{ID: 1}, {ID: 2}}, nil
+}
+
+func (m *webhookServiceMock) UpdateWebhook(
+	_ context.Context,
+	id int64,
+	update webhookmodel.Update,
+) (webhookmodel.Webhook, error) {
+	m.update = update
+	return webhookmodel.Webhook{ID: id}, nil
+}
+
+func (m *webhookServiceMock) DeleteWebhook(context.Context, int64) error {
+	return nil
+}
+
+func (m *webhookServiceMock) Deliveries(context.Context, int64) ([]webhookmodel.Delivery, error) {
+	status := 200
+	return []webhookmodel.Delivery{{ID: 7, EventID: "e1", Attempt: 1, StatusCode: &status, Success: true}}, nil
+}
+
+func TestWebhookHandler_Create(t *testing.T) {
+	m := &webhookServiceMock{}
+	h := NewWebhookHandler(m)
+	b := []byte(`{"url":"https://bot.example/hook","events":["pull_request.merged"]}`)
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/create", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.CreateWebhook(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d; body=%s", w.Code, w.Body.String())
+	}
+	if len(m.created.Events) != 1 || m.created.Events[0] != eventmodel.TypePullRequestMerged {
+		t.Fatalf("unexpected webhook: %+v", m.created)
+	}
+	var resp WebhookResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Webhook.Secret != "secret" || resp.Webhook.WebhookID != 1 {
+		t.Fatalf("expected secret in create response: %+v", resp.Webhook)
+	}
+}
+
+func TestWebhookHandler_Create_BadInput(t *testing.T) {
+	h := NewWebhookHandler(&webhookServiceMock{})
+	for _, body := range []string{
+		`{"url":"ftp://bot.example","events":["pull_request.merged"]}`,
+		`{"url":"/relative","events":["pull_request.merged"]}`,
+		`{"url":"https://bot.example","events":[]}`,
+		`{"url":"https://bot.example","events":["pull_request.opened"]}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/create", bytes.NewReader([]byte(body)))
+		w := httptest.NewRecorder()
+		h.CreateWebhook(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", body, w.Code)
+		}
+	}
+}
+
+func TestWebhookHandler_Get_HidesSecret(t *testing.T) {
+	h := NewWebhookHandler(&webhookServiceMock{})
+	req := httptest.NewRequest(http.MethodGet, "/webhooks/get?webhook_id=1", nil)
+	w := httptest.NewRecorder()
+	h.GetWebhook(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if bytes.Contains(w.Body.Bytes(), []byte("secret")) {
+		t.Fatalf("secret must not be exposed: %s", w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/webhooks/get?webhook_id=2", nil)
+	w = httptest.NewRecorder()
+	h.GetWebhook(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestWebhookHandler_Update(t *testing.T) {
+	m := &webhookServiceMock{}
+	h := NewWebhookHandler(m)
+	b := []byte(`{"webhook_id":1,"is_active":false}`)
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/update", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.UpdateWebhook(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body=%s", w.Code, w.Body.String())
+	}
+	if m.update.IsActive == nil || *m.update.IsActive || m.update.URL != nil || m.update.Events != nil {
+		t.Fatalf("unexpected update: %+v", m.update)
+	}
+}
+
+func TestWebhookHandler_Update_RejectsNonHTTPURL(t *testing.T) {
+	m := &webhookServiceMock{}
+	h := NewWebhookHandler(m)
+	b := []byte(`{"webhook_id":1,"url":"file:///etc/passwd"}`)
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/update", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	h.UpdateWebhook(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+	if m.update.URL != nil {
+		t.Fatalf("invalid url must not reach the service: %+v", m.update)
+	}
+}
+
+func TestWebhookHandler_Deliveries(t *testing.T) {
+	h := NewWebhookHandler(&webhookServiceMock{})
+	req := httptest.NewRequest(http.MethodGet, "/webhooks/deliveries?webhook_id=1", nil)
+	w := httptest.NewRecorder()
+	h.Deliveries(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var resp DeliveriesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Deliveries) != 1 || *resp.Deliveries[0].StatusCode != 200 {
+		t.Fatalf("unexpected deliveries: %+v", resp)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/webhooks/deliveries?webhook_id=abc", nil)
+	w = httptest.NewRecorder()
+	h.Deliveries(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every metric the service exposes. A private registry keeps
+// tests independent of whatever else registers on the global default.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by method, route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by method and route pattern.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	ReviewersAssigned = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "pull_request_reviewers_assigned",
+		Help:    "Reviewers assigned to a pull request when it opens.",
+		Buckets: []float64{0, 1, 2, 3, 4, 5},
+	})
+
+	NoCandidate = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "reviewer_no_candidate_total",
+		Help: "Reassignments rejected with NO_CANDIDATE, by reviewer team.",
+	}, []string{"team"})
+
+	Reassignments = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "reviewer_reassignments_total",
+		Help: "Successful reviewer reassignments, by reviewer team.",
+	}, []string{"team"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPDuration,
+		ReviewersAssigned,
+		NoCandidate,
+		Reassignments,
+	)
+}
+
+// CountReassignments records n reviewers of team replaced on open PRs,
+// whether reassigned one at a time or released in bulk.
+func CountReassignments(team string, n int) {
+	if n > 0 {
+		Reassignments.WithLabelValues(team).Add(float64(n))
+	}
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
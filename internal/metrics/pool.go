@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	poolAcquiredConns = prometheus.NewDesc("pgxpool_acquired_conns", "Connections currently in use.", nil, nil)
+	poolIdleConns     = prometheus.NewDesc("pgxpool_idle_conns", "Idle connections in the pool.", nil, nil)
+	poolTotalConns    = prometheus.NewDesc("pgxpool_total_conns", "Connections currently open.", nil, nil)
+	poolMaxConns      = prometheus.NewDesc("pgxpool_max_conns", "Maximum size of the pool.", nil, nil)
+	poolAcquires      = prometheus.NewDesc("pgxpool_acquires_total", "Successful connection acquires.", nil, nil)
+	poolEmptyAcquires = prometheus.NewDesc(
+		"pgxpool_empty_acquires_total",
+		"Acquires that had to wait because no idle connection was available.",
+		nil, nil,
+	)
+	poolCanceledAcquires = prometheus.NewDesc(
+		"pgxpool_canceled_acquires_total",
+		"Acquires canceled by their context.",
+		nil, nil,
+	)
+	poolAcquireSeconds = prometheus.NewDesc(
+		"pgxpool_acquire_duration_seconds_total",
+		"Total time spent waiting for a connection.",
+		nil, nil,
+	)
+)
+
+type poolCollector struct {
+	pool *pgxpool.Pool
+}
+
+// RegisterPool exposes the pool's statistics, read on every scrape.
+func RegisterPool(pool *pgxpool.Pool) {
+	Registry.MustRegister(poolCollector{pool: pool})
+}
+
+func (c poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolAcquiredConns
+	ch <- poolIdleConns
+	ch <- poolTotalConns
+	ch <- poolMaxConns
+	ch <- poolAcquires
+	ch <- poolEmptyAcquires
+	ch <- poolCanceledAcquires
+	ch <- poolAcquireSeconds
+}
+
+func (c poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(poolAcquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(poolIdleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(poolTotalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(poolMaxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(poolAcquires, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolEmptyAcquires, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolCanceledAcquires, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolAcquireSeconds, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+}
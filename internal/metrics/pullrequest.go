@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	prmodel "avito-intern-test/internal/model/pullrequest"
+)
+
+const statusQueryTimeout = 5 * time.Second
+
+var pullRequestsDesc = prometheus.NewDesc(
+	"pull_requests",
+	"Pull requests by status.",
+	[]string{"status"}, nil,
+)
+
+type statusCounter interface {
+	StatusCounts(ctx context.Context) (map[prmodel.PullRequestStatus]int, error)
+}
+
+type pullRequestCollector struct {
+	counter statusCounter
+}
+
+// RegisterPullRequests exposes PR counts per status, queried on every scrape
+// so the numbers are right no matter which replica handled the changes.
+func RegisterPullRequests(counter statusCounter) {
+	Registry.MustRegister(pullRequestCollector{counter: counter})
+}
+
+func (c pullRequestCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- pullRequestsDesc
+}
+
+func (c pullRequestCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), statusQueryTimeout)
+	defer cancel()
+
+	counts, err := c.counter.StatusCounts(ctx)
+	if err != nil {
+		log.Printf("metrics: %v", err)
+		ch <- prometheus.NewInvalidMetric(pullRequestsDesc, err)
+		return
+	}
+	for _, status := range []prmodel.PullRequestStatus{
+		prmodel.PullRequestStatusDraft,
+		prmodel.PullRequestStatusOpen,
+		prmodel.PullRequestStatusMerged,
+		prmodel.PullRequestStatusClosed,
+	} {
+		ch <- prometheus.MustNewConstMetric(pullRequestsDesc, prometheus.GaugeValue, float64(counts[status]), string(status))
+	}
+}
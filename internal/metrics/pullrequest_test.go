@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	prmodel "avito-intern-test/internal/model/pullrequest"
+)
+
+type statusCounterMock struct {
+	counts map[prmodel.PullRequestStatus]int
+	err    error
+}
+
+func (m statusCounterMock) StatusCounts(context.Context) (map[prmodel.PullRequestStatus]int, error) {
+	return m.counts, m.err
+}
+
+func TestPullRequestCollector(t *testing.T) {
+	c := pullRequestCollector{counter: statusCounterMock{counts: map[prmodel.PullRequestStatus]int{
+		prmodel.PullRequestStatusOpen:   3,
+		prmodel.PullRequestStatusMerged: 7,
+	}}}
+
+	expected := `
+# HELP pull_requests Pull requests by status.
+# TYPE pull_requests gauge
+pull_requests{status="CLOSED"} 0
+pull_requests{status="DRAFT"} 0
+pull_requests{status="MERGED"} 7
+pull_requests{status="OPEN"} 3
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPullRequestCollector_Error(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(pullRequestCollector{counter: statusCounterMock{err: errors.New("db down")}})
+	if _, err := reg.Gather(); err == nil {
+		t.Fatal("expected the scrape to report the query error")
+	}
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"avito-intern-test/internal/core"
+)
+
+// Key is an issued API key. The key itself is never stored, only its hash.
+type Key struct {
+	ID        int64      `json:"key_id"`
+	Name      string     `json:"name"`
+	Role      core.Role  `json:"role"`
+	TeamName  string     `json:"team_name,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+func (k Key) Principal() core.Principal {
+	return core.Principal{
+		KeyID:    k.ID,
+		Name:     k.Name,
+		Role:     k.Role,
+		TeamName: k.TeamName,
+	}
+}
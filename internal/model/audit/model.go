@@ -0,0 +1,124 @@
+package model
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"time"
+
+	"avito-intern-test/internal/core"
+)
+
+type Action string
+
+const (
+	ActionPullRequestCreate   Action = "pull_request.create"
+	ActionPullRequestMerge    Action = "pull_request.merge"
+	ActionPullRequestReview   Action = "pull_request.review"
+	ActionPullRequestReady    Action = "pull_request.ready"
+	ActionPullRequestClose    Action = "pull_request.close"
+	ActionPullRequestReopen   Action = "pull_request.reopen"
+	ActionPullRequestReassign Action = "pull_request.reassign"
+
+	ActionTeamCreate          Action = "team.create"
+	ActionTeamUpdateSettings  Action = "team.update_settings"
+	ActionTeamDeactivateUsers Action = "team.deactivate_users"
+	ActionTeamRename          Action = "team.rename"
+	ActionTeamDelete          Action = "team.delete"
+
+	ActionUserSetIsActive Action = "user.set_is_active"
+	ActionUserMoveTeam    Action = "user.move_team"
+
+	ActionWebhookCreate Action = "webhook.create"
+	ActionWebhookUpdate Action = "webhook.update"
+	ActionWebhookDelete Action = "webhook.delete"
+
+	ActionAPIKeyIssue  Action = "api_key.issue"
+	ActionAPIKeyRevoke Action = "api_key.revoke"
+)
+
+type EntityType string
+
+const (
+	EntityPullRequest EntityType = "pull_request"
+	EntityTeam        EntityType = "team"
+	EntityUser        EntityType = "user"
+	EntityWebhook     EntityType = "webhook"
+	EntityAPIKey      EntityType = "api_key"
+)
+
+func (e EntityType) Valid() bool {
+	switch e {
+	case EntityPullRequest, EntityTeam, EntityUser, EntityWebhook, EntityAPIKey:
+		return true
+	}
+	return false
+}
+
+// Entry is one audited change. Before and After are stored as JSON; entries
+// read back carry them as json.RawMessage.
+type Entry struct {
+	ID         int64
+	Actor      string
+	Action     Action
+	EntityType EntityType
+	EntityID   string
+	Before     any
+	After      any
+	CreatedAt  time.Time
+}
+
+// New describes a change to an entity made by the actor in ctx. Services
+// append it in the transaction of the change.
+func New(ctx context.Context, action Action, entityType EntityType, entityID string, before, after any) Entry {
+	return Entry{
+		Actor:      core.ActorFrom(ctx),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     before,
+		After:      after,
+	}
+}
+
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 200
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ListFilter pages through entries newest first; After is the last entry id
+// of the previous page.
+type ListFilter struct {
+	Actor      string
+	Action     Action
+	EntityType EntityType
+	EntityID   string
+	Since      *time.Time
+	Until      *time.Time
+	Limit      int
+	After      int64
+}
+
+type Page struct {
+	Entries []Entry
+	Next    int64
+}
+
+func EncodeCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func DecodeCursor(s string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidCursor
+	}
+	return id, nil
+}
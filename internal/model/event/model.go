@@ -0,0 +1,85 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+type Type string
+
+const (
+	TypePullRequestCreated Type = "pull_request.created"
+	TypeReviewersAssigned  Type = "pull_request.reviewers_assigned"
+	TypeReviewerReassigned Type = "pull_request.reviewer_reassigned"
+	TypePullRequestMerged  Type = "pull_request.merged"
+	TypeTeamCreated        Type = "team.created"
+	TypeTeamMemberAdded    Type = "team.member_added"
+)
+
+var Types = []Type{
+	TypePullRequestCreated,
+	TypeReviewersAssigned,
+	TypeReviewerReassigned,
+	TypePullRequestMerged,
+	TypeTeamCreated,
+	TypeTeamMemberAdded,
+}
+
+func (t Type) Valid() bool {
+	for _, known := range Types {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is a domain notification. ID is unique per event so receivers can
+// drop duplicates caused by retries.
+type Event struct {
+	ID         string    `json:"id"`
+	Type       Type      `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// Pending is an outbox event claimed for delivery. Attempts counts the
+// deliveries of it that failed so far.
+type Pending struct {
+	Event
+	Attempts int
+}
+
+func New(t Type, data any) Event {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return Event{
+		ID:         hex.EncodeToString(id[:]),
+		Type:       t,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
+
+type PullRequestPayload struct {
+	PullRequestID     string     `json:"pull_request_id"`
+	PullRequestName   string     `json:"pull_request_name"`
+	AuthorID          string     `json:"author_id"`
+	Status            string     `json:"status"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	MergedAt          *time.Time `json:"merged_at,omitempty"`
+	OldReviewerID     string     `json:"old_reviewer_id,omitempty"`
+	NewReviewerID     string     `json:"new_reviewer_id,omitempty"`
+}
+
+type TeamPayload struct {
+	TeamName string `json:"team_name"`
+}
+
+type TeamMemberPayload struct {
+	TeamName string `json:"team_name"`
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Record is a stored response for an Idempotency-Key. StatusCode stays zero
+// while the original request is still being processed. Lease identifies the
+// request holding the reservation, so one that lost it to a takeover cannot
+// overwrite the new owner's response.
+type Record struct {
+	Key         string
+	Route       string
+	RequestHash string
+	Lease       string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+}
+
+func (r Record) Completed() bool {
+	return r.StatusCode != 0
+}
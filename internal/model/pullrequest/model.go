@@ -1,24 +1,78 @@
 package model
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
 	"time"
 )
 
 type PullRequestStatus string
 
 const (
+	PullRequestStatusDraft  PullRequestStatus = "DRAFT"
 	PullRequestStatusOpen   PullRequestStatus = "OPEN"
 	PullRequestStatusMerged PullRequestStatus = "MERGED"
+	PullRequestStatusClosed PullRequestStatus = "CLOSED"
 )
 
+var statusTransitions = map[PullRequestStatus][]PullRequestStatus{
+	PullRequestStatusDraft:  {PullRequestStatusOpen, PullRequestStatusClosed},
+	PullRequestStatusOpen:   {PullRequestStatusMerged, PullRequestStatusClosed},
+	PullRequestStatusClosed: {PullRequestStatusOpen},
+	PullRequestStatusMerged: {},
+}
+
+func (s PullRequestStatus) Valid() bool {
+	_, ok := statusTransitions[s]
+	return ok
+}
+
+func (s PullRequestStatus) CanTransitionTo(to PullRequestStatus) bool {
+	for _, next := range statusTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+type ReviewVerdict string
+
+const (
+	ReviewVerdictApproved         ReviewVerdict = "APPROVED"
+	ReviewVerdictChangesRequested ReviewVerdict = "CHANGES_REQUESTED"
+	ReviewVerdictCommented        ReviewVerdict = "COMMENTED"
+)
+
+func (v ReviewVerdict) Valid() bool {
+	switch v {
+	case ReviewVerdictApproved, ReviewVerdictChangesRequested, ReviewVerdictCommented:
+		return true
+	}
+	return false
+}
+
 type PullRequest struct {
-	PullRequestID     string
-	PullRequestName   string
-	AuthorID          string
-	Status            PullRequestStatus
-	AssignedReviewers []string
-	CreatedAt         time.Time
-	MergedAt          *time.Time
+	PullRequestID     string            `json:"pull_request_id"`
+	PullRequestName   string            `json:"pull_request_name"`
+	AuthorID          string            `json:"author_id"`
+	Status            PullRequestStatus `json:"status"`
+	AssignedReviewers []string          `json:"assigned_reviewers"`
+	CreatedAt         time.Time         `json:"created_at"`
+	MergedAt          *time.Time        `json:"merged_at,omitempty"`
+	// FallbackTeams lists partner teams reviewers were drawn from by the
+	// current operation; it is not persisted.
+	FallbackTeams []string `json:"-"`
+}
+
+// PullRequestDetails is a PR together with its active assignments and the
+// author's current team.
+type PullRequestDetails struct {
+	PullRequest
+	AuthorTeam string
+	Reviewers  []ReviewerAssignment
 }
 
 type PullRequestShort struct {
@@ -27,3 +81,148 @@ type PullRequestShort struct {
 	AuthorID        string
 	Status          PullRequestStatus
 }
+
+type ReviewerAssignment struct {
+	UserID     string
+	AssignedAt time.Time
+	ReplacedAt *time.Time
+	ReplacedBy *string
+	Verdict    *ReviewVerdict
+	ReviewedAt *time.Time
+}
+
+type ReviewerMove struct {
+	PullRequestID string `json:"pull_request_id"`
+	FromUserID    string `json:"from_user_id"`
+	ToUserID      string `json:"to_user_id"`
+}
+
+type UnassignedReview struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+}
+
+type RebalanceReport struct {
+	Moved      []ReviewerMove     `json:"moved"`
+	Unassigned []UnassignedReview `json:"unassigned"`
+}
+
+const (
+	DefaultReviewPageLimit = 50
+	MaxReviewPageLimit     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ReviewCursor points at the last PR of a page; pages are ordered by
+// created_at and pull_request_id, newest first.
+type ReviewCursor struct {
+	CreatedAt     time.Time
+	PullRequestID string
+}
+
+func (c ReviewCursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.PullRequestID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeReviewCursor(s string) (ReviewCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ReviewCursor{}, ErrInvalidCursor
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return ReviewCursor{}, ErrInvalidCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return ReviewCursor{}, ErrInvalidCursor
+	}
+	return ReviewCursor{CreatedAt: createdAt, PullRequestID: id}, nil
+}
+
+// ReviewFilter selects PRs where ReviewerID is an active reviewer. An empty
+// Status matches everything except closed PRs.
+type ReviewFilter struct {
+	ReviewerID    string
+	Status        PullRequestStatus
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Limit         int
+	After         *ReviewCursor
+}
+
+type ReviewPage struct {
+	PullRequests []PullRequest
+	Next         *ReviewCursor
+}
+
+type ListSort string
+
+const (
+	ListSortCreatedDesc ListSort = "created_at_desc"
+	ListSortCreatedAsc  ListSort = "created_at_asc"
+	ListSortNameAsc     ListSort = "name_asc"
+	ListSortNameDesc    ListSort = "name_desc"
+)
+
+func (s ListSort) Valid() bool {
+	switch s {
+	case ListSortCreatedDesc, ListSortCreatedAsc, ListSortNameAsc, ListSortNameDesc:
+		return true
+	}
+	return false
+}
+
+// ListCursor holds the sort key and id of the last PR on a page. Sort is
+// kept so a cursor cannot be replayed against a different ordering.
+type ListCursor struct {
+	Sort          ListSort `json:"s"`
+	Key           string   `json:"k"`
+	PullRequestID string   `json:"id"`
+}
+
+func (c ListCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// CreatedAt parses Key for the created_at orderings.
+func (c ListCursor) CreatedAt() (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, c.Key)
+	if err != nil {
+		return time.Time{}, ErrInvalidCursor
+	}
+	return t, nil
+}
+
+func DecodeListCursor(s string) (ListCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ListCursor{}, ErrInvalidCursor
+	}
+	var c ListCursor
+	if err := json.Unmarshal(raw, &c); err != nil || !c.Sort.Valid() || c.PullRequestID == "" {
+		return ListCursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+type ListFilter struct {
+	AuthorID      string
+	TeamName      string
+	Status        PullRequestStatus
+	ReviewerID    string
+	NameContains  string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Sort          ListSort
+	Limit         int
+	After         *ListCursor
+}
+
+type ListPage struct {
+	PullRequests []PullRequest
+	Next         *ListCursor
+}
@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+type Filter struct {
+	TeamName      string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	MergedAfter   *time.Time
+	MergedBefore  *time.Time
+}
+
+type UserStats struct {
+	UserID            string
+	Username          string
+	TeamName          string
+	IsActive          bool
+	TotalAssignments  int
+	OpenAssignments   int
+	MergedAssignments int
+}
+
+type PullRequestStats struct {
+	PullRequestID    string
+	PullRequestName  string
+	AuthorID         string
+	TeamName         string
+	Status           string
+	Reviewers        int
+	TotalAssignments int
+}
+
+type TeamStats struct {
+	TeamName           string
+	Members            int
+	ActiveMembers      int
+	TotalAssignments   int
+	OpenAssignments    int
+	MergedAssignments  int
+	PullRequests       int
+	OpenPullRequests   int
+	MergedPullRequests int
+}
+
+type Stats struct {
+	Users        []UserStats
+	PullRequests []PullRequestStats
+	Teams        []TeamStats
+}
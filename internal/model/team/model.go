@@ -2,6 +2,72 @@ package model
 
 import "time"
 
+type ReviewerStrategy string
+
+const (
+	ReviewerStrategyRandom      ReviewerStrategy = "RANDOM"
+	ReviewerStrategyRoundRobin  ReviewerStrategy = "ROUND_ROBIN"
+	ReviewerStrategyLeastLoaded ReviewerStrategy = "LEAST_LOADED"
+	ReviewerStrategyWeighted    ReviewerStrategy = "WEIGHTED"
+)
+
+const (
+	DefaultReviewerStrategy  = ReviewerStrategyLeastLoaded
+	DefaultRequiredReviewers = 2
+)
+
+func (s ReviewerStrategy) Valid() bool {
+	switch s {
+	case ReviewerStrategyRandom, ReviewerStrategyRoundRobin, ReviewerStrategyLeastLoaded, ReviewerStrategyWeighted:
+		return true
+	}
+	return false
+}
+
+type Settings struct {
+	ReviewerStrategy  ReviewerStrategy `json:"reviewer_strategy"`
+	RequiredReviewers int              `json:"required_reviewers"`
+	RequiredApprovals int              `json:"required_approvals"`
+	FallbackTeams     []string         `json:"fallback_teams"`
+}
+
+type SettingsUpdate struct {
+	ReviewerStrategy  *ReviewerStrategy
+	RequiredReviewers *int
+	RequiredApprovals *int
+	FallbackTeams     *[]string
+}
+
+func (s Settings) Apply(u SettingsUpdate) Settings {
+	if u.ReviewerStrategy != nil {
+		s.ReviewerStrategy = *u.ReviewerStrategy
+	}
+	if u.RequiredReviewers != nil {
+		s.RequiredReviewers = *u.RequiredReviewers
+	}
+	if u.RequiredApprovals != nil {
+		s.RequiredApprovals = *u.RequiredApprovals
+	}
+	if u.FallbackTeams != nil {
+		s.FallbackTeams = *u.FallbackTeams
+	}
+	return s
+}
+
+func (u SettingsUpdate) IsEmpty() bool {
+	return u.ReviewerStrategy == nil &&
+		u.RequiredReviewers == nil &&
+		u.RequiredApprovals == nil &&
+		u.FallbackTeams == nil
+}
+
+func (s Settings) ReviewersRequired() int {
+	if s.RequiredReviewers <= 0 {
+		return DefaultRequiredReviewers
+	}
+	return s.RequiredReviewers
+}
+
 type TeamMember struct {
 	ID        string
 	TeamID    string
@@ -13,5 +79,6 @@ type Team struct {
 	TeamID    string
 	Name      string
 	CreatedAt time.Time
+	Settings  Settings
 	Members   []TeamMember
 }
@@ -1,6 +1,12 @@
 package model
 
-import "time"
+import (
+	"encoding/base64"
+	"errors"
+	"time"
+
+	prmodel "avito-intern-test/internal/model/pullrequest"
+)
 
 type User struct {
 	UserID    string    `json:"user_id" db:"id"`
@@ -9,3 +15,44 @@ type User struct {
 	TeamName  string    `json:"team_name" db:"team_name"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
+
+// Profile is a user with their current review load and the open PRs they
+// authored.
+type Profile struct {
+	User
+	OpenReviews     int
+	AuthoredOpenPRs []prmodel.PullRequestShort
+}
+
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ListFilter pages through users ordered by user_id; After is the last
+// user_id of the previous page.
+type ListFilter struct {
+	TeamName string
+	IsActive *bool
+	Limit    int
+	After    string
+}
+
+type Page struct {
+	Users []User
+	Next  string
+}
+
+func EncodeCursor(userID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(userID))
+}
+
+func DecodeCursor(s string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(raw) == 0 {
+		return "", ErrInvalidCursor
+	}
+	return string(raw), nil
+}
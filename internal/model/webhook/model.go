@@ -0,0 +1,53 @@
+package model
+
+import (
+	"slices"
+	"time"
+
+	eventmodel "avito-intern-test/internal/model/event"
+)
+
+type Webhook struct {
+	ID        int64             `json:"webhook_id"`
+	URL       string            `json:"url"`
+	Secret    string            `json:"-"`
+	Events    []eventmodel.Type `json:"events"`
+	IsActive  bool              `json:"is_active"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+func (w Webhook) Subscribed(t eventmodel.Type) bool {
+	return w.IsActive && slices.Contains(w.Events, t)
+}
+
+type Update struct {
+	URL      *string
+	Events   *[]eventmodel.Type
+	IsActive *bool
+}
+
+func (w Webhook) Apply(u Update) Webhook {
+	if u.URL != nil {
+		w.URL = *u.URL
+	}
+	if u.Events != nil {
+		w.Events = *u.Events
+	}
+	if u.IsActive != nil {
+		w.IsActive = *u.IsActive
+	}
+	return w
+}
+
+// Delivery is one attempt to send an event to a webhook.
+type Delivery struct {
+	ID         int64
+	WebhookID  int64
+	EventID    string
+	EventType  eventmodel.Type
+	Attempt    int
+	StatusCode *int
+	Error      string
+	Success    bool
+	CreatedAt  time.Time
+}
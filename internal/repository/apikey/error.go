@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"errors"
+
+	"avito-intern-test/internal/core"
+)
+
+var (
+	ErrKeyNotFound = core.Throw(core.ErrorNotFound, "api key not found")
+	// ErrKeyExists is only reachable by re-registering a configured key, so
+	// it is not a client-facing error.
+	ErrKeyExists = errors.New("api key already exists")
+)
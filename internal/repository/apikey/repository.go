@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"avito-intern-test/internal/core"
+	apikeymodel "avito-intern-test/internal/model/apikey"
+)
+
+const keyColumns = "key_id, name, role, COALESCE(team_name, ''), created_at, revoked_at"
+
+type APIKeyRepository struct {
+	db *core.TxManager
+}
+
+func NewAPIKeyRepository(pool *pgxpool.Pool) *APIKeyRepository {
+	return &APIKeyRepository{db: core.NewTxManager(pool)}
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key apikeymodel.Key, keyHash string) (apikeymodel.Key, error) {
+	var teamName *string
+	if key.TeamName != "" {
+		teamName = &key.TeamName
+	}
+	query, args, err := sq.
+		Insert("api_keys").
+		Columns("name", "key_hash", "role", "team_name", "created_at").
+		Values(key.Name, keyHash, string(key.Role), teamName, time.Now().UTC()).
+		Suffix("ON CONFLICT (key_hash) DO NOTHING RETURNING " + keyColumns).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return apikeymodel.Key{}, fmt.Errorf("build create api key query: %w", err)
+	}
+
+	created, err := scanKey(r.db.Conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apikeymodel.Key{}, ErrKeyExists
+		}
+		return apikeymodel.Key{}, fmt.Errorf("create api key: %w", err)
+	}
+	return created, nil
+}
+
+// GetActiveByHash finds a key that has not been revoked.
+func (r *APIKeyRepository) GetActiveByHash(ctx context.Context, keyHash string) (apikeymodel.Key, error) {
+	query, args, err := sq.
+		Select(keyColumns).
+		From("api_keys").
+		Where(sq.Eq{"key_hash": keyHash, "revoked_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return apikeymodel.Key{}, fmt.Errorf("build get api key query: %w", err)
+	}
+
+	key, err := scanKey(r.db.Conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apikeymodel.Key{}, ErrKeyNotFound
+		}
+		return apikeymodel.Key{}, fmt.Errorf("get api key: %w", err)
+	}
+	return key, nil
+}
+
+func (r *APIKeyRepository) List(ctx context.Context) ([]apikeymodel.Key, error) {
+	query, args, err := sq.
+		Select(keyColumns).
+		From("api_keys").
+		OrderBy("key_id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build list api keys query: %w", err)
+	}
+
+	rows, err := r.db.Conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list api keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make([]apikeymodel.Key, 0)
+	for rows.Next() {
+		key, err := scanKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan api key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list api keys rows err: %w", err)
+	}
+	return keys, nil
+}
+
+// Revoke marks the key revoked. Revoking a revoked key keeps the original
+// revocation time.
+func (r *APIKeyRepository) Revoke(ctx context.Context, keyID int64) (apikeymodel.Key, error) {
+	query, args, err := sq.
+		Update("api_keys").
+		Set("revoked_at", sq.Expr("COALESCE(revoked_at, ?)", time.Now().UTC())).
+		Where(sq.Eq{"key_id": keyID}).
+		Suffix("RETURNING " + keyColumns).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return apikeymodel.Key{}, fmt.Errorf("build revoke api key query: %w", err)
+	}
+
+	key, err := scanKey(r.db.Conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apikeymodel.Key{}, ErrKeyNotFound
+		}
+		return apikeymodel.Key{}, fmt.Errorf("revoke api key: %w", err)
+	}
+	return key, nil
+}
+
+// RevokeOthers revokes the active keys named name except the one stored
+// under keepHash, and returns the keys it revoked.
+func (r *APIKeyRepository) RevokeOthers(ctx context.Context, name, keepHash string) ([]apikeymodel.Key, error) {
+	query, args, err := sq.
+		Update("api_keys").
+		Set("revoked_at", time.Now().UTC()).
+		Where(sq.Eq{"name": name, "revoked_at": nil}).
+		Where(sq.NotEq{"key_hash": keepHash}).
+		Suffix("RETURNING " + keyColumns).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build revoke api keys query: %w", err)
+	}
+
+	rows, err := r.db.Conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("revoke api keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []apikeymodel.Key
+	for rows.Next() {
+		key, err := scanKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan api key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("revoke api keys rows err: %w", err)
+	}
+	return keys, nil
+}
+
+func scanKey(row pgx.Row) (apikeymodel.Key, error) {
+	var (
+		key  apikeymodel.Key
+		role string
+	)
+	if err := row.Scan(&key.ID, &key.Name, &role, &key.TeamName, &key.CreatedAt, &key.RevokedAt); err != nil {
+		return apikeymodel.Key{}, err
+	}
+	key.Role = core.Role(role)
+	return key, nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"avito-intern-test/internal/core"
+	auditmodel "avito-intern-test/internal/model/audit"
+)
+
+type AuditRepository struct {
+	db *core.TxManager
+}
+
+func NewAuditRepository(pool *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{db: core.NewTxManager(pool)}
+}
+
+// Append writes the entry within the caller's transaction, if any, so an
+// entry exists exactly when the change it describes was committed.
+func (r *AuditRepository) Append(ctx context.Context, entry auditmodel.Entry) error {
+	before, err := marshalState(entry.Before)
+	if err != nil {
+		return fmt.Errorf("encode audit before state: %w", err)
+	}
+	after, err := marshalState(entry.After)
+	if err != nil {
+		return fmt.Errorf("encode audit after state: %w", err)
+	}
+
+	query, args, err := sq.
+		Insert("audit_log").
+		Columns("actor", "action", "entity_type", "entity_id", "before", "after", "created_at").
+		Values(
+			entry.Actor,
+			string(entry.Action),
+			string(entry.EntityType),
+			entry.EntityID,
+			before,
+			after,
+			time.Now().UTC(),
+		).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build append audit entry query: %w", err)
+	}
+
+	if _, err := r.db.Conn(ctx).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("append audit entry: %w", err)
+	}
+	return nil
+}
+
+func (r *AuditRepository) List(ctx context.Context, filter auditmodel.ListFilter) (auditmodel.Page, error) {
+	queryBuilder := sq.
+		Select(
+			"audit_id",
+			"actor",
+			"action",
+			"entity_type",
+			"entity_id",
+			"before",
+			"after",
+			"created_at",
+		).
+		From("audit_log").
+		OrderBy("audit_id DESC").
+		Limit(uint64(filter.Limit + 1)).
+		PlaceholderFormat(sq.Dollar)
+	if filter.Actor != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"actor": filter.Actor})
+	}
+	if filter.Action != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"action": string(filter.Action)})
+	}
+	if filter.EntityType != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"entity_type": string(filter.EntityType)})
+	}
+	if filter.EntityID != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"entity_id": filter.EntityID})
+	}
+	if filter.Since != nil {
+		queryBuilder = queryBuilder.Where(sq.GtOrEq{"created_at": *filter.Since})
+	}
+	if filter.Until != nil {
+		queryBuilder = queryBuilder.Where(sq.Lt{"created_at": *filter.Until})
+	}
+	if filter.After != 0 {
+		queryBuilder = queryBuilder.Where(sq.Lt{"audit_id": filter.After})
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return auditmodel.Page{}, fmt.Errorf("build list audit entries query: %w", err)
+	}
+
+	rows, err := r.db.Conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return auditmodel.Page{}, fmt.Errorf("list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]auditmodel.Entry, 0, filter.Limit+1)
+	for rows.Next() {
+		var (
+			e                  auditmodel.Entry
+			action, entityType string
+			before, after      []byte
+		)
+		if err := rows.Scan(
+			&e.ID,
+			&e.Actor,
+			&action,
+			&entityType,
+			&e.EntityID,
+			&before,
+			&after,
+			&e.CreatedAt,
+		); err != nil {
+			return auditmodel.Page{}, fmt.Errorf("scan audit entry: %w", err)
+		}
+		e.Action = auditmodel.Action(action)
+		e.EntityType = auditmodel.EntityType(entityType)
+		if before != nil {
+			e.Before = json.RawMessage(before)
+		}
+		if after != nil {
+			e.After = json.RawMessage(after)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return auditmodel.Page{}, fmt.Errorf("list audit entries rows err: %w", err)
+	}
+
+	page := auditmodel.Page{Entries: entries}
+	if len(entries) > filter.Limit {
+		page.Entries = entries[:filter.Limit]
+		page.Next = page.Entries[filter.Limit-1].ID
+	}
+	return page, nil
+}
+
+func marshalState(state any) ([]byte, error) {
+	if state == nil {
+		return nil, nil
+	}
+	return json.Marshal(state)
+}
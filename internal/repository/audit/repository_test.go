@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	auditmodel "avito-intern-test/internal/model/audit"
+	"avito-intern-test/internal/repository/testutil"
+)
+
+func TestAuditRepository_AppendAndList(t *testing.T) {
+	pool := testutil.OpenTestPool(t)
+	r := NewAuditRepository(pool)
+	ctx := context.Background()
+	if _, err := pool.Exec(ctx, "TRUNCATE TABLE audit_log"); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	entries := []auditmodel.Entry{
+		{Actor: "alice", Action: auditmodel.ActionUserSetIsActive, EntityType: auditmodel.EntityUser, EntityID: "u1",
+			Before: map[string]bool{"is_active": true}, After: map[string]bool{"is_active": false}},
+		{Actor: "bob", Action: auditmodel.ActionTeamCreate, EntityType: auditmodel.EntityTeam, EntityID: "backend",
+			After: map[string]string{"team_name": "backend"}},
+		{Actor: "alice", Action: auditmodel.ActionUserMoveTeam, EntityType: auditmodel.EntityUser, EntityID: "u1"},
+	}
+	for _, e := range entries {
+		if err := r.Append(ctx, e); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	page, err := r.List(ctx, auditmodel.ListFilter{Actor: "alice", Limit: 1})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(page.Entries) != 1 || page.Entries[0].Action != auditmodel.ActionUserMoveTeam || page.Next == 0 {
+		t.Fatalf("unexpected first page: %+v", page)
+	}
+
+	page, err = r.List(ctx, auditmodel.ListFilter{Actor: "alice", Limit: 1, After: page.Next})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(page.Entries) != 1 || page.Next != 0 {
+		t.Fatalf("unexpected second page: %+v", page)
+	}
+	var before map[string]bool
+	if err := json.Unmarshal(page.Entries[0].Before.(json.RawMessage), &before); err != nil || !before["is_active"] {
+		t.Fatalf("unexpected before state: %v %v", page.Entries[0].Before, err)
+	}
+
+	if _, err := pool.Exec(ctx, "DELETE FROM audit_log"); err == nil {
+		t.Fatalf("audit log must reject deletes")
+	}
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"errors"
+
+	"avito-intern-test/internal/core"
+)
+
+var (
+	ErrRecordNotFound = core.Throw(core.ErrorNotFound, "idempotency key not found")
+	ErrLeaseLost      = errors.New("idempotency key reservation was taken over")
+)
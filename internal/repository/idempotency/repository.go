@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"avito-intern-test/internal/core"
+	idemmodel "avito-intern-test/internal/model/idempotency"
+)
+
+type IdempotencyRepository struct {
+	db *core.TxManager
+}
+
+func NewIdempotencyRepository(pool *pgxpool.Pool) *IdempotencyRepository {
+	return &IdempotencyRepository{db: core.NewTxManager(pool)}
+}
+
+// Reserve inserts an in-progress record and reports whether this call owns
+// the key. An existing record is taken over when it is a reservation older
+// than staleBefore, left behind by a request that never finished, or a
+// response completed before expiredBefore.
+func (r *IdempotencyRepository) Reserve(
+	ctx context.Context,
+	rec idemmodel.Record,
+	staleBefore, expiredBefore time.Time,
+) (bool, error) {
+	query, args, err := sq.
+		Insert("idempotency_keys").
+		Columns("idempotency_key", "route", "request_hash", "lease_token", "created_at").
+		Values(rec.Key, rec.Route, rec.RequestHash, rec.Lease, time.Now().UTC()).
+		Suffix(`ON CONFLICT (idempotency_key, route) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash,
+			lease_token = EXCLUDED.lease_token,
+			created_at = EXCLUDED.created_at,
+			status_code = NULL,
+			content_type = NULL,
+			response_body = NULL,
+			completed_at = NULL
+		WHERE (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at < ?)
+			OR idempotency_keys.completed_at < ?`, staleBefore.UTC(), expiredBefore.UTC()).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("build reserve idempotency key query: %w", err)
+	}
+
+	tag, err := r.db.Conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *IdempotencyRepository) Get(ctx context.Context, key, route string) (idemmodel.Record, error) {
+	query, args, err := sq.
+		Select(
+			"idempotency_key",
+			"route",
+			"request_hash",
+			"COALESCE(status_code, 0)",
+			"COALESCE(content_type, '')",
+			"response_body",
+			"created_at",
+		).
+		From("idempotency_keys").
+		Where(sq.Eq{"idempotency_key": key, "route": route}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return idemmodel.Record{}, fmt.Errorf("build get idempotency key query: %w", err)
+	}
+
+	var rec idemmodel.Record
+	err = r.db.Conn(ctx).QueryRow(ctx, query, args...).Scan(
+		&rec.Key,
+		&rec.Route,
+		&rec.RequestHash,
+		&rec.StatusCode,
+		&rec.ContentType,
+		&rec.Body,
+		&rec.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return idemmodel.Record{}, ErrRecordNotFound
+		}
+		return idemmodel.Record{}, fmt.Errorf("get idempotency key: %w", err)
+	}
+	return rec, nil
+}
+
+// Complete stores the response if rec.Lease still holds the reservation.
+// ErrLeaseLost means the key was taken over in the meantime.
+func (r *IdempotencyRepository) Complete(ctx context.Context, rec idemmodel.Record) error {
+	query, args, err := sq.
+		Update("idempotency_keys").
+		Set("status_code", rec.StatusCode).
+		Set("content_type", rec.ContentType).
+		Set("response_body", rec.Body).
+		Set("completed_at", time.Now().UTC()).
+		Where(sq.Eq{"idempotency_key": rec.Key, "route": rec.Route, "lease_token": rec.Lease}).
+		Where(sq.Eq{"status_code": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build complete idempotency key query: %w", err)
+	}
+
+	tag, err := r.db.Conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Release deletes the reservation held by lease. Completed records and
+// reservations taken over by another request are left alone.
+func (r *IdempotencyRepository) Release(ctx context.Context, key, route, lease string) error {
+	query, args, err := sq.
+		Delete("idempotency_keys").
+		Where(sq.Eq{"idempotency_key": key, "route": route, "lease_token": lease}).
+		Where(sq.Eq{"status_code": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build release idempotency key query: %w", err)
+	}
+
+	if _, err := r.db.Conn(ctx).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired removes records, finished or not, created before the cutoff.
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	query, args, err := sq.
+		Delete("idempotency_keys").
+		Where(sq.Lt{"created_at": before.UTC()}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build delete expired idempotency keys query: %w", err)
+	}
+
+	tag, err := r.db.Conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("delete expired idempotency keys: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
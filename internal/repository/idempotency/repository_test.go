@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	idemmodel "avito-intern-test/internal/model/idempotency"
+	"avito-intern-test/internal/repository/testutil"
+)
+
+func TestIdempotencyRepository_Lifecycle(t *testing.T) {
+	pool := testutil.OpenTestPool(t)
+	r := NewIdempotencyRepository(pool)
+	ctx := context.Background()
+	if _, err := pool.Exec(ctx, "TRUNCATE TABLE idempotency_keys"); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	// Cutoffs far in the past: nothing counts as stale or expired.
+	past := time.Now().Add(-time.Hour)
+	rec := idemmodel.Record{Key: "k1", Route: "POST /team/add", RequestHash: "h1", Lease: "l1"}
+	ok, err := r.Reserve(ctx, rec, past, past)
+	if err != nil || !ok {
+		t.Fatalf("reserve: ok=%v err=%v", ok, err)
+	}
+	if ok, _ := r.Reserve(ctx, rec, past, past); ok {
+		t.Fatalf("second reserve must not own the key")
+	}
+
+	got, err := r.Get(ctx, "k1", "POST /team/add")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Completed() || got.RequestHash != "h1" {
+		t.Fatalf("expected pending record, got %+v", got)
+	}
+
+	rec.StatusCode = 201
+	rec.ContentType = "application/json"
+	rec.Body = []byte(`{"ok":true}`)
+	lost := rec
+	lost.Lease = "other"
+	if err := r.Complete(ctx, lost); !errors.Is(err, ErrLeaseLost) {
+		t.Fatalf("expected ErrLeaseLost, got %v", err)
+	}
+	if err := r.Complete(ctx, rec); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if err := r.Release(ctx, "k1", "POST /team/add", "l1"); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	got, err = r.Get(ctx, "k1", "POST /team/add")
+	if err != nil {
+		t.Fatalf("completed record must survive release: %v", err)
+	}
+	if got.StatusCode != 201 || string(got.Body) != `{"ok":true}` {
+		t.Fatalf("unexpected record: %+v", got)
+	}
+
+	_, _ = r.Reserve(ctx, idemmodel.Record{Key: "k2", Route: "POST /team/add", RequestHash: "h2", Lease: "l2"}, past, past)
+	if err := r.Release(ctx, "k2", "POST /team/add", "l2"); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if _, err := r.Get(ctx, "k2", "POST /team/add"); !errors.Is(err, ErrRecordNotFound) {
+		t.Fatalf("expected ErrRecordNotFound, got %v", err)
+	}
+
+	future := time.Now().Add(time.Hour)
+	if ok, err := r.Reserve(ctx, rec, past, future); err != nil || !ok {
+		t.Fatalf("expired response must be taken over: ok=%v err=%v", ok, err)
+	}
+	if ok, err := r.Reserve(ctx, rec, future, past); err != nil || !ok {
+		t.Fatalf("stale reservation must be taken over: ok=%v err=%v", ok, err)
+	}
+	n, err := r.DeleteExpired(ctx, future)
+	if err != nil || n != 1 {
+		t.Fatalf("delete expired: n=%d err=%v", n, err)
+	}
+}
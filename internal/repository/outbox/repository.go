@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"avito-intern-test/internal/core"
+	eventmodel "avito-intern-test/internal/model/event"
+)
+
+type OutboxRepository struct {
+	db *core.TxManager
+}
+
+func NewOutboxRepository(pool *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{db: core.NewTxManager(pool)}
+}
+
+// Add stores the event. Called inside a transaction it commits or rolls back
+// together with the state change the event describes.
+func (r *OutboxRepository) Add(ctx context.Context, event eventmodel.Event) error {
+	payload, err := json.Marshal(event.Data)
+	if err != nil {
+		return fmt.Errorf("encode outbox payload: %w", err)
+	}
+
+	query, args, err := sq.
+		Insert("outbox_events").
+		Columns("event_id", "event_type", "payload", "occurred_at", "next_attempt_at").
+		Values(event.ID, string(event.Type), payload, event.OccurredAt, event.OccurredAt).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build add outbox event query: %w", err)
+	}
+
+	if _, err := r.db.Conn(ctx).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("add outbox event: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue returns up to limit events due for delivery, those waiting
+// longest first, and hides them from other relays until claimedUntil. An
+// event whose claim runs out before it is marked becomes due again.
+func (r *OutboxRepository) ClaimDue(
+	ctx context.Context,
+	limit int,
+	claimedUntil time.Time,
+) ([]eventmodel.Pending, error) {
+	due, dueArgs, err := sq.
+		Select("event_id").
+		From("outbox_events").
+		Where(sq.Eq{"published_at": nil, "dead_at": nil}).
+		Where(sq.LtOrEq{"next_attempt_at": time.Now().UTC()}).
+		OrderBy("next_attempt_at", "event_id").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build due outbox events query: %w", err)
+	}
+
+	query, args, err := sq.
+		Update("outbox_events").
+		Set("next_attempt_at", claimedUntil.UTC()).
+		Where("event_id IN ("+due+")", dueArgs...).
+		Suffix("RETURNING event_id, event_type, payload, occurred_at, attempts").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build claim outbox events query: %w", err)
+	}
+
+	rows, err := r.db.Conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("claim outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]eventmodel.Pending, 0)
+	for rows.Next() {
+		var (
+			event     eventmodel.Pending
+			eventType string
+			payload   []byte
+		)
+		if err := rows.Scan(&event.ID, &eventType, &payload, &event.OccurredAt, &event.Attempts); err != nil {
+			return nil, fmt.Errorf("scan outbox event: %w", err)
+		}
+		event.Type = eventmodel.Type(eventType)
+		event.Data = json.RawMessage(payload)
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate outbox events: %w", err)
+	}
+
+	// RETURNING does not keep the order of the subquery.
+	slices.SortFunc(events, func(a, b eventmodel.Pending) int {
+		return cmp.Or(a.OccurredAt.Compare(b.OccurredAt), cmp.Compare(a.ID, b.ID))
+	})
+	return events, nil
+}
+
+func (r *OutboxRepository) MarkPublished(ctx context.Context, eventID string) error {
+	query, args, err := sq.
+		Update("outbox_events").
+		Set("published_at", time.Now().UTC()).
+		Set("attempts", sq.Expr("attempts + 1")).
+		Set("last_error", nil).
+		Where(sq.Eq{"event_id": eventID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build mark outbox event published query: %w", err)
+	}
+
+	if _, err := r.db.Conn(ctx).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("mark outbox event published: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed delivery and makes the event due again at
+// retryAt.
+func (r *OutboxRepository) MarkFailed(ctx context.Context, eventID, reason string, retryAt time.Time) error {
+	query, args, err := sq.
+		Update("outbox_events").
+		Set("attempts", sq.Expr("attempts + 1")).
+		Set("last_error", reason).
+		Set("next_attempt_at", retryAt.UTC()).
+		Where(sq.Eq{"event_id": eventID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build mark outbox event failed query: %w", err)
+	}
+
+	if _, err := r.db.Conn(ctx).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("mark outbox event failed: %w", err)
+	}
+	return nil
+}
+
+// MarkDead records the last failed delivery of an event the relay gives up
+// on. The event stays in the table for inspection but is never due again.
+func (r *OutboxRepository) MarkDead(ctx context.Context, eventID, reason string) error {
+	query, args, err := sq.
+		Update("outbox_events").
+		Set("attempts", sq.Expr("attempts + 1")).
+		Set("last_error", reason).
+		Set("dead_at", time.Now().UTC()).
+		Where(sq.Eq{"event_id": eventID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build mark outbox event dead query: %w", err)
+	}
+
+	if _, err := r.db.Conn(ctx).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("mark outbox event dead: %w", err)
+	}
+	return nil
+}
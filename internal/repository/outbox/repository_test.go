@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"avito-intern-test/internal/core"
+	eventmodel "avito-intern-test/internal/model/event"
+	"avito-intern-test/internal/repository/testutil"
+)
+
+func TestOutboxRepository_Lifecycle(t *testing.T) {
+	pool := testutil.OpenTestPool(t)
+	r := NewOutboxRepository(pool)
+	ctx := context.Background()
+	if _, err := pool.Exec(ctx, "TRUNCATE TABLE outbox_events"); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	first := eventmodel.New(eventmodel.TypeTeamCreated, eventmodel.TeamPayload{TeamName: "backend"})
+	second := eventmodel.New(eventmodel.TypeTeamMemberAdded, eventmodel.TeamMemberPayload{TeamName: "backend", UserID: "u1"})
+	for _, e := range []eventmodel.Event{first, second} {
+		if err := r.Add(ctx, e); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+	}
+
+	claimed := time.Now().Add(time.Minute)
+	events, err := r.ClaimDue(ctx, 10, claimed)
+	if err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	if len(events) != 2 || events[0].ID != first.ID || events[0].Attempts != 0 {
+		t.Fatalf("unexpected due events: %+v", events)
+	}
+	var payload eventmodel.TeamPayload
+	if err := json.Unmarshal(events[0].Data.(json.RawMessage), &payload); err != nil || payload.TeamName != "backend" {
+		t.Fatalf("unexpected payload: %s %v", events[0].Data, err)
+	}
+	if again, _ := r.ClaimDue(ctx, 10, claimed); len(again) != 0 {
+		t.Fatalf("claimed events must not be handed out twice: %+v", again)
+	}
+
+	if err := r.MarkPublished(ctx, first.ID); err != nil {
+		t.Fatalf("mark published: %v", err)
+	}
+	if err := r.MarkFailed(ctx, second.ID, "receiver down", time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("mark failed: %v", err)
+	}
+	events, err = r.ClaimDue(ctx, 10, claimed)
+	if err != nil || len(events) != 1 || events[0].ID != second.ID || events[0].Attempts != 1 {
+		t.Fatalf("expected only the failed event due: %+v %v", events, err)
+	}
+
+	if err := r.MarkDead(ctx, second.ID, "receiver down"); err != nil {
+		t.Fatalf("mark dead: %v", err)
+	}
+	if _, err := pool.Exec(ctx, "UPDATE outbox_events SET next_attempt_at = NOW() - INTERVAL '1 hour'"); err != nil {
+		t.Fatalf("expire claims: %v", err)
+	}
+	if events, _ := r.ClaimDue(ctx, 10, claimed); len(events) != 0 {
+		t.Fatalf("dead events must not be due: %+v", events)
+	}
+}
+
+func TestOutboxRepository_RollbackDropsEvent(t *testing.T) {
+	pool := testutil.OpenTestPool(t)
+	r := NewOutboxRepository(pool)
+	ctx := context.Background()
+	if _, err := pool.Exec(ctx, "TRUNCATE TABLE outbox_events"); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	boom := errors.New("boom")
+	err := core.NewTxManager(pool).WithinTx(ctx, func(ctx context.Context) error {
+		if err := r.Add(ctx, eventmodel.New(eventmodel.TypeTeamCreated, eventmodel.TeamPayload{TeamName: "x"})); err != nil {
+			return err
+		}
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected boom, got %v", err)
+	}
+	if events, _ := r.ClaimDue(ctx, 10, time.Now()); len(events) != 0 {
+		t.Fatalf("rolled back event must not be relayed: %+v", events)
+	}
+}
@@ -1,7 +1,8 @@
 package repository
 
-import "errors"
+import "avito-intern-test/internal/core"
 
 var (
-	ErrPullRequestNotFound = errors.New("pr not found")
+	ErrPullRequestNotFound = core.Throw(core.ErrorNotFound, "pr not found")
+	ErrReviewerNotAssigned = core.Throw(core.ErrorNotAssigned, "reviewer is not assigned to this PR")
 )
@@ -4,21 +4,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"avito-intern-test/internal/core"
 	prmodel "avito-intern-test/internal/model/pullrequest"
 )
 
 type PullRequestRepository struct {
-	pool *pgxpool.Pool
+	db *core.TxManager
 }
 
 func NewPullRequestRepository(pool *pgxpool.Pool) *PullRequestRepository {
-	return &PullRequestRepository{pool: pool}
+	return &PullRequestRepository{db: core.NewTxManager(pool)}
 }
 
 func (r *PullRequestRepository) Exists(ctx context.Context, prID string) (bool, error) {
@@ -34,7 +36,7 @@ func (r *PullRequestRepository) Exists(ctx context.Context, prID string) (bool,
 	}
 
 	var flag int
-	err = r.pool.QueryRow(ctx, query, args...).Scan(&flag)
+	err = r.db.Conn(ctx).QueryRow(ctx, query, args...).Scan(&flag)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return false, nil
@@ -48,12 +50,6 @@ func (r *PullRequestRepository) Create(
 	ctx context.Context,
 	pr prmodel.PullRequest,
 ) error {
-	tx, err := r.pool.Begin(ctx)
-	if err != nil {
-		return fmt.Errorf("begin tx: %w", err)
-	}
-	defer func() { _ = tx.Rollback(ctx) }()
-
 	createdAt := time.Now().UTC()
 
 	queryBuilder := sq.
@@ -67,11 +63,12 @@ func (r *PullRequestRepository) Create(
 		return fmt.Errorf("build insert PR query: %w", err)
 	}
 
-	if _, err := tx.Exec(ctx, query, args...); err != nil {
-		return fmt.Errorf("insert pull_request: %w", err)
-	}
+	return r.db.WithinTx(ctx, func(ctx context.Context) error {
+		q := r.db.Conn(ctx)
+		if _, err := q.Exec(ctx, query, args...); err != nil {
+			return fmt.Errorf("insert pull_request: %w", err)
+		}
 
-	if len(pr.AssignedReviewers) > 0 {
 		for _, id := range pr.AssignedReviewers {
 			queryBuilder := sq.
 				Insert("pr_reviewers").
@@ -84,15 +81,12 @@ func (r *PullRequestRepository) Create(
 				return fmt.Errorf("build insert pr_reviewer query: %w", err)
 			}
 
-			if _, err := tx.Exec(ctx, query, args...); err != nil {
+			if _, err := q.Exec(ctx, query, args...); err != nil {
 				return fmt.Errorf("insert pr_reviewer: %w", err)
 			}
 		}
-	}
-	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("commit tx: %w", err)
-	}
-	return nil
+		return nil
+	})
 }
 
 func (r *PullRequestRepository) GetByID(
@@ -114,7 +108,7 @@ func (r *PullRequestRepository) GetByID(
 	var status string
 	var createdAt time.Time
 
-	err = r.pool.QueryRow(ctx, query, args...).Scan(
+	err = r.db.Conn(ctx).QueryRow(ctx, query, args...).Scan(
 		&pr.PullRequestID,
 		&pr.PullRequestName,
 		&pr.AuthorID,
@@ -124,7 +118,7 @@ func (r *PullRequestRepository) GetByID(
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return prmodel.PullRequest{}, fmt.Errorf("PR not found: %w", err)
+			return prmodel.PullRequest{}, ErrPullRequestNotFound
 		}
 		return prmodel.PullRequest{}, fmt.Errorf("get PR by id: %w", err)
 	}
@@ -135,6 +129,7 @@ func (r *PullRequestRepository) GetByID(
 		Select("user_id").
 		From("pr_reviewers").
 		Where(sq.Eq{"pull_request_id": prID}).
+		Where(sq.Eq{"replaced_at": nil}).
 		OrderBy("user_id").
 		PlaceholderFormat(sq.Dollar)
 
@@ -143,7 +138,7 @@ func (r *PullRequestRepository) GetByID(
 		return prmodel.PullRequest{}, fmt.Errorf("build get reviewers query: %w", err)
 	}
 
-	rows, err := r.pool.Query(ctx, queryReviewers, argsReviewers...)
+	rows, err := r.db.Conn(ctx).Query(ctx, queryReviewers, argsReviewers...)
 	if err != nil {
 		return prmodel.PullRequest{}, fmt.Errorf("get PR reviewers: %w", err)
 	}
@@ -166,116 +161,340 @@ func (r *PullRequestRepository) GetByID(
 	return pr, nil
 }
 
-func (r *PullRequestRepository) GetMany(
+func (r *PullRequestRepository) Update(
 	ctx context.Context,
-	prIDs []string,
-) ([]prmodel.PullRequest, error) {
+	pr prmodel.PullRequest,
+) error {
+	createdAt := time.Now().UTC()
+	if !pr.CreatedAt.IsZero() {
+		createdAt = pr.CreatedAt
+	}
+
+	queryBuilderUpdate := sq.
+		Update("pull_requests").
+		Set("pull_request_name", pr.PullRequestName).
+		Set("author_id", pr.AuthorID).
+		Set("status", string(pr.Status)).
+		Set("created_at", createdAt).
+		Set("merged_at", pr.MergedAt).
+		Where(sq.Eq{"pull_request_id": pr.PullRequestID}).
+		PlaceholderFormat(sq.Dollar)
+
+	queryUpdate, argsUpdate, err := queryBuilderUpdate.ToSql()
+	if err != nil {
+		return fmt.Errorf("build update PR query: %w", err)
+	}
+
+	return r.db.WithinTx(ctx, func(ctx context.Context) error {
+		q := r.db.Conn(ctx)
+		if _, err := q.Exec(ctx, queryUpdate, argsUpdate...); err != nil {
+			return fmt.Errorf("update pull_request: %w", err)
+		}
+		return syncReviewers(ctx, q, pr.PullRequestID, pr.AssignedReviewers, nil)
+	})
+}
+
+func (r *PullRequestRepository) ReplaceReviewer(
+	ctx context.Context,
+	pr prmodel.PullRequest,
+	oldUserID string,
+	newUserID string,
+) error {
+	replacedBy := map[string]string{oldUserID: newUserID}
+	return r.db.WithinTx(ctx, func(ctx context.Context) error {
+		return syncReviewers(ctx, r.db.Conn(ctx), pr.PullRequestID, pr.AssignedReviewers, replacedBy)
+	})
+}
+
+func (r *PullRequestRepository) History(
+	ctx context.Context,
+	prID string,
+) ([]prmodel.ReviewerAssignment, error) {
 	queryBuilder := sq.
-		Select("pull_request_id, pull_request_name, author_id, status").
-		From("pull_requests").
-		Where(sq.Eq{"pull_request_id": prIDs}).
+		Select("user_id", "assigned_at", "replaced_at", "replaced_by", "verdict", "reviewed_at").
+		From("pr_reviewers").
+		Where(sq.Eq{"pull_request_id": prID}).
+		OrderBy("assigned_at", "replaced_at NULLS LAST", "user_id").
 		PlaceholderFormat(sq.Dollar)
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("build reviewer history query: %w", err)
 	}
 
-	rows, err := r.pool.Query(ctx, query, args...)
+	rows, err := r.db.Conn(ctx).Query(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("get reviewer history: %w", err)
 	}
 	defer rows.Close()
 
-	var prs []prmodel.PullRequest
+	var history []prmodel.ReviewerAssignment
 	for rows.Next() {
-		var pr prmodel.PullRequest
-		err = rows.Scan(
-			&pr.PullRequestID,
-			&pr.PullRequestName,
-			&pr.AuthorID,
-			&pr.Status,
-		)
-		if err != nil {
-			return nil, err
+		var a prmodel.ReviewerAssignment
+		if err := rows.Scan(
+			&a.UserID,
+			&a.AssignedAt,
+			&a.ReplacedAt,
+			&a.ReplacedBy,
+			&a.Verdict,
+			&a.ReviewedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan reviewer assignment: %w", err)
 		}
-		prs = append(prs, pr)
+		history = append(history, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reviewer history rows err: %w", err)
 	}
 
-	return prs, nil
+	return history, nil
 }
 
-func (r *PullRequestRepository) Update(
+func (r *PullRequestRepository) SetVerdict(
 	ctx context.Context,
-	pr prmodel.PullRequest,
+	prID string,
+	userID string,
+	verdict prmodel.ReviewVerdict,
 ) error {
-	tx, err := r.pool.Begin(ctx)
+	queryBuilder := sq.
+		Update("pr_reviewers").
+		Set("verdict", string(verdict)).
+		Set("reviewed_at", time.Now().UTC()).
+		Where(sq.Eq{"pull_request_id": prID, "user_id": userID}).
+		Where(sq.Eq{"replaced_at": nil}).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := queryBuilder.ToSql()
 	if err != nil {
-		return fmt.Errorf("begin tx: %w", err)
+		return fmt.Errorf("build set verdict query: %w", err)
 	}
-	defer func() { _ = tx.Rollback(ctx) }()
 
-	createdAt := time.Now().UTC()
-	if !pr.CreatedAt.IsZero() {
-		createdAt = pr.CreatedAt
+	tag, err := r.db.Conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("set verdict: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrReviewerNotAssigned
 	}
+	return nil
+}
 
-	queryBuilderUpdate := sq.
-		Update("pull_requests").
-		Set("pull_request_name", pr.PullRequestName).
-		Set("author_id", pr.AuthorID).
-		Set("status", string(pr.Status)).
-		Set("created_at", createdAt).
-		Set("merged_at", pr.MergedAt).
-		Where(sq.Eq{"pull_request_id": pr.PullRequestID}).
+func (r *PullRequestRepository) CountApprovals(ctx context.Context, prID string) (int, error) {
+	queryBuilder := sq.
+		Select("COUNT(*)").
+		From("pr_reviewers").
+		Where(sq.Eq{"pull_request_id": prID}).
+		Where(sq.Eq{"replaced_at": nil}).
+		Where(sq.Eq{"verdict": string(prmodel.ReviewVerdictApproved)}).
 		PlaceholderFormat(sq.Dollar)
 
-	queryUpdate, argsUpdate, err := queryBuilderUpdate.ToSql()
+	query, args, err := queryBuilder.ToSql()
 	if err != nil {
-		return fmt.Errorf("build update PR query: %w", err)
+		return 0, fmt.Errorf("build count approvals query: %w", err)
 	}
 
-	if _, err := tx.Exec(ctx, queryUpdate, argsUpdate...); err != nil {
-		return fmt.Errorf("update pull_request: %w", err)
+	var count int
+	if err := r.db.Conn(ctx).QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count approvals: %w", err)
 	}
+	return count, nil
+}
 
-	queryBuilderDelete := sq.
-		Delete("pr_reviewers").
-		Where(sq.Eq{"pull_request_id": pr.PullRequestID}).
-		Suffix("RETURNING user_id").
-		PlaceholderFormat(sq.Dollar)
+// syncReviewers closes the assignments of reviewers that are no longer in
+// the list and opens new ones, so that pr_reviewers keeps the full history.
+func syncReviewers(
+	ctx context.Context,
+	q core.Querier,
+	prID string,
+	reviewers []string,
+	replacedBy map[string]string,
+) error {
+	querySelect, argsSelect, err := sq.
+		Select("user_id").
+		From("pr_reviewers").
+		Where(sq.Eq{"pull_request_id": prID}).
+		Where(sq.Eq{"replaced_at": nil}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build select reviewers query: %w", err)
+	}
 
-	queryDelete, argsDelete, err := queryBuilderDelete.ToSql()
+	rows, err := q.Query(ctx, querySelect, argsSelect...)
 	if err != nil {
-		return fmt.Errorf("build delete reviewers query: %w", err)
+		return fmt.Errorf("select pr_reviewers: %w", err)
+	}
+	current := map[string]struct{}{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan reviewer: %w", err)
+		}
+		current[id] = struct{}{}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("reviewers rows err: %w", err)
 	}
 
-	if _, err := tx.Exec(ctx, queryDelete, argsDelete...); err != nil {
-		return fmt.Errorf("delete pr_reviewers: %w", err)
+	wanted := make(map[string]struct{}, len(reviewers))
+	for _, id := range reviewers {
+		wanted[id] = struct{}{}
 	}
 
-	for _, uid := range pr.AssignedReviewers {
-		queryBuilderInsert := sq.
-			Insert("pr_reviewers").
-			Columns("pull_request_id", "user_id").
-			Values(pr.PullRequestID, uid).
-			PlaceholderFormat(sq.Dollar)
+	now := time.Now().UTC()
+	for id := range current {
+		if _, ok := wanted[id]; ok {
+			continue
+		}
 
-		queryInsert, argsInsert, err := queryBuilderInsert.ToSql()
+		var newID *string
+		if by, ok := replacedBy[id]; ok {
+			newID = &by
+		}
+
+		queryReplace, argsReplace, err := sq.
+			Update("pr_reviewers").
+			Set("replaced_at", now).
+			Set("replaced_by", newID).
+			Where(sq.Eq{"pull_request_id": prID, "user_id": id}).
+			Where(sq.Eq{"replaced_at": nil}).
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("build replace reviewer query: %w", err)
+		}
+
+		if _, err := q.Exec(ctx, queryReplace, argsReplace...); err != nil {
+			return fmt.Errorf("replace pr_reviewer: %w", err)
+		}
+	}
+
+	for _, id := range reviewers {
+		if _, ok := current[id]; ok {
+			continue
+		}
+
+		queryInsert, argsInsert, err := sq.
+			Insert("pr_reviewers").
+			Columns("pull_request_id", "user_id", "assigned_at").
+			Values(prID, id, now).
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
 		if err != nil {
 			return fmt.Errorf("build insert reviewer query: %w", err)
 		}
 
-		if _, err := tx.Exec(ctx, queryInsert, argsInsert...); err != nil {
+		if _, err := q.Exec(ctx, queryInsert, argsInsert...); err != nil {
 			return fmt.Errorf("insert pr_reviewer: %w", err)
 		}
 	}
 
-	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("commit tx: %w", err)
+	return nil
+}
+
+func (r *PullRequestRepository) OpenReviewCounts(
+	ctx context.Context,
+	userIDs []string,
+) (map[string]int, error) {
+	queryBuilder := sq.
+		Select("r.user_id", "COUNT(*)").
+		From("pr_reviewers r").
+		Join("pull_requests p ON p.pull_request_id = r.pull_request_id").
+		Where(sq.Eq{"r.user_id": userIDs}).
+		Where(sq.Eq{"r.replaced_at": nil}).
+		Where(sq.Eq{"p.status": string(prmodel.PullRequestStatusOpen)}).
+		GroupBy("r.user_id").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build open review counts query: %w", err)
+	}
+
+	rows, err := r.db.Conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("open review counts: %w", err)
 	}
+	defer rows.Close()
 
-	return nil
+	counts := make(map[string]int, len(userIDs))
+	for rows.Next() {
+		var userID string
+		var count int
+		if err := rows.Scan(&userID, &count); err != nil {
+			return nil, fmt.Errorf("scan open review count: %w", err)
+		}
+		counts[userID] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("open review counts rows err: %w", err)
+	}
+
+	return counts, nil
+}
+
+// OpenReviewsOf loads every open PR where any of the users is an active
+// reviewer, together with all of its active reviewers, in one query.
+func (r *PullRequestRepository) OpenReviewsOf(
+	ctx context.Context,
+	userIDs []string,
+) ([]prmodel.PullRequest, error) {
+	queryBuilder := sq.
+		Select(
+			"p.pull_request_id",
+			"p.pull_request_name",
+			"p.author_id",
+			"p.status",
+			"p.created_at",
+			"p.merged_at",
+			"array_agg(r.user_id ORDER BY r.user_id)",
+		).
+		From("pull_requests p").
+		Join("pr_reviewers r ON r.pull_request_id = p.pull_request_id AND r.replaced_at IS NULL").
+		Where(sq.Eq{"p.status": string(prmodel.PullRequestStatusOpen)}).
+		Where(sq.Expr(
+			"p.pull_request_id IN (SELECT pull_request_id FROM pr_reviewers WHERE user_id = ANY(?) AND replaced_at IS NULL)",
+			userIDs,
+		)).
+		GroupBy("p.pull_request_id").
+		OrderBy("p.pull_request_id").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build open reviews query: %w", err)
+	}
+
+	rows, err := r.db.Conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get open reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var prs []prmodel.PullRequest
+	for rows.Next() {
+		var pr prmodel.PullRequest
+		if err := rows.Scan(
+			&pr.PullRequestID,
+			&pr.PullRequestName,
+			&pr.AuthorID,
+			&pr.Status,
+			&pr.CreatedAt,
+			&pr.MergedAt,
+			&pr.AssignedReviewers,
+		); err != nil {
+			return nil, fmt.Errorf("scan open review: %w", err)
+		}
+		prs = append(prs, pr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("open reviews rows err: %w", err)
+	}
+
+	return prs, nil
 }
 
 func (r *PullRequestRepository) ReviewerPRs(ctx context.Context, userID string) ([]prmodel.PullRequestShort, error) {
@@ -289,6 +508,8 @@ func (r *PullRequestRepository) ReviewerPRs(ctx context.Context, userID string)
 		From("pull_requests p").
 		Join("pr_reviewers r ON r.pull_request_id = p.pull_request_id").
 		Where(sq.Eq{"r.user_id": userID}).
+		Where(sq.Eq{"r.replaced_at": nil}).
+		Where(sq.NotEq{"p.status": string(prmodel.PullRequestStatusClosed)}).
 		OrderBy("p.pull_request_id").
 		PlaceholderFormat(sq.Dollar)
 
@@ -297,7 +518,7 @@ func (r *PullRequestRepository) ReviewerPRs(ctx context.Context, userID string)
 		return nil, fmt.Errorf("build list PRs by reviewer query: %w", err)
 	}
 
-	rows, err := r.pool.Query(ctx, query, args...)
+	rows, err := r.db.Conn(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("list PRs by reviewer: %w", err)
 	}
@@ -325,3 +546,261 @@ func (r *PullRequestRepository) ReviewerPRs(ctx context.Context, userID string)
 
 	return result, nil
 }
+
+// ReviewerPage returns one keyset page of the reviewer's PRs. It fetches a
+// single extra row to tell whether another page follows.
+func (r *PullRequestRepository) ReviewerPage(
+	ctx context.Context,
+	filter prmodel.ReviewFilter,
+) (prmodel.ReviewPage, error) {
+	queryBuilder := sq.
+		Select(
+			"p.pull_request_id",
+			"p.pull_request_name",
+			"p.author_id",
+			"p.status",
+			"p.created_at",
+			"p.merged_at",
+			activeReviewersColumn,
+		).
+		From("pull_requests p").
+		Join("pr_reviewers r ON r.pull_request_id = p.pull_request_id AND r.replaced_at IS NULL").
+		Where(sq.Eq{"r.user_id": filter.ReviewerID}).
+		OrderBy("p.created_at DESC", "p.pull_request_id DESC").
+		Limit(uint64(filter.Limit + 1)).
+		PlaceholderFormat(sq.Dollar)
+
+	if filter.Status != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"p.status": string(filter.Status)})
+	} else {
+		queryBuilder = queryBuilder.Where(sq.NotEq{"p.status": string(prmodel.PullRequestStatusClosed)})
+	}
+	if filter.CreatedAfter != nil {
+		queryBuilder = queryBuilder.Where(sq.GtOrEq{"p.created_at": *filter.CreatedAfter})
+	}
+	if filter.CreatedBefore != nil {
+		queryBuilder = queryBuilder.Where(sq.Lt{"p.created_at": *filter.CreatedBefore})
+	}
+	if filter.After != nil {
+		queryBuilder = queryBuilder.Where(
+			"(p.created_at, p.pull_request_id) < (?, ?)",
+			filter.After.CreatedAt, filter.After.PullRequestID,
+		)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return prmodel.ReviewPage{}, fmt.Errorf("build reviewer page query: %w", err)
+	}
+
+	prs, err := r.queryWithReviewers(ctx, query, args)
+	if err != nil {
+		return prmodel.ReviewPage{}, fmt.Errorf("get reviewer page: %w", err)
+	}
+
+	page := prmodel.ReviewPage{PullRequests: prs}
+	if len(prs) > filter.Limit {
+		page.PullRequests = prs[:filter.Limit]
+		last := page.PullRequests[filter.Limit-1]
+		page.Next = &prmodel.ReviewCursor{CreatedAt: last.CreatedAt, PullRequestID: last.PullRequestID}
+	}
+	return page, nil
+}
+
+// List runs a dynamic search over all PRs with keyset pagination on the
+// requested ordering.
+func (r *PullRequestRepository) List(
+	ctx context.Context,
+	filter prmodel.ListFilter,
+) (prmodel.ListPage, error) {
+	sortColumn, desc := "p.created_at", true
+	switch filter.Sort {
+	case prmodel.ListSortCreatedAsc:
+		desc = false
+	case prmodel.ListSortNameAsc:
+		sortColumn, desc = "p.pull_request_name", false
+	case prmodel.ListSortNameDesc:
+		sortColumn = "p.pull_request_name"
+	}
+	direction, cmp := "ASC", ">"
+	if desc {
+		direction, cmp = "DESC", "<"
+	}
+
+	queryBuilder := sq.
+		Select(
+			"p.pull_request_id",
+			"p.pull_request_name",
+			"p.author_id",
+			"p.status",
+			"p.created_at",
+			"p.merged_at",
+			activeReviewersColumn,
+		).
+		From("pull_requests p").
+		OrderBy(sortColumn+" "+direction, "p.pull_request_id "+direction).
+		Limit(uint64(filter.Limit + 1)).
+		PlaceholderFormat(sq.Dollar)
+
+	if filter.AuthorID != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"p.author_id": filter.AuthorID})
+	}
+	if filter.TeamName != "" {
+		queryBuilder = queryBuilder.
+			Join("users u ON u.user_id = p.author_id").
+			Where(sq.Eq{"u.team_name": filter.TeamName})
+	}
+	if filter.Status != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"p.status": string(filter.Status)})
+	}
+	if filter.ReviewerID != "" {
+		queryBuilder = queryBuilder.Where(sq.Expr(
+			"EXISTS (SELECT 1 FROM pr_reviewers r WHERE r.pull_request_id = p.pull_request_id AND r.user_id = ? AND r.replaced_at IS NULL)",
+			filter.ReviewerID,
+		))
+	}
+	if filter.NameContains != "" {
+		queryBuilder = queryBuilder.Where(sq.ILike{"p.pull_request_name": "%" + escapeLike(filter.NameContains) + "%"})
+	}
+	if filter.CreatedAfter != nil {
+		queryBuilder = queryBuilder.Where(sq.GtOrEq{"p.created_at": *filter.CreatedAfter})
+	}
+	if filter.CreatedBefore != nil {
+		queryBuilder = queryBuilder.Where(sq.Lt{"p.created_at": *filter.CreatedBefore})
+	}
+	if filter.After != nil {
+		var key any = filter.After.Key
+		if sortColumn == "p.created_at" {
+			createdAt, err := filter.After.CreatedAt()
+			if err != nil {
+				return prmodel.ListPage{}, err
+			}
+			key = createdAt
+		}
+		queryBuilder = queryBuilder.Where(
+			"("+sortColumn+", p.pull_request_id) "+cmp+" (?, ?)",
+			key, filter.After.PullRequestID,
+		)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return prmodel.ListPage{}, fmt.Errorf("build list PRs query: %w", err)
+	}
+
+	prs, err := r.queryWithReviewers(ctx, query, args)
+	if err != nil {
+		return prmodel.ListPage{}, fmt.Errorf("list PRs: %w", err)
+	}
+
+	page := prmodel.ListPage{PullRequests: prs}
+	if len(prs) > filter.Limit {
+		page.PullRequests = prs[:filter.Limit]
+		last := page.PullRequests[filter.Limit-1]
+		next := prmodel.ListCursor{Sort: filter.Sort, Key: last.PullRequestName, PullRequestID: last.PullRequestID}
+		if sortColumn == "p.created_at" {
+			next.Key = last.CreatedAt.UTC().Format(time.RFC3339Nano)
+		}
+		page.Next = &next
+	}
+	return page, nil
+}
+
+const activeReviewersColumn = "ARRAY(SELECT a.user_id FROM pr_reviewers a " +
+	"WHERE a.pull_request_id = p.pull_request_id AND a.replaced_at IS NULL ORDER BY a.user_id)"
+
+// queryWithReviewers scans PR rows selected together with activeReviewersColumn.
+func (r *PullRequestRepository) queryWithReviewers(
+	ctx context.Context,
+	query string,
+	args []any,
+) ([]prmodel.PullRequest, error) {
+	rows, err := r.db.Conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prs []prmodel.PullRequest
+	for rows.Next() {
+		var pr prmodel.PullRequest
+		if err := rows.Scan(
+			&pr.PullRequestID,
+			&pr.PullRequestName,
+			&pr.AuthorID,
+			&pr.Status,
+			&pr.CreatedAt,
+			&pr.MergedAt,
+			&pr.AssignedReviewers,
+		); err != nil {
+			return nil, fmt.Errorf("scan PR: %w", err)
+		}
+		prs = append(prs, pr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("PR rows err: %w", err)
+	}
+	return prs, nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// ApplyReviewerMoves closes and opens all assignments with two statements
+// regardless of the number of moves. Callers run it inside a transaction.
+func ApplyReviewerMoves(ctx context.Context, q core.Querier, moves []prmodel.ReviewerMove) error {
+	if len(moves) == 0 {
+		return nil
+	}
+
+	prIDs := make([]string, 0, len(moves))
+	fromIDs := make([]string, 0, len(moves))
+	toIDs := make([]string, 0, len(moves))
+	for _, m := range moves {
+		prIDs = append(prIDs, m.PullRequestID)
+		fromIDs = append(fromIDs, m.FromUserID)
+		toIDs = append(toIDs, m.ToUserID)
+	}
+	now := time.Now().UTC()
+
+	queryReplace, argsReplace, err := sq.
+		Update("pr_reviewers r").
+		Set("replaced_at", now).
+		Set("replaced_by", sq.Expr("m.to_user")).
+		From("unnest(?::text[], ?::text[], ?::text[]) AS m(pr_id, from_user, to_user)").
+		Where("r.pull_request_id = m.pr_id AND r.user_id = m.from_user AND r.replaced_at IS NULL").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build replace reviewers query: %w", err)
+	}
+	argsReplace = append(argsReplace, prIDs, fromIDs, toIDs)
+
+	tag, err := q.Exec(ctx, queryReplace, argsReplace...)
+	if err != nil {
+		return fmt.Errorf("replace pr_reviewers: %w", err)
+	}
+	if tag.RowsAffected() != int64(len(moves)) {
+		return ErrReviewerNotAssigned
+	}
+
+	queryInsert, argsInsert, err := sq.
+		Insert("pr_reviewers").
+		Columns("pull_request_id", "user_id", "assigned_at").
+		Select(sq.
+			Select("m.pr_id", "m.to_user", "?::timestamp").
+			From("unnest(?::text[], ?::text[]) AS m(pr_id, to_user)")).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert reviewers query: %w", err)
+	}
+	argsInsert = append(argsInsert, now, prIDs, toIDs)
+
+	if _, err := q.Exec(ctx, queryInsert, argsInsert...); err != nil {
+		return fmt.Errorf("insert pr_reviewers: %w", err)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -66,3 +67,254 @@ func TestPullRequestRepository_Lifecycle(t *testing.T) {
 		t.Fatalf("expected pr-1 in list, got %+v", list)
 	}
 }
+
+func TestPullRequestRepository_OpenReviewCounts(t *testing.T) {
+	pool := testutil.OpenTestPool(t)
+	testutil.TruncateAll(t, pool)
+	r := NewPullRequestRepository(pool)
+	ctx := context.Background()
+
+	testutil.EnsureUser(t, pool, "a1", "auth", "t1", true)
+	testutil.EnsureUser(t, pool, "r1", "rev1", "t1", true)
+	testutil.EnsureUser(t, pool, "r2", "rev2", "t1", true)
+
+	now := time.Now().UTC()
+	testutil.InsertPR(t, pool, prmodel.PullRequest{
+		PullRequestID: "pr-1", PullRequestName: "a", AuthorID: "a1",
+		Status: prmodel.PullRequestStatusOpen, AssignedReviewers: []string{"r1", "r2"}, CreatedAt: now,
+	})
+	testutil.InsertPR(t, pool, prmodel.PullRequest{
+		PullRequestID: "pr-2", PullRequestName: "b", AuthorID: "a1",
+		Status: prmodel.PullRequestStatusOpen, AssignedReviewers: []string{"r1"}, CreatedAt: now,
+	})
+	testutil.InsertPR(t, pool, prmodel.PullRequest{
+		PullRequestID: "pr-3", PullRequestName: "c", AuthorID: "a1",
+		Status: prmodel.PullRequestStatusMerged, AssignedReviewers: []string{"r2"}, CreatedAt: now, MergedAt: &now,
+	})
+
+	counts, err := r.OpenReviewCounts(ctx, []string{"r1", "r2", "a1"})
+	if err != nil {
+		t.Fatalf("open review counts: %v", err)
+	}
+	if counts["r1"] != 2 || counts["r2"] != 1 || counts["a1"] != 0 {
+		t.Fatalf("unexpected counts: %+v", counts)
+	}
+}
+
+func TestPullRequestRepository_ReplaceReviewer_KeepsHistory(t *testing.T) {
+	pool := testutil.OpenTestPool(t)
+	testutil.TruncateAll(t, pool)
+	r := NewPullRequestRepository(pool)
+	ctx := context.Background()
+
+	testutil.EnsureUser(t, pool, "a1", "auth", "t1", true)
+	testutil.EnsureUser(t, pool, "r1", "rev1", "t1", true)
+	testutil.EnsureUser(t, pool, "r2", "rev2", "t1", true)
+
+	pr := prmodel.PullRequest{
+		PullRequestID:     "pr-1",
+		PullRequestName:   "Test",
+		AuthorID:          "a1",
+		Status:            prmodel.PullRequestStatusOpen,
+		AssignedReviewers: []string{"r1"},
+		CreatedAt:         time.Now().UTC(),
+	}
+	if err := r.Create(ctx, pr); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	pr.AssignedReviewers = []string{"r2"}
+	if err := r.ReplaceReviewer(ctx, pr, "r1", "r2"); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	pr.AssignedReviewers = []string{"r1"}
+	if err := r.ReplaceReviewer(ctx, pr, "r2", "r1"); err != nil {
+		t.Fatalf("replace back: %v", err)
+	}
+
+	got, err := r.GetByID(ctx, "pr-1")
+	if err != nil || len(got.AssignedReviewers) != 1 || got.AssignedReviewers[0] != "r1" {
+		t.Fatalf("unexpected active reviewers: %+v err=%v", got, err)
+	}
+
+	history, err := r.History(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("expected 3 assignments, got %+v", history)
+	}
+	if history[0].UserID != "r1" || history[0].ReplacedBy == nil || *history[0].ReplacedBy != "r2" {
+		t.Fatalf("unexpected first assignment: %+v", history[0])
+	}
+	if history[2].UserID != "r1" || history[2].ReplacedAt != nil {
+		t.Fatalf("unexpected last assignment: %+v", history[2])
+	}
+}
+
+func TestPullRequestRepository_Verdicts(t *testing.T) {
+	pool := testutil.OpenTestPool(t)
+	testutil.TruncateAll(t, pool)
+	r := NewPullRequestRepository(pool)
+	ctx := context.Background()
+
+	testutil.EnsureUser(t, pool, "a1", "auth", "t1", true)
+	testutil.EnsureUser(t, pool, "r1", "rev1", "t1", true)
+	testutil.EnsureUser(t, pool, "r2", "rev2", "t1", true)
+	testutil.InsertPR(t, pool, prmodel.PullRequest{
+		PullRequestID: "pr-1", PullRequestName: "a", AuthorID: "a1",
+		Status: prmodel.PullRequestStatusOpen, AssignedReviewers: []string{"r1", "r2"}, CreatedAt: time.Now().UTC(),
+	})
+
+	if err := r.SetVerdict(ctx, "pr-1", "r1", prmodel.ReviewVerdictApproved); err != nil {
+		t.Fatalf("set verdict: %v", err)
+	}
+	if err := r.SetVerdict(ctx, "pr-1", "r2", prmodel.ReviewVerdictChangesRequested); err != nil {
+		t.Fatalf("set verdict: %v", err)
+	}
+	if err := r.SetVerdict(ctx, "pr-1", "a1", prmodel.ReviewVerdictApproved); err != ErrReviewerNotAssigned {
+		t.Fatalf("expected ErrReviewerNotAssigned, got %v", err)
+	}
+
+	count, err := r.CountApprovals(ctx, "pr-1")
+	if err != nil || count != 1 {
+		t.Fatalf("expected 1 approval, got %d err=%v", count, err)
+	}
+}
+
+func TestPullRequestRepository_OpenReviewsOf(t *testing.T) {
+	pool := testutil.OpenTestPool(t)
+	testutil.TruncateAll(t, pool)
+	r := NewPullRequestRepository(pool)
+	ctx := context.Background()
+
+	testutil.EnsureUser(t, pool, "a1", "a", "t1", true)
+	testutil.EnsureUser(t, pool, "u1", "b", "t1", true)
+	testutil.EnsureUser(t, pool, "u2", "c", "t1", true)
+	if _, err := pool.Exec(ctx, `
+		INSERT INTO pull_requests(pull_request_id, pull_request_name, author_id, status, created_at)
+		VALUES ('pr1','x','a1','OPEN', NOW()), ('pr2','y','a1','MERGED', NOW()), ('pr3','z','a1','OPEN', NOW());
+		INSERT INTO pr_reviewers(pull_request_id, user_id) VALUES ('pr1','u1'), ('pr1','u2'), ('pr2','u1'), ('pr3','u2');
+	`); err != nil {
+		t.Fatalf("seed PRs: %v", err)
+	}
+
+	prs, err := r.OpenReviewsOf(ctx, []string{"u1"})
+	if err != nil {
+		t.Fatalf("open reviews: %v", err)
+	}
+	if len(prs) != 1 || prs[0].PullRequestID != "pr1" {
+		t.Fatalf("expected only pr1, got %+v", prs)
+	}
+	if len(prs[0].AssignedReviewers) != 2 {
+		t.Fatalf("expected all active reviewers, got %v", prs[0].AssignedReviewers)
+	}
+}
+
+func TestPullRequestRepository_ReviewerPage(t *testing.T) {
+	pool := testutil.OpenTestPool(t)
+	testutil.TruncateAll(t, pool)
+	r := NewPullRequestRepository(pool)
+	ctx := context.Background()
+
+	testutil.EnsureUser(t, pool, "a", "author", "t1", true)
+	testutil.EnsureUser(t, pool, "u1", "rev", "t1", true)
+	testutil.EnsureUser(t, pool, "u2", "rev2", "t1", true)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	statuses := []prmodel.PullRequestStatus{
+		prmodel.PullRequestStatusOpen,
+		prmodel.PullRequestStatusMerged,
+		prmodel.PullRequestStatusOpen,
+		prmodel.PullRequestStatusClosed,
+		prmodel.PullRequestStatusOpen,
+	}
+	for i, st := range statuses {
+		testutil.InsertPR(t, pool, prmodel.PullRequest{
+			PullRequestID:     fmt.Sprintf("pr%d", i),
+			PullRequestName:   "x",
+			AuthorID:          "a",
+			Status:            st,
+			CreatedAt:         base.Add(time.Duration(i) * time.Hour),
+			AssignedReviewers: []string{"u1", "u2"},
+		})
+	}
+
+	page, err := r.ReviewerPage(ctx, prmodel.ReviewFilter{ReviewerID: "u1", Limit: 2})
+	if err != nil {
+		t.Fatalf("first page: %v", err)
+	}
+	if len(page.PullRequests) != 2 || page.PullRequests[0].PullRequestID != "pr4" || page.PullRequests[1].PullRequestID != "pr2" {
+		t.Fatalf("unexpected first page: %+v", page.PullRequests)
+	}
+	if len(page.PullRequests[0].AssignedReviewers) != 2 || page.Next == nil {
+		t.Fatalf("expected reviewers and a next cursor, got %+v", page)
+	}
+
+	page, err = r.ReviewerPage(ctx, prmodel.ReviewFilter{ReviewerID: "u1", Limit: 2, After: page.Next})
+	if err != nil {
+		t.Fatalf("second page: %v", err)
+	}
+	if len(page.PullRequests) != 2 || page.PullRequests[0].PullRequestID != "pr1" || page.Next != nil {
+		t.Fatalf("unexpected last page: %+v next=%v", page.PullRequests, page.Next)
+	}
+
+	after := base.Add(90 * time.Minute)
+	page, err = r.ReviewerPage(ctx, prmodel.ReviewFilter{
+		ReviewerID:   "u1",
+		Status:       prmodel.PullRequestStatusOpen,
+		CreatedAfter: &after,
+		Limit:        10,
+	})
+	if err != nil {
+		t.Fatalf("filtered page: %v", err)
+	}
+	if len(page.PullRequests) != 2 {
+		t.Fatalf("expected pr2 and pr4, got %+v", page.PullRequests)
+	}
+}
+
+func TestPullRequestRepository_List(t *testing.T) {
+	pool := testutil.OpenTestPool(t)
+	testutil.TruncateAll(t, pool)
+	r := NewPullRequestRepository(pool)
+	ctx := context.Background()
+
+	testutil.EnsureUser(t, pool, "a1", "author", "backend", true)
+	testutil.EnsureUser(t, pool, "a2", "author2", "frontend", true)
+	testutil.EnsureUser(t, pool, "u1", "rev", "backend", true)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	seed := []prmodel.PullRequest{
+		{PullRequestID: "pr1", PullRequestName: "Fix login", AuthorID: "a1", Status: prmodel.PullRequestStatusOpen, AssignedReviewers: []string{"u1"}},
+		{PullRequestID: "pr2", PullRequestName: "Add 100% coverage", AuthorID: "a1", Status: prmodel.PullRequestStatusMerged},
+		{PullRequestID: "pr3", PullRequestName: "fix styles", AuthorID: "a2", Status: prmodel.PullRequestStatusOpen, AssignedReviewers: []string{"u1"}},
+	}
+	for i, pr := range seed {
+		pr.CreatedAt = base.Add(time.Duration(i) * time.Hour)
+		testutil.InsertPR(t, pool, pr)
+	}
+
+	page, err := r.List(ctx, prmodel.ListFilter{NameContains: "fix", Sort: prmodel.ListSortNameAsc, Limit: 1})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(page.PullRequests) != 1 || page.PullRequests[0].PullRequestID != "pr1" || page.Next == nil {
+		t.Fatalf("unexpected first page: %+v", page)
+	}
+	page, err = r.List(ctx, prmodel.ListFilter{NameContains: "fix", Sort: prmodel.ListSortNameAsc, Limit: 1, After: page.Next})
+	if err != nil {
+		t.Fatalf("list next: %v", err)
+	}
+	if len(page.PullRequests) != 1 || page.PullRequests[0].PullRequestID != "pr3" || page.Next != nil {
+		t.Fatalf("unexpected second page: %+v", page)
+	}
+
+	page, _ = r.List(ctx, prmodel.ListFilter{NameContains: "100%", Sort: prmodel.ListSortCreatedDesc, Limit: 10})
+	if len(page.PullRequests) != 1 || page.PullRequests[0].PullRequestID != "pr2" {
+		t.Fatalf("expected literal %% match, got %+v", page.PullRequests)
+	}
+
+	page, _ = r.List(ctx, prmodel.ListFilter{TeamName: "backend", ReviewerID: "u1", Sort: prmodel.ListSortCreatedDesc, Limit: 10})
+	if len(page.PullRequests) != 1 || page.PullRequests[0].PullRequestID != "pr1" {
+		t.Fatalf("expected pr1 for backend/u1, got %+v", page.PullRequests)
+	}
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	prmodel "avito-intern-test/internal/model/pullrequest"
+	statsmodel "avito-intern-test/internal/model/stats"
+)
+
+type StatsRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewStatsRepository(pool *pgxpool.Pool) *StatsRepository {
+	return &StatsRepository{pool: pool}
+}
+
+func (r *StatsRepository) UserStats(
+	ctx context.Context,
+	filter statsmodel.Filter,
+) ([]statsmodel.UserStats, error) {
+	prQuery, prArgs, err := filteredPullRequests(filter).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build filtered PRs query: %w", err)
+	}
+
+	queryBuilder := sq.
+		Select(
+			"u.user_id",
+			"u.username",
+			"COALESCE(u.team_name, '')",
+			"u.is_active",
+			"COUNT(r.user_id)",
+		).
+		Column(sq.Expr(
+			"COUNT(r.user_id) FILTER (WHERE r.replaced_at IS NULL AND p.status = ?)",
+			string(prmodel.PullRequestStatusOpen),
+		)).
+		Column(sq.Expr(
+			"COUNT(r.user_id) FILTER (WHERE r.replaced_at IS NULL AND p.status = ?)",
+			string(prmodel.PullRequestStatusMerged),
+		)).
+		From("users u").
+		LeftJoin(
+			"(pr_reviewers r JOIN ("+prQuery+") p ON p.pull_request_id = r.pull_request_id) ON r.user_id = u.user_id",
+			prArgs...,
+		).
+		GroupBy("u.user_id").
+		OrderBy("u.user_id").
+		PlaceholderFormat(sq.Dollar)
+	if filter.TeamName != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"u.team_name": filter.TeamName})
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build user stats query: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get user stats: %w", err)
+	}
+	defer rows.Close()
+
+	var result []statsmodel.UserStats
+	for rows.Next() {
+		var s statsmodel.UserStats
+		if err := rows.Scan(
+			&s.UserID,
+			&s.Username,
+			&s.TeamName,
+			&s.IsActive,
+			&s.TotalAssignments,
+			&s.OpenAssignments,
+			&s.MergedAssignments,
+		); err != nil {
+			return nil, fmt.Errorf("scan user stats: %w", err)
+		}
+		result = append(result, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("user stats rows err: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *StatsRepository) PullRequestStats(
+	ctx context.Context,
+	filter statsmodel.Filter,
+) ([]statsmodel.PullRequestStats, error) {
+	queryBuilder := sq.
+		Select(
+			"p.pull_request_id",
+			"p.pull_request_name",
+			"p.author_id",
+			"COALESCE(a.team_name, '')",
+			"p.status",
+			"COUNT(r.user_id) FILTER (WHERE r.replaced_at IS NULL)",
+			"COUNT(r.user_id)",
+		).
+		FromSelect(filteredPullRequests(filter), "p").
+		LeftJoin("users a ON a.user_id = p.author_id").
+		LeftJoin("pr_reviewers r ON r.pull_request_id = p.pull_request_id").
+		GroupBy("p.pull_request_id", "p.pull_request_name", "p.author_id", "a.team_name", "p.status").
+		OrderBy("p.pull_request_id").
+		PlaceholderFormat(sq.Dollar)
+	if filter.TeamName != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"a.team_name": filter.TeamName})
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build PR stats query: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get PR stats: %w", err)
+	}
+	defer rows.Close()
+
+	var result []statsmodel.PullRequestStats
+	for rows.Next() {
+		var s statsmodel.PullRequestStats
+		if err := rows.Scan(
+			&s.PullRequestID,
+			&s.PullRequestName,
+			&s.AuthorID,
+			&s.TeamName,
+			&s.Status,
+			&s.Reviewers,
+			&s.TotalAssignments,
+		); err != nil {
+			return nil, fmt.Errorf("scan PR stats: %w", err)
+		}
+		result = append(result, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("PR stats rows err: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *StatsRepository) StatusCounts(ctx context.Context) (map[prmodel.PullRequestStatus]int, error) {
+	query, args, err := sq.
+		Select("status", "COUNT(*)").
+		From("pull_requests").
+		GroupBy("status").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build status counts query: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get status counts: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[prmodel.PullRequestStatus]int)
+	for rows.Next() {
+		var (
+			status prmodel.PullRequestStatus
+			count  int
+		)
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("scan status count: %w", err)
+		}
+		result[status] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("status counts rows err: %w", err)
+	}
+
+	return result, nil
+}
+
+func filteredPullRequests(filter statsmodel.Filter) sq.SelectBuilder {
+	queryBuilder := sq.
+		Select("pull_request_id", "pull_request_name", "author_id", "status").
+		From("pull_requests")
+	if filter.CreatedAfter != nil {
+		queryBuilder = queryBuilder.Where(sq.GtOrEq{"created_at": *filter.CreatedAfter})
+	}
+	if filter.CreatedBefore != nil {
+		queryBuilder = queryBuilder.Where(sq.Lt{"created_at": *filter.CreatedBefore})
+	}
+	if filter.MergedAfter != nil {
+		queryBuilder = queryBuilder.Where(sq.GtOrEq{"merged_at": *filter.MergedAfter})
+	}
+	if filter.MergedBefore != nil {
+		queryBuilder = queryBuilder.Where(sq.Lt{"merged_at": *filter.MergedBefore})
+	}
+	return queryBuilder
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	prmodel "avito-intern-test/internal/model/pullrequest"
+	statsmodel "avito-intern-test/internal/model/stats"
+	"avito-intern-test/internal/repository/testutil"
+)
+
+func TestStatsRepository_Counts(t *testing.T) {
+	pool := testutil.OpenTestPool(t)
+	testutil.TruncateAll(t, pool)
+	r := NewStatsRepository(pool)
+	ctx := context.Background()
+
+	testutil.EnsureUser(t, pool, "a1", "a", "t1", true)
+	testutil.EnsureUser(t, pool, "u1", "b", "t1", true)
+	testutil.EnsureUser(t, pool, "u2", "c", "t2", true)
+	if _, err := pool.Exec(ctx, `
+		INSERT INTO pull_requests(pull_request_id, pull_request_name, author_id, status, created_at, merged_at)
+		VALUES ('pr1','x','a1','OPEN', NOW(), NULL),
+		       ('pr2','y','a1','MERGED', NOW() - INTERVAL '10 days', NOW() - INTERVAL '9 days');
+		INSERT INTO pr_reviewers(pull_request_id, user_id) VALUES ('pr1','u1'), ('pr2','u1'), ('pr1','u2');
+	`); err != nil {
+		t.Fatalf("seed PRs: %v", err)
+	}
+
+	users, err := r.UserStats(ctx, statsmodel.Filter{TeamName: "t1"})
+	if err != nil {
+		t.Fatalf("user stats: %v", err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("expected 2 users in t1, got %+v", users)
+	}
+	u1 := users[1]
+	if u1.UserID != "u1" || u1.TotalAssignments != 2 || u1.OpenAssignments != 1 || u1.MergedAssignments != 1 {
+		t.Fatalf("unexpected u1 stats: %+v", u1)
+	}
+
+	since := time.Now().Add(-24 * time.Hour)
+	prs, err := r.PullRequestStats(ctx, statsmodel.Filter{CreatedAfter: &since})
+	if err != nil {
+		t.Fatalf("PR stats: %v", err)
+	}
+	if len(prs) != 1 || prs[0].PullRequestID != "pr1" || prs[0].Reviewers != 2 || prs[0].TeamName != "t1" {
+		t.Fatalf("unexpected PR stats: %+v", prs)
+	}
+
+	counts, err := r.StatusCounts(ctx)
+	if err != nil {
+		t.Fatalf("status counts: %v", err)
+	}
+	if counts[prmodel.PullRequestStatusOpen] != 1 || counts[prmodel.PullRequestStatusMerged] != 1 || len(counts) != 2 {
+		t.Fatalf("unexpected status counts: %+v", counts)
+	}
+}
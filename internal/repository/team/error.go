@@ -1 +1,8 @@
 package repository
+
+import "avito-intern-test/internal/core"
+
+var (
+	ErrTeamNotFound = core.Throw(core.ErrorNotFound, "team not found")
+	ErrTeamExists   = core.Throw(core.ErrorTeamExists, "team_name already exists")
+)
@@ -2,21 +2,30 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"avito-intern-test/internal/core"
+	prmodel "avito-intern-test/internal/model/pullrequest"
 	teammodel "avito-intern-test/internal/model/team"
 	usermodel "avito-intern-test/internal/model/user"
+	prrepo "avito-intern-test/internal/repository/pullrequest"
 )
 
+const uniqueViolation = "23505"
+
 type TeamRepository struct {
-	pool *pgxpool.Pool
+	db *core.TxManager
 }
 
 func NewTeamRepository(pool *pgxpool.Pool) *TeamRepository {
-	return &TeamRepository{pool: pool}
+	return &TeamRepository{db: core.NewTxManager(pool)}
 }
 
 func (r *TeamRepository) GetTeamMembers(
@@ -35,7 +44,7 @@ func (r *TeamRepository) GetTeamMembers(
 		return nil, err
 	}
 
-	rows, err := r.pool.Query(ctx, query, args...)
+	rows, err := r.db.Conn(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -68,8 +77,11 @@ func (r *TeamRepository) Exists(ctx context.Context, teamName string) (bool, err
 	}
 
 	var exists = 0
-	err = r.pool.QueryRow(ctx, query, args...).Scan(&exists)
+	err = r.db.Conn(ctx).QueryRow(ctx, query, args...).Scan(&exists)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
 		return false, err
 	}
 	return exists > 0, nil
@@ -83,7 +95,7 @@ func (r *TeamRepository) Create(
 		Insert("teams").
 		Columns("team_name", "created_at").
 		Values(teamName, time.Now()).
-		Suffix("RETURNING team_name, created_at").
+		Suffix("RETURNING team_name, created_at, reviewer_strategy, required_reviewers, required_approvals").
 		PlaceholderFormat(sq.Dollar)
 
 	query, args, err := queryBuilder.ToSql()
@@ -92,11 +104,268 @@ func (r *TeamRepository) Create(
 	}
 
 	var createdAt time.Time
-	if err := r.pool.QueryRow(ctx, query, args...).Scan(&teamName, &createdAt); err != nil {
+	var settings teammodel.Settings
+	if err := r.db.Conn(ctx).QueryRow(ctx, query, args...).Scan(
+		&teamName,
+		&createdAt,
+		&settings.ReviewerStrategy,
+		&settings.RequiredReviewers,
+		&settings.RequiredApprovals,
+	); err != nil {
 		return nil, err
 	}
 	return &teammodel.Team{
 		Name:      teamName,
 		CreatedAt: createdAt,
+		Settings:  settings,
 	}, nil
 }
+
+func (r *TeamRepository) GetSettings(
+	ctx context.Context,
+	teamName string,
+) (teammodel.Settings, error) {
+	queryBuilder := sq.
+		Select("reviewer_strategy", "required_reviewers", "required_approvals").
+		From("teams").
+		Where(sq.Eq{"team_name": teamName}).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return teammodel.Settings{}, fmt.Errorf("build get team settings query: %w", err)
+	}
+
+	var settings teammodel.Settings
+	if err := r.db.Conn(ctx).QueryRow(ctx, query, args...).Scan(
+		&settings.ReviewerStrategy,
+		&settings.RequiredReviewers,
+		&settings.RequiredApprovals,
+	); err != nil {
+		return teammodel.Settings{}, fmt.Errorf("get team settings: %w", err)
+	}
+
+	fallbacks, err := getFallbackTeams(ctx, r.db.Conn(ctx), teamName)
+	if err != nil {
+		return teammodel.Settings{}, err
+	}
+	settings.FallbackTeams = fallbacks
+	return settings, nil
+}
+
+func (r *TeamRepository) UpdateSettings(
+	ctx context.Context,
+	teamName string,
+	settings teammodel.Settings,
+) (teammodel.Settings, error) {
+	queryBuilder := sq.
+		Update("teams").
+		Set("reviewer_strategy", string(settings.ReviewerStrategy)).
+		Set("required_reviewers", settings.RequiredReviewers).
+		Set("required_approvals", settings.RequiredApprovals).
+		Where(sq.Eq{"team_name": teamName}).
+		Suffix("RETURNING reviewer_strategy, required_reviewers, required_approvals").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return teammodel.Settings{}, fmt.Errorf("build update team settings query: %w", err)
+	}
+
+	var updated teammodel.Settings
+	err = r.db.WithinTx(ctx, func(ctx context.Context) error {
+		q := r.db.Conn(ctx)
+		if err := q.QueryRow(ctx, query, args...).Scan(
+			&updated.ReviewerStrategy,
+			&updated.RequiredReviewers,
+			&updated.RequiredApprovals,
+		); err != nil {
+			return fmt.Errorf("update team settings: %w", err)
+		}
+
+		if err := replaceFallbackTeams(ctx, q, teamName, settings.FallbackTeams); err != nil {
+			return err
+		}
+		fallbacks, err := getFallbackTeams(ctx, q, teamName)
+		if err != nil {
+			return err
+		}
+		updated.FallbackTeams = fallbacks
+		return nil
+	})
+	if err != nil {
+		return teammodel.Settings{}, err
+	}
+	return updated, nil
+}
+
+// Delete removes the team in one transaction. Members either move to
+// targetTeam or, when it is empty, are deactivated after their open reviews
+// are handed over according to moves.
+func (r *TeamRepository) Delete(
+	ctx context.Context,
+	teamName string,
+	targetTeam string,
+	moves []prmodel.ReviewerMove,
+) ([]usermodel.User, error) {
+	queryBuilder := sq.
+		Update("users").
+		Where(sq.Eq{"team_name": teamName}).
+		Suffix("RETURNING user_id, username, team_name, is_active").
+		PlaceholderFormat(sq.Dollar)
+	if targetTeam != "" {
+		queryBuilder = queryBuilder.Set("team_name", targetTeam)
+	} else {
+		queryBuilder = queryBuilder.Set("is_active", false)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build release members query: %w", err)
+	}
+
+	queryDelete, argsDelete, err := sq.
+		Delete("teams").
+		Where(sq.Eq{"team_name": teamName}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build delete team query: %w", err)
+	}
+
+	var users []usermodel.User
+	err = r.db.WithinTx(ctx, func(ctx context.Context) error {
+		q := r.db.Conn(ctx)
+		if targetTeam == "" {
+			if err := prrepo.ApplyReviewerMoves(ctx, q, moves); err != nil {
+				return err
+			}
+		}
+
+		rows, err := q.Query(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("release team members: %w", err)
+		}
+		for rows.Next() {
+			var u usermodel.User
+			if err := rows.Scan(&u.UserID, &u.Username, &u.TeamName, &u.IsActive); err != nil {
+				rows.Close()
+				return fmt.Errorf("scan user: %w", err)
+			}
+			if targetTeam == "" {
+				u.TeamName = ""
+			}
+			users = append(users, u)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("release team members rows err: %w", err)
+		}
+
+		tag, err := q.Exec(ctx, queryDelete, argsDelete...)
+		if err != nil {
+			return fmt.Errorf("delete team: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrTeamNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// Rename changes the primary key; users and fallback links follow through
+// ON UPDATE CASCADE foreign keys.
+func (r *TeamRepository) Rename(ctx context.Context, teamName, newName string) error {
+	query, args, err := sq.
+		Update("teams").
+		Set("team_name", newName).
+		Where(sq.Eq{"team_name": teamName}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build rename team query: %w", err)
+	}
+
+	tag, err := r.db.Conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return ErrTeamExists
+		}
+		return fmt.Errorf("rename team: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTeamNotFound
+	}
+	return nil
+}
+
+func getFallbackTeams(ctx context.Context, q core.Querier, teamName string) ([]string, error) {
+	query, args, err := sq.
+		Select("fallback_team").
+		From("team_fallbacks").
+		Where(sq.Eq{"team_name": teamName}).
+		OrderBy("position").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build get fallback teams query: %w", err)
+	}
+
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get fallback teams: %w", err)
+	}
+	defer rows.Close()
+
+	var teams []string
+	for rows.Next() {
+		var team string
+		if err := rows.Scan(&team); err != nil {
+			return nil, fmt.Errorf("scan fallback team: %w", err)
+		}
+		teams = append(teams, team)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("fallback teams rows err: %w", err)
+	}
+	return teams, nil
+}
+
+func replaceFallbackTeams(ctx context.Context, q core.Querier, teamName string, fallbacks []string) error {
+	queryDelete, argsDelete, err := sq.
+		Delete("team_fallbacks").
+		Where(sq.Eq{"team_name": teamName}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build delete fallback teams query: %w", err)
+	}
+	if _, err := q.Exec(ctx, queryDelete, argsDelete...); err != nil {
+		return fmt.Errorf("delete fallback teams: %w", err)
+	}
+
+	if len(fallbacks) == 0 {
+		return nil
+	}
+
+	insert := sq.
+		Insert("team_fallbacks").
+		Columns("team_name", "fallback_team", "position").
+		PlaceholderFormat(sq.Dollar)
+	for i, team := range fallbacks {
+		insert = insert.Values(teamName, team, i)
+	}
+	queryInsert, argsInsert, err := insert.ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert fallback teams query: %w", err)
+	}
+	if _, err := q.Exec(ctx, queryInsert, argsInsert...); err != nil {
+		return fmt.Errorf("insert fallback teams: %w", err)
+	}
+	return nil
+}
@@ -2,9 +2,15 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"reflect"
 	"testing"
 
+	"avito-intern-test/internal/core"
+	teammodel "avito-intern-test/internal/model/team"
+	usermodel "avito-intern-test/internal/model/user"
 	"avito-intern-test/internal/repository/testutil"
+	userrepo "avito-intern-test/internal/repository/user"
 )
 
 func TestTeamRepository_Create_Exists_GetMembers(t *testing.T) {
@@ -36,3 +42,171 @@ func TestTeamRepository_Create_Exists_GetMembers(t *testing.T) {
 		t.Fatalf("expected 2 members, got %d", len(users))
 	}
 }
+
+func TestTeamRepository_Settings(t *testing.T) {
+	pool := testutil.OpenTestPool(t)
+	testutil.TruncateAll(t, pool)
+	r := NewTeamRepository(pool)
+	ctx := context.Background()
+
+	team, err := r.Create(ctx, "backend")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if team.Settings.ReviewerStrategy != teammodel.DefaultReviewerStrategy ||
+		team.Settings.RequiredReviewers != teammodel.DefaultRequiredReviewers {
+		t.Fatalf("expected default settings, got %+v", team.Settings)
+	}
+
+	updated, err := r.UpdateSettings(ctx, "backend", teammodel.Settings{
+		ReviewerStrategy:  teammodel.ReviewerStrategyRoundRobin,
+		RequiredReviewers: 3,
+	})
+	if err != nil {
+		t.Fatalf("update settings: %v", err)
+	}
+	if updated.ReviewerStrategy != teammodel.ReviewerStrategyRoundRobin || updated.RequiredReviewers != 3 {
+		t.Fatalf("unexpected settings: %+v", updated)
+	}
+
+	got, err := r.GetSettings(ctx, "backend")
+	if err != nil {
+		t.Fatalf("get settings: %v", err)
+	}
+	if !reflect.DeepEqual(got, updated) {
+		t.Fatalf("expected %+v, got %+v", updated, got)
+	}
+}
+
+func TestTeamRepository_FallbackTeams(t *testing.T) {
+	pool := testutil.OpenTestPool(t)
+	testutil.TruncateAll(t, pool)
+	r := NewTeamRepository(pool)
+	ctx := context.Background()
+
+	testutil.EnsureTeam(t, pool, "backend")
+	testutil.EnsureTeam(t, pool, "platform")
+	testutil.EnsureTeam(t, pool, "infra")
+
+	settings, err := r.GetSettings(ctx, "backend")
+	if err != nil {
+		t.Fatalf("get settings: %v", err)
+	}
+	settings.FallbackTeams = []string{"platform", "infra"}
+	if _, err := r.UpdateSettings(ctx, "backend", settings); err != nil {
+		t.Fatalf("update settings: %v", err)
+	}
+
+	got, err := r.GetSettings(ctx, "backend")
+	if err != nil {
+		t.Fatalf("get settings: %v", err)
+	}
+	if !reflect.DeepEqual(got.FallbackTeams, []string{"platform", "infra"}) {
+		t.Fatalf("expected ordered fallbacks, got %v", got.FallbackTeams)
+	}
+
+	settings.FallbackTeams = []string{"infra"}
+	updated, err := r.UpdateSettings(ctx, "backend", settings)
+	if err != nil {
+		t.Fatalf("update settings: %v", err)
+	}
+	if !reflect.DeepEqual(updated.FallbackTeams, []string{"infra"}) {
+		t.Fatalf("expected fallbacks to be replaced, got %v", updated.FallbackTeams)
+	}
+}
+
+func TestTeamRepository_Delete(t *testing.T) {
+	pool := testutil.OpenTestPool(t)
+	testutil.TruncateAll(t, pool)
+	r := NewTeamRepository(pool)
+	ctx := context.Background()
+
+	testutil.EnsureUser(t, pool, "u1", "a", "old", true)
+	testutil.EnsureUser(t, pool, "u2", "b", "gone", true)
+	testutil.EnsureTeam(t, pool, "new")
+
+	users, err := r.Delete(ctx, "old", "new", nil)
+	if err != nil {
+		t.Fatalf("delete with target: %v", err)
+	}
+	if len(users) != 1 || users[0].TeamName != "new" || !users[0].IsActive {
+		t.Fatalf("expected member moved to new, got %+v", users)
+	}
+
+	users, err = r.Delete(ctx, "gone", "", nil)
+	if err != nil {
+		t.Fatalf("forced delete: %v", err)
+	}
+	if len(users) != 1 || users[0].TeamName != "" || users[0].IsActive {
+		t.Fatalf("expected member deactivated without team, got %+v", users)
+	}
+	if exists, _ := r.Exists(ctx, "gone"); exists {
+		t.Fatalf("team must be deleted")
+	}
+
+	if _, err := r.Delete(ctx, "missing", "new", nil); !errors.Is(err, ErrTeamNotFound) {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+}
+
+func TestTeamRepository_Rename(t *testing.T) {
+	pool := testutil.OpenTestPool(t)
+	testutil.TruncateAll(t, pool)
+	r := NewTeamRepository(pool)
+	ctx := context.Background()
+
+	testutil.EnsureUser(t, pool, "u1", "a", "backend", true)
+	testutil.EnsureTeam(t, pool, "platform")
+	settings, _ := r.GetSettings(ctx, "platform")
+	settings.FallbackTeams = []string{"backend"}
+	if _, err := r.UpdateSettings(ctx, "platform", settings); err != nil {
+		t.Fatalf("update settings: %v", err)
+	}
+
+	if err := r.Rename(ctx, "backend", "core"); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	members, err := r.GetTeamMembers(ctx, "core")
+	if err != nil || len(members) != 1 {
+		t.Fatalf("expected member to follow rename, got %v %v", members, err)
+	}
+	got, _ := r.GetSettings(ctx, "platform")
+	if !reflect.DeepEqual(got.FallbackTeams, []string{"core"}) {
+		t.Fatalf("expected fallback to follow rename, got %v", got.FallbackTeams)
+	}
+
+	if err := r.Rename(ctx, "core", "platform"); !errors.Is(err, ErrTeamExists) {
+		t.Fatalf("expected ErrTeamExists, got %v", err)
+	}
+	if err := r.Rename(ctx, "missing", "x"); !errors.Is(err, ErrTeamNotFound) {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+}
+
+func TestTeamRepository_CreateWithinTx_RollsBack(t *testing.T) {
+	pool := testutil.OpenTestPool(t)
+	testutil.TruncateAll(t, pool)
+	r := NewTeamRepository(pool)
+	users := userrepo.NewUserRepository(pool)
+	ctx := context.Background()
+
+	boom := errors.New("boom")
+	err := core.NewTxManager(pool).WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := r.Create(ctx, "backend"); err != nil {
+			return err
+		}
+		if err := users.CreateOrUpdate(ctx, usermodel.User{UserID: "u1", Username: "a", TeamName: "backend", IsActive: true}); err != nil {
+			return err
+		}
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected boom, got %v", err)
+	}
+	if exists, _ := r.Exists(ctx, "backend"); exists {
+		t.Fatalf("team must not survive a rolled back transaction")
+	}
+	if _, err := users.GetByID(ctx, "u1"); !errors.Is(err, userrepo.ErrUserNotFound) {
+		t.Fatalf("user must not survive a rolled back transaction, got %v", err)
+	}
+}
@@ -1,7 +1,7 @@
 package repository
 
-import "errors"
+import "avito-intern-test/internal/core"
 
 var (
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserNotFound = core.Throw(core.ErrorNotFound, "user not found")
 )
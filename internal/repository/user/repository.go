@@ -2,54 +2,25 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"avito-intern-test/internal/core"
+	prmodel "avito-intern-test/internal/model/pullrequest"
 	usermodel "avito-intern-test/internal/model/user"
+	prrepo "avito-intern-test/internal/repository/pullrequest"
 )
 
 type UserRepository struct {
-	pool *pgxpool.Pool
+	db *core.TxManager
 }
 
 func NewUserRepository(pool *pgxpool.Pool) *UserRepository {
-	return &UserRepository{pool: pool}
-}
-
-func (r *UserRepository) GetReviewerPRs(ctx context.Context, reviewerID string) ([]string, error) {
-	queryBuilder := sq.
-		Select("pull_request_id").
-		From("pr_reviewers").
-		Where(sq.Eq{"user_id": reviewerID}).
-		OrderBy("pull_request_id").
-		PlaceholderFormat(sq.Dollar)
-
-	query, args, err := queryBuilder.ToSql()
-	if err != nil {
-		return nil, fmt.Errorf("build get reviewer PRs query: %w", err)
-	}
-
-	rows, err := r.pool.Query(ctx, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("get reviewer PRs: %w", err)
-	}
-	defer rows.Close()
-
-	var ids []string
-	for rows.Next() {
-		var id string
-		if err := rows.Scan(&id); err != nil {
-			return nil, fmt.Errorf("scan pull_request_id: %w", err)
-		}
-		ids = append(ids, id)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("reviewer PRs rows err: %w", err)
-	}
-	return ids, nil
+	return &UserRepository{db: core.NewTxManager(pool)}
 }
 
 func (r *UserRepository) CreateOrUpdate(
@@ -71,7 +42,7 @@ func (r *UserRepository) CreateOrUpdate(
 		return fmt.Errorf("build insert user query: %w", err)
 	}
 
-	if _, err := r.pool.Exec(ctx, query, args...); err != nil {
+	if _, err := r.db.Conn(ctx).Exec(ctx, query, args...); err != nil {
 		return fmt.Errorf("create or update user: %w", err)
 	}
 
@@ -80,7 +51,7 @@ func (r *UserRepository) CreateOrUpdate(
 
 func (r *UserRepository) GetByID(ctx context.Context, userID string) (usermodel.User, error) {
 	queryBuilder := sq.
-		Select("user_id", "username", "team_name", "is_active").
+		Select("user_id", "username", "COALESCE(team_name, '')", "is_active").
 		From("users").
 		Where(sq.Eq{"user_id": userID}).
 		PlaceholderFormat(sq.Dollar)
@@ -91,15 +62,15 @@ func (r *UserRepository) GetByID(ctx context.Context, userID string) (usermodel.
 	}
 
 	var u usermodel.User
-	err = r.pool.QueryRow(ctx, query, args...).Scan(
+	err = r.db.Conn(ctx).QueryRow(ctx, query, args...).Scan(
 		&u.UserID,
 		&u.Username,
 		&u.TeamName,
 		&u.IsActive,
 	)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return usermodel.User{}, fmt.Errorf("user not found")
+		if errors.Is(err, pgx.ErrNoRows) {
+			return usermodel.User{}, ErrUserNotFound
 		}
 		return usermodel.User{}, fmt.Errorf("get user by id: %w", err)
 	}
@@ -109,7 +80,7 @@ func (r *UserRepository) GetByID(ctx context.Context, userID string) (usermodel.
 
 func (r *UserRepository) GetByTeam(ctx context.Context, teamName string) ([]usermodel.User, error) {
 	queryBuilder := sq.
-		Select("user_id", "username", "team_name", "is_active").
+		Select("user_id", "username", "COALESCE(team_name, '')", "is_active").
 		From("users").
 		Where(sq.Eq{"team_name": teamName}).
 		OrderBy("user_id").
@@ -120,7 +91,7 @@ func (r *UserRepository) GetByTeam(ctx context.Context, teamName string) ([]user
 		return nil, fmt.Errorf("build get users by team query: %w", err)
 	}
 
-	rows, err := r.pool.Query(ctx, query, args...)
+	rows, err := r.db.Conn(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("get users by team: %w", err)
 	}
@@ -141,6 +112,122 @@ func (r *UserRepository) GetByTeam(ctx context.Context, teamName string) ([]user
 	return users, nil
 }
 
+// GetProfile loads the user with their active open review count and the
+// open PRs they authored.
+func (r *UserRepository) GetProfile(ctx context.Context, userID string) (usermodel.Profile, error) {
+	query, args, err := sq.
+		Select(
+			"u.user_id",
+			"u.username",
+			"COALESCE(u.team_name, '')",
+			"u.is_active",
+			"(SELECT COUNT(*) FROM pr_reviewers r JOIN pull_requests p ON p.pull_request_id = r.pull_request_id"+
+				" WHERE r.user_id = u.user_id AND r.replaced_at IS NULL AND p.status = 'OPEN')",
+		).
+		From("users u").
+		Where(sq.Eq{"u.user_id": userID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return usermodel.Profile{}, fmt.Errorf("build get profile query: %w", err)
+	}
+
+	var p usermodel.Profile
+	err = r.db.Conn(ctx).QueryRow(ctx, query, args...).Scan(
+		&p.UserID,
+		&p.Username,
+		&p.TeamName,
+		&p.IsActive,
+		&p.OpenReviews,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return usermodel.Profile{}, ErrUserNotFound
+		}
+		return usermodel.Profile{}, fmt.Errorf("get profile: %w", err)
+	}
+
+	queryPRs, argsPRs, err := sq.
+		Select("pull_request_id", "pull_request_name", "author_id", "status").
+		From("pull_requests").
+		Where(sq.Eq{"author_id": userID}).
+		Where(sq.Eq{"status": string(prmodel.PullRequestStatusOpen)}).
+		OrderBy("created_at DESC", "pull_request_id DESC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return usermodel.Profile{}, fmt.Errorf("build authored PRs query: %w", err)
+	}
+
+	rows, err := r.db.Conn(ctx).Query(ctx, queryPRs, argsPRs...)
+	if err != nil {
+		return usermodel.Profile{}, fmt.Errorf("get authored PRs: %w", err)
+	}
+	defer rows.Close()
+
+	p.AuthoredOpenPRs = []prmodel.PullRequestShort{}
+	for rows.Next() {
+		var pr prmodel.PullRequestShort
+		if err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status); err != nil {
+			return usermodel.Profile{}, fmt.Errorf("scan authored PR: %w", err)
+		}
+		p.AuthoredOpenPRs = append(p.AuthoredOpenPRs, pr)
+	}
+	if err := rows.Err(); err != nil {
+		return usermodel.Profile{}, fmt.Errorf("authored PRs rows err: %w", err)
+	}
+
+	return p, nil
+}
+
+func (r *UserRepository) List(ctx context.Context, filter usermodel.ListFilter) (usermodel.Page, error) {
+	queryBuilder := sq.
+		Select("user_id", "username", "COALESCE(team_name, '')", "is_active").
+		From("users").
+		OrderBy("user_id").
+		Limit(uint64(filter.Limit + 1)).
+		PlaceholderFormat(sq.Dollar)
+	if filter.TeamName != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"team_name": filter.TeamName})
+	}
+	if filter.IsActive != nil {
+		queryBuilder = queryBuilder.Where(sq.Eq{"is_active": *filter.IsActive})
+	}
+	if filter.After != "" {
+		queryBuilder = queryBuilder.Where(sq.Gt{"user_id": filter.After})
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return usermodel.Page{}, fmt.Errorf("build list users query: %w", err)
+	}
+
+	rows, err := r.db.Conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return usermodel.Page{}, fmt.Errorf("list users: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]usermodel.User, 0, filter.Limit+1)
+	for rows.Next() {
+		var u usermodel.User
+		if err := rows.Scan(&u.UserID, &u.Username, &u.TeamName, &u.IsActive); err != nil {
+			return usermodel.Page{}, fmt.Errorf("scan user: %w", err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return usermodel.Page{}, fmt.Errorf("list users rows err: %w", err)
+	}
+
+	page := usermodel.Page{Users: users}
+	if len(users) > filter.Limit {
+		page.Users = users[:filter.Limit]
+		page.Next = page.Users[filter.Limit-1].UserID
+	}
+	return page, nil
+}
+
 func (r *UserRepository) SetIsActive(
 	ctx context.Context,
 	userID string,
@@ -150,7 +237,7 @@ func (r *UserRepository) SetIsActive(
 		Update("users").
 		Set("is_active", flag).
 		Where(sq.Eq{"user_id": userID}).
-		Suffix("RETURNING user_id, username, team_name, is_active").
+		Suffix("RETURNING user_id, username, COALESCE(team_name, ''), is_active").
 		PlaceholderFormat(sq.Dollar)
 
 	query, args, err := queryBuilder.ToSql()
@@ -159,18 +246,117 @@ func (r *UserRepository) SetIsActive(
 	}
 
 	var u usermodel.User
-	err = r.pool.QueryRow(ctx, query, args...).Scan(
+	err = r.db.Conn(ctx).QueryRow(ctx, query, args...).Scan(
 		&u.UserID,
 		&u.Username,
 		&u.TeamName,
 		&u.IsActive,
 	)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return usermodel.User{}, fmt.Errorf("user not found")
+		if errors.Is(err, pgx.ErrNoRows) {
+			return usermodel.User{}, ErrUserNotFound
 		}
 		return usermodel.User{}, fmt.Errorf("set user is_active: %w", err)
 	}
 
 	return u, nil
 }
+
+// Deactivate marks the users inactive and applies the reviewer moves in a
+// single transaction.
+func (r *UserRepository) Deactivate(
+	ctx context.Context,
+	userIDs []string,
+	moves []prmodel.ReviewerMove,
+) ([]usermodel.User, error) {
+	queryUpdate, argsUpdate, err := sq.
+		Update("users").
+		Set("is_active", false).
+		Where(sq.Eq{"user_id": userIDs}).
+		Suffix("RETURNING user_id, username, COALESCE(team_name, ''), is_active").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build deactivate users query: %w", err)
+	}
+
+	var users []usermodel.User
+	err = r.db.WithinTx(ctx, func(ctx context.Context) error {
+		q := r.db.Conn(ctx)
+		if err := prrepo.ApplyReviewerMoves(ctx, q, moves); err != nil {
+			return err
+		}
+
+		rows, err := q.Query(ctx, queryUpdate, argsUpdate...)
+		if err != nil {
+			return fmt.Errorf("deactivate users: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var u usermodel.User
+			if err := rows.Scan(&u.UserID, &u.Username, &u.TeamName, &u.IsActive); err != nil {
+				return fmt.Errorf("scan user: %w", err)
+			}
+			users = append(users, u)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("deactivate users rows err: %w", err)
+		}
+		if len(users) != len(userIDs) {
+			return ErrUserNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// MoveToTeam transfers the user to teamName and applies the reviewer moves
+// in a single transaction.
+func (r *UserRepository) MoveToTeam(
+	ctx context.Context,
+	userID string,
+	teamName string,
+	moves []prmodel.ReviewerMove,
+) (usermodel.User, error) {
+	query, args, err := sq.
+		Update("users").
+		Set("team_name", teamName).
+		Where(sq.Eq{"user_id": userID}).
+		Suffix("RETURNING user_id, username, COALESCE(team_name, ''), is_active").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return usermodel.User{}, fmt.Errorf("build move user query: %w", err)
+	}
+
+	var u usermodel.User
+	err = r.db.WithinTx(ctx, func(ctx context.Context) error {
+		q := r.db.Conn(ctx)
+		if err := prrepo.ApplyReviewerMoves(ctx, q, moves); err != nil {
+			return err
+		}
+
+		err := q.QueryRow(ctx, query, args...).Scan(
+			&u.UserID,
+			&u.Username,
+			&u.TeamName,
+			&u.IsActive,
+		)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrUserNotFound
+			}
+			return fmt.Errorf("move user: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return usermodel.User{}, err
+	}
+	return u, nil
+}
@@ -2,12 +2,40 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"testing"
 
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	prmodel "avito-intern-test/internal/model/pullrequest"
 	usermodel "avito-intern-test/internal/model/user"
+	prrepo "avito-intern-test/internal/repository/pullrequest"
 	"avito-intern-test/internal/repository/testutil"
 )
 
+// reviewerPRs lists the PRs userID currently reviews.
+func reviewerPRs(t *testing.T, pool *pgxpool.Pool, userID string) []string {
+	t.Helper()
+	rows, err := pool.Query(context.Background(), `
+		SELECT pull_request_id FROM pr_reviewers
+		WHERE user_id = $1 AND replaced_at IS NULL
+		ORDER BY pull_request_id`, userID)
+	if err != nil {
+		t.Fatalf("query reviewer PRs: %v", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			t.Fatalf("scan reviewer PR: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
 func TestUserRepository_CRUD(t *testing.T) {
 	pool := testutil.OpenTestPool(t)
 	testutil.TruncateAll(t, pool)
@@ -49,7 +77,7 @@ func TestUserRepository_CRUD(t *testing.T) {
 	}
 }
 
-func TestUserRepository_ByTeam_And_ReviewerPRs(t *testing.T) {
+func TestUserRepository_ByTeam(t *testing.T) {
 	pool := testutil.OpenTestPool(t)
 	testutil.TruncateAll(t, pool)
 	r := NewUserRepository(pool)
@@ -69,21 +97,6 @@ func TestUserRepository_ByTeam_And_ReviewerPRs(t *testing.T) {
 	if len(users) != 2 {
 		t.Fatalf("expected 2 users, got %d", len(users))
 	}
-
-	if _, err := pool.Exec(ctx, `
-		INSERT INTO pull_requests(pull_request_id, pull_request_name, author_id, status, created_at) 
-		VALUES ('pr1','x','u1','OPEN', NOW()), ('pr2','y','u1','OPEN', NOW());
-		INSERT INTO pr_reviewers(pull_request_id, user_id) VALUES ('pr1','u2'), ('pr2','u2');
-	`); err != nil {
-		t.Fatalf("seed PRs: %v", err)
-	}
-	ids, err := r.GetReviewerPRs(ctx, "u2")
-	if err != nil {
-		t.Fatalf("get reviewer prs: %v", err)
-	}
-	if len(ids) != 2 {
-		t.Fatalf("expected 2 ids, got %d", len(ids))
-	}
 }
 
 func TestUserRepository_GetReviewerPRs(t *testing.T) {
@@ -147,19 +160,126 @@ func TestUserRepository_GetByTeam(t *testing.T) {
 	if len(users) != 2 {
 		t.Fatalf("expected 2 users, got %d", len(users))
 	}
+}
 
+func TestUserRepository_Deactivate_MovesReviews(t *testing.T) {
+	pool := testutil.OpenTestPool(t)
+	testutil.TruncateAll(t, pool)
+	r := NewUserRepository(pool)
+	ctx := context.Background()
+
+	testutil.EnsureUser(t, pool, "u1", "a", "t1", true)
+	testutil.EnsureUser(t, pool, "u2", "b", "t1", true)
+	testutil.EnsureUser(t, pool, "u3", "c", "t1", true)
 	if _, err := pool.Exec(ctx, `
-		INSERT INTO pull_requests(pull_request_id, pull_request_name, author_id, status, created_at) 
-		VALUES ('pr1','x','u1','OPEN', NOW()), ('pr2','y','u1','OPEN', NOW());
-		INSERT INTO pr_reviewers(pull_request_id, user_id) VALUES ('pr1','u2'), ('pr2','u2');
+		INSERT INTO pull_requests(pull_request_id, pull_request_name, author_id, status, created_at)
+		VALUES ('pr1','x','u1','OPEN', NOW());
+		INSERT INTO pr_reviewers(pull_request_id, user_id) VALUES ('pr1','u2');
 	`); err != nil {
 		t.Fatalf("seed PRs: %v", err)
 	}
-	ids, err := r.GetReviewerPRs(ctx, "u2")
+
+	users, err := r.Deactivate(ctx, []string{"u2"}, []prmodel.ReviewerMove{
+		{PullRequestID: "pr1", FromUserID: "u2", ToUserID: "u3"},
+	})
 	if err != nil {
-		t.Fatalf("get reviewer prs: %v", err)
+		t.Fatalf("deactivate: %v", err)
+	}
+	if len(users) != 1 || users[0].IsActive {
+		t.Fatalf("unexpected users: %+v", users)
+	}
+	ids := reviewerPRs(t, pool, "u3")
+	if len(ids) != 1 || ids[0] != "pr1" {
+		t.Fatalf("expected pr1 moved to u3, got %v", ids)
+	}
+
+	_, err = r.Deactivate(ctx, []string{"u3"}, []prmodel.ReviewerMove{
+		{PullRequestID: "pr1", FromUserID: "u2", ToUserID: "u1"},
+	})
+	if !errors.Is(err, prrepo.ErrReviewerNotAssigned) {
+		t.Fatalf("expected ErrReviewerNotAssigned, got %v", err)
+	}
+	got, _ := r.GetByID(ctx, "u3")
+	if !got.IsActive {
+		t.Fatalf("failed deactivation must roll back")
+	}
+}
+
+func TestUserRepository_MoveToTeam(t *testing.T) {
+	pool := testutil.OpenTestPool(t)
+	testutil.TruncateAll(t, pool)
+	r := NewUserRepository(pool)
+	ctx := context.Background()
+
+	testutil.EnsureUser(t, pool, "u1", "a", "t1", true)
+	testutil.EnsureUser(t, pool, "u2", "b", "t1", true)
+	testutil.EnsureUser(t, pool, "u3", "c", "t1", true)
+	testutil.EnsureTeam(t, pool, "t2")
+	if _, err := pool.Exec(ctx, `
+		INSERT INTO pull_requests(pull_request_id, pull_request_name, author_id, status, created_at)
+		VALUES ('pr1','x','u1','OPEN', NOW());
+		INSERT INTO pr_reviewers(pull_request_id, user_id) VALUES ('pr1','u2');
+	`); err != nil {
+		t.Fatalf("seed PRs: %v", err)
+	}
+
+	got, err := r.MoveToTeam(ctx, "u2", "t2", []prmodel.ReviewerMove{
+		{PullRequestID: "pr1", FromUserID: "u2", ToUserID: "u3"},
+	})
+	if err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if got.TeamName != "t2" {
+		t.Fatalf("expected t2, got %+v", got)
+	}
+	if ids := reviewerPRs(t, pool, "u2"); len(ids) != 0 {
+		t.Fatalf("expected reviews handed over, got %v", ids)
+	}
+
+	if _, err := r.MoveToTeam(ctx, "nope", "t2", nil); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestUserRepository_GetProfile_And_List(t *testing.T) {
+	pool := testutil.OpenTestPool(t)
+	testutil.TruncateAll(t, pool)
+	r := NewUserRepository(pool)
+	ctx := context.Background()
+
+	testutil.EnsureUser(t, pool, "u1", "a", "t1", true)
+	testutil.EnsureUser(t, pool, "u2", "b", "t1", false)
+	testutil.EnsureUser(t, pool, "u3", "c", "t1", true)
+	testutil.EnsureUser(t, pool, "u4", "d", "t2", true)
+	if _, err := pool.Exec(ctx, `
+		INSERT INTO pull_requests(pull_request_id, pull_request_name, author_id, status, created_at)
+		VALUES ('pr1','x','u1','OPEN', NOW()), ('pr2','y','u1','MERGED', NOW()), ('pr3','z','u3','OPEN', NOW());
+		INSERT INTO pr_reviewers(pull_request_id, user_id) VALUES ('pr3','u1'), ('pr2','u1');
+	`); err != nil {
+		t.Fatalf("seed PRs: %v", err)
+	}
+
+	p, err := r.GetProfile(ctx, "u1")
+	if err != nil {
+		t.Fatalf("get profile: %v", err)
+	}
+	if p.TeamName != "t1" || p.OpenReviews != 1 || len(p.AuthoredOpenPRs) != 1 || p.AuthoredOpenPRs[0].PullRequestID != "pr1" {
+		t.Fatalf("unexpected profile: %+v", p)
+	}
+	if _, err := r.GetProfile(ctx, "ghost"); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+
+	active := true
+	page, err := r.List(ctx, usermodel.ListFilter{TeamName: "t1", IsActive: &active, Limit: 1})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(page.Users) != 1 || page.Users[0].UserID != "u1" || page.Next != "u1" {
+		t.Fatalf("unexpected first page: %+v", page)
 	}
-	if len(ids) != 2 {
-		t.Fatalf("expected 2 PR ids, got %d", len(ids))
+	page, _ = r.List(ctx, usermodel.ListFilter{TeamName: "t1", IsActive: &active, Limit: 1, After: page.Next})
+	if len(page.Users) != 1 || page.Users[0].UserID != "u3" || page.Next != "" {
+		t.Fatalf("unexpected last page: %+v", page)
 	}
 }
@@ -0,0 +1,5 @@
+package repository
+
+import "avito-intern-test/internal/core"
+
+var ErrWebhookNotFound = core.Throw(core.ErrorNotFound, "webhook not found")
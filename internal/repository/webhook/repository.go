@@ -0,0 +1,294 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"avito-intern-test/internal/core"
+	eventmodel "avito-intern-test/internal/model/event"
+	webhookmodel "avito-intern-test/internal/model/webhook"
+)
+
+const webhookColumns = "webhook_id, url, secret, events, is_active, created_at"
+
+type WebhookRepository struct {
+	db *core.TxManager
+}
+
+func NewWebhookRepository(pool *pgxpool.Pool) *WebhookRepository {
+	return &WebhookRepository{db: core.NewTxManager(pool)}
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, wh webhookmodel.Webhook) (webhookmodel.Webhook, error) {
+	query, args, err := sq.
+		Insert("webhooks").
+		Columns("url", "secret", "events", "is_active", "created_at").
+		Values(wh.URL, wh.Secret, eventTypesToStrings(wh.Events), wh.IsActive, time.Now().UTC()).
+		Suffix("RETURNING " + webhookColumns).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return webhookmodel.Webhook{}, fmt.Errorf("build create webhook query: %w", err)
+	}
+
+	created, err := scanWebhook(r.db.Conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		return webhookmodel.Webhook{}, fmt.Errorf("create webhook: %w", err)
+	}
+	return created, nil
+}
+
+func (r *WebhookRepository) GetByID(ctx context.Context, id int64) (webhookmodel.Webhook, error) {
+	query, args, err := sq.
+		Select(webhookColumns).
+		From("webhooks").
+		Where(sq.Eq{"webhook_id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return webhookmodel.Webhook{}, fmt.Errorf("build get webhook query: %w", err)
+	}
+
+	wh, err := scanWebhook(r.db.Conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return webhookmodel.Webhook{}, ErrWebhookNotFound
+		}
+		return webhookmodel.Webhook{}, fmt.Errorf("get webhook: %w", err)
+	}
+	return wh, nil
+}
+
+func (r *WebhookRepository) List(ctx context.Context) ([]webhookmodel.Webhook, error) {
+	query, args, err := sq.
+		Select(webhookColumns).
+		From("webhooks").
+		OrderBy("webhook_id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build list webhooks query: %w", err)
+	}
+	return r.queryWebhooks(ctx, query, args)
+}
+
+// ListSubscribed returns active webhooks that asked for the event type.
+func (r *WebhookRepository) ListSubscribed(ctx context.Context, t eventmodel.Type) ([]webhookmodel.Webhook, error) {
+	query, args, err := sq.
+		Select(webhookColumns).
+		From("webhooks").
+		Where(sq.Eq{"is_active": true}).
+		Where(sq.Expr("? = ANY(events)", string(t))).
+		OrderBy("webhook_id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build list subscribed webhooks query: %w", err)
+	}
+	return r.queryWebhooks(ctx, query, args)
+}
+
+func (r *WebhookRepository) Update(ctx context.Context, wh webhookmodel.Webhook) (webhookmodel.Webhook, error) {
+	query, args, err := sq.
+		Update("webhooks").
+		Set("url", wh.URL).
+		Set("events", eventTypesToStrings(wh.Events)).
+		Set("is_active", wh.IsActive).
+		Where(sq.Eq{"webhook_id": wh.ID}).
+		Suffix("RETURNING " + webhookColumns).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return webhookmodel.Webhook{}, fmt.Errorf("build update webhook query: %w", err)
+	}
+
+	updated, err := scanWebhook(r.db.Conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return webhookmodel.Webhook{}, ErrWebhookNotFound
+		}
+		return webhookmodel.Webhook{}, fmt.Errorf("update webhook: %w", err)
+	}
+	return updated, nil
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, id int64) error {
+	query, args, err := sq.
+		Delete("webhooks").
+		Where(sq.Eq{"webhook_id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build delete webhook query: %w", err)
+	}
+
+	tag, err := r.db.Conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("delete webhook: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (r *WebhookRepository) LogDelivery(ctx context.Context, d webhookmodel.Delivery) error {
+	var errText *string
+	if d.Error != "" {
+		errText = &d.Error
+	}
+	query, args, err := sq.
+		Insert("webhook_deliveries").
+		Columns("webhook_id", "event_id", "event_type", "attempt", "status_code", "error", "success", "created_at").
+		Values(d.WebhookID, d.EventID, string(d.EventType), d.Attempt, d.StatusCode, errText, d.Success, time.Now().UTC()).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build log webhook delivery query: %w", err)
+	}
+
+	if _, err := r.db.Conn(ctx).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("log webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// DeliveredWebhooks returns the webhooks that acknowledged the event.
+func (r *WebhookRepository) DeliveredWebhooks(ctx context.Context, eventID string) ([]int64, error) {
+	query, args, err := sq.
+		Select("DISTINCT webhook_id").
+		From("webhook_deliveries").
+		Where(sq.Eq{"event_id": eventID, "success": true}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build delivered webhooks query: %w", err)
+	}
+
+	rows, err := r.db.Conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list delivered webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan delivered webhook: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate delivered webhooks: %w", err)
+	}
+	return ids, nil
+}
+
+// Deliveries returns the newest delivery attempts of a webhook first.
+func (r *WebhookRepository) Deliveries(ctx context.Context, webhookID int64, limit int) ([]webhookmodel.Delivery, error) {
+	query, args, err := sq.
+		Select(
+			"delivery_id",
+			"webhook_id",
+			"event_id",
+			"event_type",
+			"attempt",
+			"status_code",
+			"COALESCE(error, '')",
+			"success",
+			"created_at",
+		).
+		From("webhook_deliveries").
+		Where(sq.Eq{"webhook_id": webhookID}).
+		OrderBy("delivery_id DESC").
+		Limit(uint64(limit)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build list webhook deliveries query: %w", err)
+	}
+
+	rows, err := r.db.Conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]webhookmodel.Delivery, 0)
+	for rows.Next() {
+		var (
+			d         webhookmodel.Delivery
+			eventType string
+		)
+		if err := rows.Scan(
+			&d.ID,
+			&d.WebhookID,
+			&d.EventID,
+			&eventType,
+			&d.Attempt,
+			&d.StatusCode,
+			&d.Error,
+			&d.Success,
+			&d.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan webhook delivery: %w", err)
+		}
+		d.EventType = eventmodel.Type(eventType)
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) queryWebhooks(ctx context.Context, query string, args []any) ([]webhookmodel.Webhook, error) {
+	rows, err := r.db.Conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := make([]webhookmodel.Webhook, 0)
+	for rows.Next() {
+		wh, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, wh)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+func scanWebhook(row pgx.Row) (webhookmodel.Webhook, error) {
+	var (
+		wh     webhookmodel.Webhook
+		events []string
+	)
+	if err := row.Scan(&wh.ID, &wh.URL, &wh.Secret, &events, &wh.IsActive, &wh.CreatedAt); err != nil {
+		return webhookmodel.Webhook{}, err
+	}
+	wh.Events = make([]eventmodel.Type, 0, len(events))
+	for _, e := range events {
+		wh.Events = append(wh.Events, eventmodel.Type(e))
+	}
+	return wh, nil
+}
+
+func eventTypesToStrings(types []eventmodel.Type) []string {
+	out := make([]string, 0, len(types))
+	for _, t := range types {
+		out = append(out, string(t))
+	}
+	return out
+}
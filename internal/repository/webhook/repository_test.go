@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	eventmodel "avito-intern-test/internal/model/event"
+	webhookmodel "avito-intern-test/internal/model/webhook"
+	"avito-intern-test/internal/repository/testutil"
+)
+
+func TestWebhookRepository_Lifecycle(t *testing.T) {
+	pool := testutil.OpenTestPool(t)
+	r := NewWebhookRepository(pool)
+	ctx := context.Background()
+	if _, err := pool.Exec(ctx, "TRUNCATE TABLE webhooks CASCADE"); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	wh, err := r.Create(ctx, webhookmodel.Webhook{
+		URL:      "http://example.test/hook",
+		Secret:   "s3cret",
+		Events:   []eventmodel.Type{eventmodel.TypePullRequestMerged},
+		IsActive: true,
+	})
+	if err != nil || wh.ID == 0 {
+		t.Fatalf("create: %+v %v", wh, err)
+	}
+
+	subs, err := r.ListSubscribed(ctx, eventmodel.TypePullRequestMerged)
+	if err != nil || len(subs) != 1 {
+		t.Fatalf("expected one subscriber, got %+v %v", subs, err)
+	}
+	if subs, _ := r.ListSubscribed(ctx, eventmodel.TypeReviewersAssigned); len(subs) != 0 {
+		t.Fatalf("unexpected subscribers: %+v", subs)
+	}
+
+	wh.IsActive = false
+	if _, err := r.Update(ctx, wh); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if subs, _ := r.ListSubscribed(ctx, eventmodel.TypePullRequestMerged); len(subs) != 0 {
+		t.Fatalf("inactive webhook must not be subscribed")
+	}
+
+	status := 500
+	if err := r.LogDelivery(ctx, webhookmodel.Delivery{
+		WebhookID:  wh.ID,
+		EventID:    "e1",
+		EventType:  eventmodel.TypePullRequestMerged,
+		Attempt:    1,
+		StatusCode: &status,
+		Error:      "unexpected status 500",
+	}); err != nil {
+		t.Fatalf("log delivery: %v", err)
+	}
+	deliveries, err := r.Deliveries(ctx, wh.ID, 10)
+	if err != nil || len(deliveries) != 1 || *deliveries[0].StatusCode != 500 {
+		t.Fatalf("unexpected deliveries: %+v %v", deliveries, err)
+	}
+	if ids, err := r.DeliveredWebhooks(ctx, "e1"); err != nil || len(ids) != 0 {
+		t.Fatalf("failed attempt must not count as delivered: %v %v", ids, err)
+	}
+	status = 204
+	if err := r.LogDelivery(ctx, webhookmodel.Delivery{
+		WebhookID:  wh.ID,
+		EventID:    "e1",
+		EventType:  eventmodel.TypePullRequestMerged,
+		Attempt:    2,
+		StatusCode: &status,
+		Success:    true,
+	}); err != nil {
+		t.Fatalf("log delivery: %v", err)
+	}
+	if ids, err := r.DeliveredWebhooks(ctx, "e1"); err != nil || len(ids) != 1 || ids[0] != wh.ID {
+		t.Fatalf("expected webhook %d delivered, got %v %v", wh.ID, ids, err)
+	}
+
+	if err := r.Delete(ctx, wh.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := r.GetByID(ctx, wh.ID); !errors.Is(err, ErrWebhookNotFound) {
+		t.Fatalf("expected ErrWebhookNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+package routing
+
+import (
+	"github.com/go-chi/chi/v5"
+
+	"avito-intern-test/internal/core"
+	ak "avito-intern-test/internal/handler/apikey"
+	mw "avito-intern-test/internal/handler/middleware"
+)
+
+func RegisterAPIKeyRoutes(r chi.Router, h *ak.APIKeyHandler, idempotency *mw.Idempotency) {
+	r.Route("/admin/apiKeys", func(r chi.Router) {
+		r.Use(mw.RequireRole(core.RoleAdmin))
+		// The create response carries the plaintext key, so it is never
+		// stored for replay.
+		r.Post("/create", h.CreateAPIKey)
+		r.Get("/list", h.ListAPIKeys)
+		r.With(idempotency.Handle).Post("/revoke", h.RevokeAPIKey)
+	})
+}
@@ -0,0 +1,33 @@
+package routing
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"avito-intern-test/internal/core"
+	akh "avito-intern-test/internal/handler/apikey"
+	mw "avito-intern-test/internal/handler/middleware"
+)
+
+func TestAPIKeyRoutes_CreateIsNotStored(t *testing.T) {
+	svc := &idempotencyServiceMock{}
+	r := chi.NewRouter()
+	r.Use(withPrincipal(core.Principal{KeyID: 1, Role: core.RoleAdmin}))
+	RegisterAPIKeyRoutes(r, akh.NewAPIKeyHandler(nil), mw.NewIdempotency(svc))
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/apiKeys/create", bytes.NewBufferString(`{`))
+	req.Header.Set(mw.IdempotencyKeyHeader, "k1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected the handler to run, got %d", w.Code)
+	}
+	if svc.begun != 0 {
+		t.Fatalf("responses carrying a plaintext key must not be stored")
+	}
+}
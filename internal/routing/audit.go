@@ -0,0 +1,16 @@
+package routing
+
+import (
+	"github.com/go-chi/chi/v5"
+
+	"avito-intern-test/internal/core"
+	a "avito-intern-test/internal/handler/audit"
+	mw "avito-intern-test/internal/handler/middleware"
+)
+
+func RegisterAuditRoutes(r chi.Router, h *a.AuditHandler) {
+	r.Route("/audit", func(r chi.Router) {
+		r.Use(mw.RequireRole(core.RoleAdmin))
+		r.Get("/list", h.ListAudit)
+	})
+}
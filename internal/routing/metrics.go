@@ -0,0 +1,11 @@
+package routing
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterMetricsRoutes(r chi.Router, h http.Handler) {
+	r.Method(http.MethodGet, "/metrics", h)
+}
@@ -3,13 +3,25 @@ package routing
 import (
 	"github.com/go-chi/chi/v5"
 
+	"avito-intern-test/internal/core"
+	mw "avito-intern-test/internal/handler/middleware"
 	pr "avito-intern-test/internal/handler/pullrequest"
 )
 
-func RegisterPullRequestRoutes(r chi.Router, h *pr.PullRequestHandler) {
+func RegisterPullRequestRoutes(r chi.Router, h *pr.PullRequestHandler, idempotency *mw.Idempotency) {
+	bot := chi.Chain(mw.RequireRole(core.RoleBot), idempotency.Handle)
+	lead := chi.Chain(mw.RequireRole(core.RoleTeamLead), idempotency.Handle)
+
 	r.Route("/pullRequest", func(r chi.Router) {
-		r.Post("/create", h.CreatePullRequest)
-		r.Post("/merge", h.MergePullRequest)
-		r.Post("/reassign", h.ReassignPullRequest)
+		r.With(bot...).Post("/create", h.CreatePullRequest)
+		r.With(bot...).Post("/merge", h.MergePullRequest)
+		r.With(lead...).Post("/reassign", h.ReassignPullRequest)
+		r.With(bot...).Post("/ready", h.MarkReady)
+		r.With(bot...).Post("/close", h.ClosePullRequest)
+		r.With(bot...).Post("/reopen", h.ReopenPullRequest)
+		r.With(bot...).Post("/review", h.ReviewPullRequest)
+		r.Get("/get", h.GetPullRequest)
+		r.Get("/history", h.GetHistory)
+		r.Get("/list", h.ListPullRequests)
 	})
 }
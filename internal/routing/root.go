@@ -1,26 +1,52 @@
 package routing
 
 import (
+	"net/http"
+
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
 
+	akh "avito-intern-test/internal/handler/apikey"
+	ah "avito-intern-test/internal/handler/audit"
 	common "avito-intern-test/internal/handler/common"
+	mw "avito-intern-test/internal/handler/middleware"
 	prh "avito-intern-test/internal/handler/pullrequest"
+	sh "avito-intern-test/internal/handler/stats"
 	th "avito-intern-test/internal/handler/team"
 	uh "avito-intern-test/internal/handler/user"
+	wh "avito-intern-test/internal/handler/webhook"
 )
 
 func Router(
 	prHandler *prh.PullRequestHandler,
 	teamHandler *th.TeamHandler,
 	userHandler *uh.UserHandler,
+	statsHandler *sh.StatsHandler,
+	webhookHandler *wh.WebhookHandler,
+	auditHandler *ah.AuditHandler,
+	apiKeyHandler *akh.APIKeyHandler,
+	auth *mw.Auth,
+	idempotency *mw.Idempotency,
+	metricsHandler http.Handler,
 ) *chi.Mux {
 	r := chi.NewRouter()
-	r.Use(middleware.Logger)
+	r.Use(mw.Tracing)
+	r.Use(mw.Metrics)
+	r.Use(mw.Actor)
 
 	RegisterCommonRoutes(r, common.Healthcheck)
-	RegisterPullRequestRoutes(r, prHandler)
-	RegisterTeamRoutes(r, teamHandler)
-	RegisterUserRoutes(r, userHandler)
+	RegisterMetricsRoutes(r, metricsHandler)
+	r.Group(func(r chi.Router) {
+		r.Use(auth.Handle)
+
+		// Idempotency is applied per route, after the role check, so a
+		// caller cannot replay a response to a request it may not make.
+		RegisterPullRequestRoutes(r, prHandler, idempotency)
+		RegisterTeamRoutes(r, teamHandler, idempotency)
+		RegisterUserRoutes(r, userHandler, idempotency)
+		RegisterStatsRoutes(r, statsHandler)
+		RegisterWebhookRoutes(r, webhookHandler, idempotency)
+		RegisterAuditRoutes(r, auditHandler)
+		RegisterAPIKeyRoutes(r, apiKeyHandler, idempotency)
+	})
 	return r
 }
@@ -0,0 +1,11 @@
+package routing
+
+import (
+	"github.com/go-chi/chi/v5"
+
+	s "avito-intern-test/internal/handler/stats"
+)
+
+func RegisterStatsRoutes(r chi.Router, h *s.StatsHandler) {
+	r.Get("/stats", h.GetStats)
+}
@@ -3,12 +3,22 @@ package routing
 import (
 	"github.com/go-chi/chi/v5"
 
+	"avito-intern-test/internal/core"
+	mw "avito-intern-test/internal/handler/middleware"
 	t "avito-intern-test/internal/handler/team"
 )
 
-func RegisterTeamRoutes(r chi.Router, h *t.TeamHandler) {
+func RegisterTeamRoutes(r chi.Router, h *t.TeamHandler, idempotency *mw.Idempotency) {
+	admin := chi.Chain(mw.RequireRole(core.RoleAdmin), idempotency.Handle)
+	lead := chi.Chain(mw.RequireRole(core.RoleTeamLead), idempotency.Handle)
+
 	r.Route("/team", func(r chi.Router) {
-		r.Post("/add", h.CreateTeam)
+		r.With(admin...).Post("/add", h.CreateTeam)
 		r.Get("/get", h.GetTeam)
+		r.Get("/settings", h.GetSettings)
+		r.With(lead...).Post("/settings", h.UpdateSettings)
+		r.With(lead...).Post("/deactivateUsers", h.DeactivateUsers)
+		r.With(admin...).Post("/rename", h.RenameTeam)
+		r.With(admin...).Post("/delete", h.DeleteTeam)
 	})
 }
@@ -0,0 +1,56 @@
+package routing
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"avito-intern-test/internal/core"
+	mw "avito-intern-test/internal/handler/middleware"
+	th "avito-intern-test/internal/handler/team"
+	idemmodel "avito-intern-test/internal/model/idempotency"
+)
+
+type idempotencyServiceMock struct {
+	begun int
+}
+
+func (m *idempotencyServiceMock) Begin(context.Context, string, string, string) (string, *idemmodel.Record, error) {
+	m.begun++
+	return "", &idemmodel.Record{StatusCode: http.StatusCreated}, nil
+}
+
+func (m *idempotencyServiceMock) Complete(context.Context, idemmodel.Record) error { return nil }
+
+func (m *idempotencyServiceMock) Abandon(context.Context, string, string, string) error { return nil }
+
+func withPrincipal(p core.Principal) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(core.WithPrincipal(r.Context(), p)))
+		})
+	}
+}
+
+func TestTeamRoutes_RoleCheckedBeforeIdempotency(t *testing.T) {
+	svc := &idempotencyServiceMock{}
+	r := chi.NewRouter()
+	r.Use(withPrincipal(core.Principal{KeyID: 7, Role: core.RoleReader}))
+	RegisterTeamRoutes(r, th.NewTeamHandler(nil), mw.NewIdempotency(svc))
+
+	req := httptest.NewRequest(http.MethodPost, "/team/add", bytes.NewBufferString(`{}`))
+	req.Header.Set(mw.IdempotencyKeyHeader, "k1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", w.Code)
+	}
+	if svc.begun != 0 {
+		t.Fatalf("a forbidden caller must not reach stored responses")
+	}
+}
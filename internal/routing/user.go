@@ -3,12 +3,19 @@ package routing
 import (
 	"github.com/go-chi/chi/v5"
 
+	"avito-intern-test/internal/core"
+	mw "avito-intern-test/internal/handler/middleware"
 	u "avito-intern-test/internal/handler/user"
 )
 
-func RegisterUserRoutes(r chi.Router, h *u.UserHandler) {
+func RegisterUserRoutes(r chi.Router, h *u.UserHandler, idempotency *mw.Idempotency) {
+	admin := chi.Chain(mw.RequireRole(core.RoleAdmin), idempotency.Handle)
+
 	r.Route("/users", func(r chi.Router) {
-		r.Post("/setIsActive", h.SetIsActive)
+		r.With(admin...).Post("/setIsActive", h.SetIsActive)
+		r.With(admin...).Post("/moveTeam", h.MoveTeam)
+		r.Get("/get", h.GetUser)
+		r.Get("/list", h.ListUsers)
 		r.Get("/getReview", h.GetReview)
 	})
 }
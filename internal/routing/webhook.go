@@ -0,0 +1,23 @@
+package routing
+
+import (
+	"github.com/go-chi/chi/v5"
+
+	"avito-intern-test/internal/core"
+	mw "avito-intern-test/internal/handler/middleware"
+	wh "avito-intern-test/internal/handler/webhook"
+)
+
+func RegisterWebhookRoutes(r chi.Router, h *wh.WebhookHandler, idempotency *mw.Idempotency) {
+	r.Route("/webhooks", func(r chi.Router) {
+		r.Use(mw.RequireRole(core.RoleAdmin))
+		// The create response carries the signing secret, so like API key
+		// creation it is never stored for replay.
+		r.Post("/create", h.CreateWebhook)
+		r.Get("/get", h.GetWebhook)
+		r.Get("/list", h.ListWebhooks)
+		r.With(idempotency.Handle).Post("/update", h.UpdateWebhook)
+		r.With(idempotency.Handle).Post("/delete", h.DeleteWebhook)
+		r.Get("/deliveries", h.Deliveries)
+	})
+}
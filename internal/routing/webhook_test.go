@@ -0,0 +1,33 @@
+package routing
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"avito-intern-test/internal/core"
+	mw "avito-intern-test/internal/handler/middleware"
+	wh "avito-intern-test/internal/handler/webhook"
+)
+
+func TestWebhookRoutes_CreateIsNotStored(t *testing.T) {
+	svc := &idempotencyServiceMock{}
+	r := chi.NewRouter()
+	r.Use(withPrincipal(core.Principal{KeyID: 1, Role: core.RoleAdmin}))
+	RegisterWebhookRoutes(r, wh.NewWebhookHandler(nil), mw.NewIdempotency(svc))
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/create", bytes.NewBufferString(`{`))
+	req.Header.Set(mw.IdempotencyKeyHeader, "k1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected the handler to run, got %d", w.Code)
+	}
+	if svc.begun != 0 {
+		t.Fatalf("responses carrying a signing secret must not be stored")
+	}
+}
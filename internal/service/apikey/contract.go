@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+
+	apikeymodel "avito-intern-test/internal/model/apikey"
+	auditmodel "avito-intern-test/internal/model/audit"
+)
+
+type (
+	txManager interface {
+		WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	}
+
+	apiKeyRepository interface {
+		Create(ctx context.Context, key apikeymodel.Key, keyHash string) (apikeymodel.Key, error)
+		GetActiveByHash(ctx context.Context, keyHash string) (apikeymodel.Key, error)
+		List(ctx context.Context) ([]apikeymodel.Key, error)
+		Revoke(ctx context.Context, keyID int64) (apikeymodel.Key, error)
+		RevokeOthers(ctx context.Context, name, keepHash string) ([]apikeymodel.Key, error)
+	}
+
+	teamRepository interface {
+		Exists(ctx context.Context, teamName string) (bool, error)
+	}
+
+	auditRepository interface {
+		Append(ctx context.Context, entry auditmodel.Entry) error
+	}
+)
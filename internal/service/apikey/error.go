@@ -0,0 +1,8 @@
+package service
+
+import "avito-intern-test/internal/core"
+
+var (
+	ErrInvalidKey   = core.Throw(core.ErrorUnauthorized, "invalid API key")
+	ErrTeamNotFound = core.Throw(core.ErrorNotFound, "team not found")
+)
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"avito-intern-test/internal/core"
+	apikeymodel "avito-intern-test/internal/model/apikey"
+	auditmodel "avito-intern-test/internal/model/audit"
+	apikeyrepo "avito-intern-test/internal/repository/apikey"
+)
+
+const keyPrefix = "ak_"
+
+type APIKeyService struct {
+	txManager        txManager
+	apiKeyRepository apiKeyRepository
+	teamRepository   teamRepository
+	auditRepository  auditRepository
+}
+
+func NewAPIKeyService(
+	txManager txManager,
+	apiKeyRepository apiKeyRepository,
+	teamRepository teamRepository,
+	auditRepository auditRepository,
+) *APIKeyService {
+	return &APIKeyService{
+		txManager:        txManager,
+		apiKeyRepository: apiKeyRepository,
+		teamRepository:   teamRepository,
+		auditRepository:  auditRepository,
+	}
+}
+
+// IssueKey creates a key and returns it together with the plaintext token,
+// which is not stored and cannot be recovered later.
+func (s *APIKeyService) IssueKey(ctx context.Context, key apikeymodel.Key) (apikeymodel.Key, string, error) {
+	if key.TeamName != "" {
+		exists, err := s.teamRepository.Exists(ctx, key.TeamName)
+		if err != nil {
+			return apikeymodel.Key{}, "", fmt.Errorf("check team exists: %w", err)
+		}
+		if !exists {
+			return apikeymodel.Key{}, "", ErrTeamNotFound
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return apikeymodel.Key{}, "", fmt.Errorf("generate api key: %w", err)
+	}
+	token := keyPrefix + hex.EncodeToString(secret)
+
+	var created apikeymodel.Key
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.apiKeyRepository.Create(ctx, key, HashKey(token))
+		if err != nil {
+			return err
+		}
+		return s.auditRepository.Append(ctx, auditmodel.New(
+			ctx, auditmodel.ActionAPIKeyIssue, auditmodel.EntityAPIKey, strconv.FormatInt(created.ID, 10),
+			nil, created,
+		))
+	})
+	if err != nil {
+		return apikeymodel.Key{}, "", err
+	}
+	return created, token, nil
+}
+
+// EnsureKey registers a key whose token comes from configuration, so that
+// a fresh deployment has an admin able to issue further keys. Keys registered
+// earlier under the same name are revoked, so rotating the token retires the
+// old one.
+func (s *APIKeyService) EnsureKey(ctx context.Context, key apikeymodel.Key, token string) error {
+	hash := HashKey(token)
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		_, err := s.apiKeyRepository.Create(ctx, key, hash)
+		if err != nil && !errors.Is(err, apikeyrepo.ErrKeyExists) {
+			return err
+		}
+		revoked, err := s.apiKeyRepository.RevokeOthers(ctx, key.Name, hash)
+		if err != nil {
+			return err
+		}
+		for _, old := range revoked {
+			err := s.auditRepository.Append(ctx, auditmodel.New(
+				ctx, auditmodel.ActionAPIKeyRevoke, auditmodel.EntityAPIKey, strconv.FormatInt(old.ID, 10), nil, old,
+			))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *APIKeyService) Authenticate(ctx context.Context, token string) (core.Principal, error) {
+	key, err := s.apiKeyRepository.GetActiveByHash(ctx, HashKey(token))
+	if err != nil {
+		if errors.Is(err, apikeyrepo.ErrKeyNotFound) {
+			return core.Principal{}, ErrInvalidKey
+		}
+		return core.Principal{}, err
+	}
+	return key.Principal(), nil
+}
+
+func (s *APIKeyService) ListKeys(ctx context.Context) ([]apikeymodel.Key, error) {
+	return s.apiKeyRepository.List(ctx)
+}
+
+func (s *APIKeyService) RevokeKey(ctx context.Context, keyID int64) (apikeymodel.Key, error) {
+	var revoked apikeymodel.Key
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		revoked, err = s.apiKeyRepository.Revoke(ctx, keyID)
+		if err != nil {
+			return err
+		}
+		return s.auditRepository.Append(ctx, auditmodel.New(
+			ctx, auditmodel.ActionAPIKeyRevoke, auditmodel.EntityAPIKey, strconv.FormatInt(keyID, 10), nil, revoked,
+		))
+	})
+	if err != nil {
+		return apikeymodel.Key{}, err
+	}
+	return revoked, nil
+}
+
+// HashKey is what gets stored for a token. Tokens carry 256 random bits, so
+// a plain SHA-256 is enough; a slow password hash would only add latency.
+func HashKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"avito-intern-test/internal/core"
+	apikeymodel "avito-intern-test/internal/model/apikey"
+	auditmodel "avito-intern-test/internal/model/audit"
+	apikeyrepo "avito-intern-test/internal/repository/apikey"
+)
+
+type apiKeyRepoMock struct {
+	keys   map[int64]apikeymodel.Key
+	hashes map[string]int64
+}
+
+func newAPIKeyRepoMock() *apiKeyRepoMock {
+	return &apiKeyRepoMock{keys: map[int64]apikeymodel.Key{}, hashes: map[string]int64{}}
+}
+
+func (m *apiKeyRepoMock) Create(_ context.Context, key apikeymodel.Key, keyHash string) (apikeymodel.Key, error) {
+	if _, ok := m.hashes[keyHash]; ok {
+		return apikeymodel.Key{}, apikeyrepo.ErrKeyExists
+	}
+	key.ID = int64(len(m.keys) + 1)
+	key.CreatedAt = time.Now()
+	m.keys[key.ID] = key
+	m.hashes[keyHash] = key.ID
+	return key, nil
+}
+
+func (m *apiKeyRepoMock) GetActiveByHash(_ context.Context, keyHash string) (apikeymodel.Key, error) {
+	id, ok := m.hashes[keyHash]
+	if !ok || m.keys[id].RevokedAt != nil {
+		return apikeymodel.Key{}, apikeyrepo.ErrKeyNotFound
+	}
+	return m.keys[id], nil
+}
+
+func (m *apiKeyRepoMock) List(context.Context) ([]apikeymodel.Key, error) {
+	out := make([]apikeymodel.Key, 0, len(m.keys))
+	for _, k := range m.keys {
+		out = append(out, k)
+	}
+	return out, nil
+}
+
+func (m *apiKeyRepoMock) Revoke(_ context.Context, keyID int64) (apikeymodel.Key, error) {
+	k, ok := m.keys[keyID]
+	if !ok {
+		return apikeymodel.Key{}, apikeyrepo.ErrKeyNotFound
+	}
+	now := time.Now()
+	k.RevokedAt = &now
+	m.keys[keyID] = k
+	return k, nil
+}
+
+func (m *apiKeyRepoMock) RevokeOthers(_ context.Context, name, keepHash string) ([]apikeymodel.Key, error) {
+	var revoked []apikeymodel.Key
+	for hash, id := range m.hashes {
+		k := m.keys[id]
+		if k.Name != name || hash == keepHash || k.RevokedAt != nil {
+			continue
+		}
+		now := time.Now()
+		k.RevokedAt = &now
+		m.keys[id] = k
+		revoked = append(revoked, k)
+	}
+	return revoked, nil
+}
+
+type teamRepoMock struct {
+	teams map[string]bool
+}
+
+func (m teamRepoMock) Exists(_ context.Context, teamName string) (bool, error) {
+	return m.teams[teamName], nil
+}
+
+type txMock struct{}
+
+func (txMock) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type auditMock struct {
+	entries []auditmodel.Entry
+}
+
+func (m *auditMock) Append(_ context.Context, entry auditmodel.Entry) error {
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func newTestService() (*APIKeyService, *apiKeyRepoMock, *auditMock) {
+	repo := newAPIKeyRepoMock()
+	audit := &auditMock{}
+	teams := teamRepoMock{teams: map[string]bool{"backend": true}}
+	return NewAPIKeyService(txMock{}, repo, teams, audit), repo, audit
+}
+
+func TestIssueKey_Authenticate(t *testing.T) {
+	svc, repo, audit := newTestService()
+	ctx := core.WithActor(context.Background(), "root")
+
+	key, token, err := svc.IssueKey(ctx, apikeymodel.Key{Name: "lead", Role: core.RoleTeamLead, TeamName: "backend"})
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if !strings.HasPrefix(token, keyPrefix) {
+		t.Fatalf("token = %q, want %q prefix", token, keyPrefix)
+	}
+	if _, ok := repo.hashes[token]; ok {
+		t.Fatal("plaintext token stored")
+	}
+	if len(audit.entries) != 1 || audit.entries[0].Action != auditmodel.ActionAPIKeyIssue || audit.entries[0].Actor != "root" {
+		t.Fatalf("audit entries = %+v", audit.entries)
+	}
+
+	p, err := svc.Authenticate(ctx, token)
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if p.KeyID != key.ID || p.Role != core.RoleTeamLead || p.TeamName != "backend" {
+		t.Fatalf("principal = %+v", p)
+	}
+
+	if _, err := svc.Authenticate(ctx, token+"x"); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("wrong token: err = %v, want ErrInvalidKey", err)
+	}
+}
+
+func TestIssueKey_UnknownTeam(t *testing.T) {
+	svc, repo, _ := newTestService()
+
+	_, _, err := svc.IssueKey(context.Background(), apikeymodel.Key{Name: "lead", Role: core.RoleTeamLead, TeamName: "ghost"})
+	if !errors.Is(err, ErrTeamNotFound) {
+		t.Fatalf("err = %v, want ErrTeamNotFound", err)
+	}
+	if len(repo.keys) != 0 {
+		t.Fatal("key created for unknown team")
+	}
+}
+
+func TestRevokeKey(t *testing.T) {
+	svc, _, audit := newTestService()
+	ctx := context.Background()
+
+	key, token, err := svc.IssueKey(ctx, apikeymodel.Key{Name: "ci", Role: core.RoleBot})
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	revoked, err := svc.RevokeKey(ctx, key.ID)
+	if err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if revoked.RevokedAt == nil {
+		t.Fatal("revoked_at not set")
+	}
+	if _, err := svc.Authenticate(ctx, token); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("revoked key: err = %v, want ErrInvalidKey", err)
+	}
+	if got := audit.entries[len(audit.entries)-1]; got.Action != auditmodel.ActionAPIKeyRevoke || got.EntityID != "1" {
+		t.Fatalf("last audit entry = %+v", got)
+	}
+}
+
+func TestEnsureKey_Idempotent(t *testing.T) {
+	svc, repo, _ := newTestService()
+	ctx := context.Background()
+	key := apikeymodel.Key{Name: "bootstrap-admin", Role: core.RoleAdmin}
+
+	for range 2 {
+		if err := svc.EnsureKey(ctx, key, "secret"); err != nil {
+			t.Fatalf("ensure: %v", err)
+		}
+	}
+	if len(repo.keys) != 1 {
+		t.Fatalf("keys = %d, want 1", len(repo.keys))
+	}
+	p, err := svc.Authenticate(ctx, "secret")
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if p.Role != core.RoleAdmin {
+		t.Fatalf("role = %s, want admin", p.Role)
+	}
+}
+
+func TestEnsureKey_RotationRevokesPreviousKey(t *testing.T) {
+	svc, _, audit := newTestService()
+	ctx := context.Background()
+	key := apikeymodel.Key{Name: "bootstrap-admin", Role: core.RoleAdmin}
+
+	if err := svc.EnsureKey(ctx, key, "old"); err != nil {
+		t.Fatalf("ensure old: %v", err)
+	}
+	if err := svc.EnsureKey(ctx, key, "new"); err != nil {
+		t.Fatalf("ensure new: %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, "old"); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("rotated-out key must stop working, got %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, "new"); err != nil {
+		t.Fatalf("authenticate new: %v", err)
+	}
+	if len(audit.entries) != 1 || audit.entries[0].Action != auditmodel.ActionAPIKeyRevoke {
+		t.Fatalf("expected the revocation audited, got %+v", audit.entries)
+	}
+}
@@ -0,0 +1,11 @@
+package service
+
+import (
+	"context"
+
+	auditmodel "avito-intern-test/internal/model/audit"
+)
+
+type auditRepository interface {
+	List(ctx context.Context, filter auditmodel.ListFilter) (auditmodel.Page, error)
+}
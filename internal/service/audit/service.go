@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+
+	auditmodel "avito-intern-test/internal/model/audit"
+)
+
+type AuditService struct {
+	auditRepository auditRepository
+}
+
+func NewAuditService(auditRepository auditRepository) *AuditService {
+	return &AuditService{auditRepository: auditRepository}
+}
+
+func (s *AuditService) ListEntries(ctx context.Context, filter auditmodel.ListFilter) (auditmodel.Page, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = auditmodel.DefaultListLimit
+	}
+	filter.Limit = min(filter.Limit, auditmodel.MaxListLimit)
+	return s.auditRepository.List(ctx, filter)
+}
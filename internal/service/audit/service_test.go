@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	auditmodel "avito-intern-test/internal/model/audit"
+)
+
+type auditRepoMock struct {
+	filter auditmodel.ListFilter
+}
+
+func (m *auditRepoMock) List(_ context.Context, filter auditmodel.ListFilter) (auditmodel.Page, error) {
+	m.filter = filter
+	return auditmodel.Page{}, nil
+}
+
+func TestAuditService_ListEntries_ClampsLimit(t *testing.T) {
+	repo := &auditRepoMock{}
+	svc := NewAuditService(repo)
+
+	_, _ = svc.ListEntries(context.Background(), auditmodel.ListFilter{Actor: "alice"})
+	if repo.filter.Limit != auditmodel.DefaultListLimit || repo.filter.Actor != "alice" {
+		t.Fatalf("unexpected filter: %+v", repo.filter)
+	}
+
+	_, _ = svc.ListEntries(context.Background(), auditmodel.ListFilter{Limit: 10000})
+	if repo.filter.Limit != auditmodel.MaxListLimit {
+		t.Fatalf("expected limit to be capped, got %d", repo.filter.Limit)
+	}
+}
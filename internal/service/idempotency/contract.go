@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	idemmodel "avito-intern-test/internal/model/idempotency"
+)
+
+type idempotencyRepository interface {
+	Reserve(ctx context.Context, rec idemmodel.Record, staleBefore, expiredBefore time.Time) (bool, error)
+	Get(ctx context.Context, key, route string) (idemmodel.Record, error)
+	Complete(ctx context.Context, rec idemmodel.Record) error
+	Release(ctx context.Context, key, route, lease string) error
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
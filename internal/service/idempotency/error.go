@@ -0,0 +1,8 @@
+package service
+
+import "avito-intern-test/internal/core"
+
+var (
+	ErrKeyMismatch   = core.Throw(core.ErrorIdempotencyMismatch, "Idempotency-Key was used with a different request")
+	ErrKeyInProgress = core.Throw(core.ErrorIdempotencyInProgress, "request with this Idempotency-Key is still in progress")
+)
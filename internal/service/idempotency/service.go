@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+
+	idemmodel "avito-intern-test/internal/model/idempotency"
+)
+
+type Config struct {
+	// Lease is how long a reservation blocks retries before it is considered
+	// abandoned by a crashed or timed out request.
+	Lease time.Duration
+	// TTL is how long a completed response is replayed.
+	TTL time.Duration
+	// PurgeInterval is how often expired records are deleted.
+	PurgeInterval time.Duration
+}
+
+var DefaultConfig = Config{
+	Lease:         5 * time.Minute,
+	TTL:           24 * time.Hour,
+	PurgeInterval: time.Hour,
+}
+
+type IdempotencyService struct {
+	idempotencyRepository idempotencyRepository
+	cfg                   Config
+}
+
+func NewIdempotencyService(idempotencyRepository idempotencyRepository, cfg Config) *IdempotencyService {
+	return &IdempotencyService{idempotencyRepository: idempotencyRepository, cfg: cfg}
+}
+
+// Begin claims the key for the request. It returns the lease to complete
+// or abandon the key with when the caller should run the request, or the
+// stored record when it must be replayed.
+func (s *IdempotencyService) Begin(
+	ctx context.Context,
+	key, route, requestHash string,
+) (string, *idemmodel.Record, error) {
+	var token [16]byte
+	if _, err := rand.Read(token[:]); err != nil {
+		return "", nil, fmt.Errorf("generate lease: %w", err)
+	}
+	lease := hex.EncodeToString(token[:])
+
+	now := time.Now()
+	reserved, err := s.idempotencyRepository.Reserve(ctx, idemmodel.Record{
+		Key:         key,
+		Route:       route,
+		RequestHash: requestHash,
+		Lease:       lease,
+	}, now.Add(-s.cfg.Lease), now.Add(-s.cfg.TTL))
+	if err != nil {
+		return "", nil, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+	if reserved {
+		return lease, nil, nil
+	}
+
+	rec, err := s.idempotencyRepository.Get(ctx, key, route)
+	if err != nil {
+		return "", nil, fmt.Errorf("get idempotency key: %w", err)
+	}
+	if rec.RequestHash != requestHash {
+		return "", nil, ErrKeyMismatch
+	}
+	if !rec.Completed() {
+		return "", nil, ErrKeyInProgress
+	}
+	return "", &rec, nil
+}
+
+// Complete stores the response under rec.Lease. It fails when the
+// reservation has been taken over since Begin.
+func (s *IdempotencyService) Complete(ctx context.Context, rec idemmodel.Record) error {
+	return s.idempotencyRepository.Complete(ctx, rec)
+}
+
+// Abandon frees a key whose request failed with a server error, so that a
+// retry runs the request again instead of replaying the failure.
+func (s *IdempotencyService) Abandon(ctx context.Context, key, route, lease string) error {
+	return s.idempotencyRepository.Release(ctx, key, route, lease)
+}
+
+// PurgeExpired deletes records older than the TTL and returns how many.
+func (s *IdempotencyService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.idempotencyRepository.DeleteExpired(ctx, time.Now().Add(-s.cfg.TTL))
+}
+
+// Run purges expired records every PurgeInterval until ctx is cancelled.
+func (s *IdempotencyService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PurgeInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.PurgeExpired(ctx); err != nil {
+			log.Printf("purge idempotency keys: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
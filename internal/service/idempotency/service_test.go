@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	idemmodel "avito-intern-test/internal/model/idempotency"
+	idemrepo "avito-intern-test/internal/repository/idempotency"
+)
+
+type idempotencyRepoMock struct {
+	records map[string]idemmodel.Record
+}
+
+func (m *idempotencyRepoMock) Reserve(
+	_ context.Context,
+	rec idemmodel.Record,
+	staleBefore, expiredBefore time.Time,
+) (bool, error) {
+	if m.records == nil {
+		m.records = map[string]idemmodel.Record{}
+	}
+	if old, ok := m.records[rec.Key+rec.Route]; ok {
+		stale := !old.Completed() && old.CreatedAt.Before(staleBefore)
+		expired := old.Completed() && old.CreatedAt.Before(expiredBefore)
+		if !stale && !expired {
+			return false, nil
+		}
+	}
+	rec.CreatedAt = time.Now()
+	m.records[rec.Key+rec.Route] = rec
+	return true, nil
+}
+
+func (m *idempotencyRepoMock) Get(_ context.Context, key, route string) (idemmodel.Record, error) {
+	return m.records[key+route], nil
+}
+
+func (m *idempotencyRepoMock) Complete(_ context.Context, rec idemmodel.Record) error {
+	old := m.records[rec.Key+rec.Route]
+	if old.Lease != rec.Lease || old.Completed() {
+		return idemrepo.ErrLeaseLost
+	}
+	rec.CreatedAt = m.records[rec.Key+rec.Route].CreatedAt
+	m.records[rec.Key+rec.Route] = rec
+	return nil
+}
+
+func (m *idempotencyRepoMock) Release(_ context.Context, key, route, lease string) error {
+	if rec := m.records[key+route]; rec.Lease == lease && !rec.Completed() {
+		delete(m.records, key+route)
+	}
+	return nil
+}
+
+func (m *idempotencyRepoMock) DeleteExpired(_ context.Context, before time.Time) (int64, error) {
+	var n int64
+	for k, rec := range m.records {
+		if rec.CreatedAt.Before(before) {
+			delete(m.records, k)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	repo := &idempotencyRepoMock{}
+	svc := NewIdempotencyService(repo, DefaultConfig)
+	ctx := context.Background()
+
+	lease, rec, err := svc.Begin(ctx, "k", "POST /team/add", "h")
+	if err != nil || rec != nil || lease == "" {
+		t.Fatalf("first call must run the request, got %q %v %v", lease, rec, err)
+	}
+	if _, _, err := svc.Begin(ctx, "k", "POST /team/add", "h"); !errors.Is(err, ErrKeyInProgress) {
+		t.Fatalf("expected ErrKeyInProgress, got %v", err)
+	}
+
+	if err := svc.Complete(ctx, idemmodel.Record{
+		Key: "k", Route: "POST /team/add", RequestHash: "h", Lease: lease, StatusCode: 201, Body: []byte("{}"),
+	}); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	_, rec, err = svc.Begin(ctx, "k", "POST /team/add", "h")
+	if err != nil || rec == nil || rec.StatusCode != 201 {
+		t.Fatalf("expected replay, got %+v %v", rec, err)
+	}
+	if _, _, err := svc.Begin(ctx, "k", "POST /team/add", "other"); !errors.Is(err, ErrKeyMismatch) {
+		t.Fatalf("expected ErrKeyMismatch, got %v", err)
+	}
+}
+
+func TestIdempotencyService_Abandon(t *testing.T) {
+	repo := &idempotencyRepoMock{}
+	svc := NewIdempotencyService(repo, DefaultConfig)
+	ctx := context.Background()
+
+	lease, _, _ := svc.Begin(ctx, "k", "POST /pullRequest/create", "h")
+	if err := svc.Abandon(ctx, "k", "POST /pullRequest/create", lease); err != nil {
+		t.Fatalf("abandon: %v", err)
+	}
+	if _, rec, err := svc.Begin(ctx, "k", "POST /pullRequest/create", "h"); err != nil || rec != nil {
+		t.Fatalf("abandoned key must be claimable again, got %v %v", rec, err)
+	}
+}
+
+func TestIdempotencyService_TakesOverStaleReservation(t *testing.T) {
+	repo := &idempotencyRepoMock{}
+	svc := NewIdempotencyService(repo, DefaultConfig)
+	ctx := context.Background()
+
+	stale, _, _ := svc.Begin(ctx, "k", "POST /pullRequest/merge", "h")
+	rec := repo.records["kPOST /pullRequest/merge"]
+	rec.CreatedAt = time.Now().Add(-DefaultConfig.Lease - time.Second)
+	repo.records["kPOST /pullRequest/merge"] = rec
+
+	lease, replay, err := svc.Begin(ctx, "k", "POST /pullRequest/merge", "h")
+	if err != nil || replay != nil {
+		t.Fatalf("stale reservation must be claimable again, got %v %v", replay, err)
+	}
+
+	err = svc.Complete(ctx, idemmodel.Record{
+		Key: "k", Route: "POST /pullRequest/merge", Lease: stale, StatusCode: 200,
+	})
+	if !errors.Is(err, idemrepo.ErrLeaseLost) {
+		t.Fatalf("the previous holder must not complete the key, got %v", err)
+	}
+	if err := svc.Abandon(ctx, "k", "POST /pullRequest/merge", stale); err != nil {
+		t.Fatalf("abandon: %v", err)
+	}
+	if _, ok := repo.records["kPOST /pullRequest/merge"]; !ok {
+		t.Fatal("the previous holder must not release the new reservation")
+	}
+	if err := svc.Complete(ctx, idemmodel.Record{
+		Key: "k", Route: "POST /pullRequest/merge", Lease: lease, StatusCode: 200,
+	}); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+}
+
+func TestIdempotencyService_PurgeExpired(t *testing.T) {
+	repo := &idempotencyRepoMock{}
+	svc := NewIdempotencyService(repo, DefaultConfig)
+	ctx := context.Background()
+
+	_, _, _ = svc.Begin(ctx, "old", "POST /team/add", "h")
+	_, _, _ = svc.Begin(ctx, "new", "POST /team/add", "h")
+	rec := repo.records["oldPOST /team/add"]
+	rec.CreatedAt = time.Now().Add(-DefaultConfig.TTL - time.Second)
+	repo.records["oldPOST /team/add"] = rec
+
+	n, err := svc.PurgeExpired(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("expected one purged record, got %d %v", n, err)
+	}
+	if _, ok := repo.records["newPOST /team/add"]; !ok {
+		t.Fatal("fresh record must survive the purge")
+	}
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	eventmodel "avito-intern-test/internal/model/event"
+)
+
+type (
+	txManager interface {
+		WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	}
+
+	outboxRepository interface {
+		ClaimDue(ctx context.Context, limit int, claimedUntil time.Time) ([]eventmodel.Pending, error)
+		MarkPublished(ctx context.Context, eventID string) error
+		MarkFailed(ctx context.Context, eventID, reason string, retryAt time.Time) error
+		MarkDead(ctx context.Context, eventID, reason string) error
+	}
+)
+
+// EventPublisher delivers an event downstream and returns once it is
+// delivered. A nil error means the event will not be offered again; an error
+// makes the relay offer it again after a backoff.
+type EventPublisher interface {
+	Publish(ctx context.Context, event eventmodel.Event) error
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	eventmodel "avito-intern-test/internal/model/event"
+)
+
+type RelayConfig struct {
+	Interval  time.Duration
+	BatchSize int
+	// ClaimTimeout hides a claimed batch from other relays while it is being
+	// delivered. It should exceed the time a batch takes to publish; a batch
+	// that overruns it may be published twice.
+	ClaimTimeout time.Duration
+	// MaxAttempts is how many failed deliveries an event gets before the
+	// relay gives up on it.
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+var DefaultRelayConfig = RelayConfig{
+	Interval:       time.Second,
+	BatchSize:      100,
+	ClaimTimeout:   5 * time.Minute,
+	MaxAttempts:    10,
+	InitialBackoff: 5 * time.Second,
+	MaxBackoff:     30 * time.Minute,
+}
+
+// Relay moves events from the outbox to a publisher. An event is marked
+// delivered only after the publisher accepted it, so delivery is
+// at-least-once: a crash in between makes the next run publish it again.
+type Relay struct {
+	txManager        txManager
+	outboxRepository outboxRepository
+	publisher        EventPublisher
+	cfg              RelayConfig
+}
+
+func NewRelay(
+	txManager txManager,
+	outboxRepository outboxRepository,
+	publisher EventPublisher,
+	cfg RelayConfig,
+) *Relay {
+	return &Relay{
+		txManager:        txManager,
+		outboxRepository: outboxRepository,
+		publisher:        publisher,
+		cfg:              cfg,
+	}
+}
+
+// Run relays events until ctx is cancelled. A fully published batch is
+// followed by another one right away; otherwise the relay sleeps for the
+// interval.
+func (r *Relay) Run(ctx context.Context) {
+	for {
+		n, err := r.RelayOnce(ctx)
+		if err != nil {
+			log.Printf("outbox relay: %v", err)
+		}
+		if err == nil && n == r.cfg.BatchSize {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.cfg.Interval):
+		}
+	}
+}
+
+// RelayOnce publishes one batch of due events and returns how many of them
+// were published. The batch is claimed in one statement and the results are
+// stored in a short transaction afterwards, so no row lock is held while
+// publishing. A failed event is retried with exponential backoff until it
+// runs out of attempts.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	events, err := r.outboxRepository.ClaimDue(ctx, r.cfg.BatchSize, time.Now().Add(r.cfg.ClaimTimeout))
+	if err != nil {
+		return 0, fmt.Errorf("claim due events: %w", err)
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	failures := make([]error, len(events))
+	for i, event := range events {
+		failures[i] = r.publisher.Publish(ctx, event.Event)
+	}
+
+	var n int
+	err = r.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		for i, event := range events {
+			if failures[i] == nil {
+				if err := r.outboxRepository.MarkPublished(ctx, event.ID); err != nil {
+					return fmt.Errorf("mark event %s published: %w", event.ID, err)
+				}
+				n++
+				continue
+			}
+			if err := r.markFailed(ctx, event, failures[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (r *Relay) markFailed(ctx context.Context, event eventmodel.Pending, cause error) error {
+	attempts := event.Attempts + 1
+	if attempts >= r.cfg.MaxAttempts {
+		log.Printf("outbox relay: giving up on event %s after %d attempts: %v", event.ID, attempts, cause)
+		if err := r.outboxRepository.MarkDead(ctx, event.ID, cause.Error()); err != nil {
+			return fmt.Errorf("mark event %s dead: %w", event.ID, err)
+		}
+		return nil
+	}
+	retryAt := time.Now().Add(r.backoff(attempts))
+	if err := r.outboxRepository.MarkFailed(ctx, event.ID, cause.Error(), retryAt); err != nil {
+		return fmt.Errorf("mark event %s failed: %w", event.ID, err)
+	}
+	return nil
+}
+
+// backoff is the wait before the next delivery of an event that failed
+// attempts times.
+func (r *Relay) backoff(attempts int) time.Duration {
+	backoff := r.cfg.InitialBackoff
+	for range attempts - 1 {
+		if backoff >= r.cfg.MaxBackoff {
+			break
+		}
+		backoff *= 2
+	}
+	return min(backoff, r.cfg.MaxBackoff)
+}
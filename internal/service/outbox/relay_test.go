@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	eventmodel "avito-intern-test/internal/model/event"
+)
+
+type txKey struct{}
+
+type txMock struct {
+	calls int
+}
+
+func (m *txMock) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	return fn(context.WithValue(ctx, txKey{}, true))
+}
+
+type outboxRepoMock struct {
+	pending   []eventmodel.Pending
+	published []string
+	failed    map[string]time.Time
+	dead      []string
+}
+
+func (m *outboxRepoMock) ClaimDue(_ context.Context, limit int, _ time.Time) ([]eventmodel.Pending, error) {
+	return m.pending[:min(limit, len(m.pending))], nil
+}
+
+func (m *outboxRepoMock) MarkPublished(ctx context.Context, eventID string) error {
+	if ctx.Value(txKey{}) == nil {
+		return errors.New("mark outside of transaction")
+	}
+	m.published = append(m.published, eventID)
+	return nil
+}
+
+func (m *outboxRepoMock) MarkFailed(_ context.Context, eventID, _ string, retryAt time.Time) error {
+	m.failed[eventID] = retryAt
+	return nil
+}
+
+func (m *outboxRepoMock) MarkDead(_ context.Context, eventID, _ string) error {
+	m.dead = append(m.dead, eventID)
+	return nil
+}
+
+type publisherMock struct {
+	fail  map[string]bool
+	seen  []string
+	txCtx bool
+}
+
+func (m *publisherMock) Publish(ctx context.Context, event eventmodel.Event) error {
+	m.seen = append(m.seen, event.ID)
+	if ctx.Value(txKey{}) != nil {
+		m.txCtx = true
+	}
+	if m.fail[event.ID] {
+		return errors.New("receiver down")
+	}
+	return nil
+}
+
+var testRelayConfig = RelayConfig{
+	Interval:       time.Millisecond,
+	BatchSize:      10,
+	ClaimTimeout:   time.Minute,
+	MaxAttempts:    3,
+	InitialBackoff: time.Second,
+	MaxBackoff:     time.Minute,
+}
+
+func TestRelay_RelayOnce(t *testing.T) {
+	repo := &outboxRepoMock{
+		pending: []eventmodel.Pending{
+			{Event: eventmodel.Event{ID: "e1", Type: eventmodel.TypePullRequestCreated}},
+			{Event: eventmodel.Event{ID: "e2", Type: eventmodel.TypePullRequestMerged}},
+			{Event: eventmodel.Event{ID: "e3", Type: eventmodel.TypeTeamCreated}},
+		},
+		failed: map[string]time.Time{},
+	}
+	pub := &publisherMock{fail: map[string]bool{"e2": true}}
+	tx := &txMock{}
+	r := NewRelay(tx, repo, pub, testRelayConfig)
+
+	n, err := r.RelayOnce(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if n != 2 || len(repo.published) != 2 || repo.published[0] != "e1" || repo.published[1] != "e3" {
+		t.Fatalf("unexpected published events: n=%d %v", n, repo.published)
+	}
+	if retryAt, ok := repo.failed["e2"]; !ok || time.Until(retryAt) <= 0 {
+		t.Fatalf("expected e2 scheduled for a later retry, got %v", repo.failed)
+	}
+	if pub.txCtx {
+		t.Fatalf("events must be published outside of a transaction")
+	}
+	if tx.calls != 1 {
+		t.Fatalf("expected one transaction, got %d", tx.calls)
+	}
+}
+
+func TestRelay_GivesUpAfterMaxAttempts(t *testing.T) {
+	repo := &outboxRepoMock{
+		pending: []eventmodel.Pending{
+			{Event: eventmodel.Event{ID: "e1"}, Attempts: testRelayConfig.MaxAttempts - 1},
+		},
+		failed: map[string]time.Time{},
+	}
+	r := NewRelay(&txMock{}, repo, &publisherMock{fail: map[string]bool{"e1": true}}, testRelayConfig)
+
+	if _, err := r.RelayOnce(context.Background()); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(repo.dead) != 1 || len(repo.failed) != 0 {
+		t.Fatalf("expected e1 given up on, dead=%v failed=%v", repo.dead, repo.failed)
+	}
+}
+
+func TestRelay_Backoff(t *testing.T) {
+	r := NewRelay(&txMock{}, &outboxRepoMock{}, &publisherMock{}, testRelayConfig)
+	for attempts, want := range map[int]time.Duration{
+		1:  time.Second,
+		2:  2 * time.Second,
+		3:  4 * time.Second,
+		20: time.Minute,
+	} {
+		if got := r.backoff(attempts); got != want {
+			t.Fatalf("backoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+func TestRelay_RunStopsOnCancel(t *testing.T) {
+	repo := &outboxRepoMock{failed: map[string]time.Time{}}
+	r := NewRelay(&txMock{}, repo, &publisherMock{}, testRelayConfig)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		r.Run(ctx)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("relay did not stop after cancel")
+	}
+}
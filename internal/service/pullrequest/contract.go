@@ -3,7 +3,10 @@ package service
 import (
 	"context"
 
+	auditmodel "avito-intern-test/internal/model/audit"
+	eventmodel "avito-intern-test/internal/model/event"
 	prmodel "avito-intern-test/internal/model/pullrequest"
+	teammodel "avito-intern-test/internal/model/team"
 	usermodel "avito-intern-test/internal/model/user"
 )
 
@@ -13,14 +16,34 @@ type (
 		Create(ctx context.Context, pr prmodel.PullRequest) error
 		GetByID(ctx context.Context, prID string) (prmodel.PullRequest, error)
 		Update(ctx context.Context, pr prmodel.PullRequest) error
+		ReplaceReviewer(ctx context.Context, pr prmodel.PullRequest, oldUserID, newUserID string) error
+		History(ctx context.Context, prID string) ([]prmodel.ReviewerAssignment, error)
+		SetVerdict(ctx context.Context, prID, userID string, verdict prmodel.ReviewVerdict) error
+		CountApprovals(ctx context.Context, prID string) (int, error)
+		OpenReviewsOf(ctx context.Context, userIDs []string) ([]prmodel.PullRequest, error)
+		OpenReviewCounts(ctx context.Context, userIDs []string) (map[string]int, error)
+		List(ctx context.Context, filter prmodel.ListFilter) (prmodel.ListPage, error)
 	}
 
 	teamRepository interface {
 		Exists(ctx context.Context, teamName string) (bool, error)
+		GetSettings(ctx context.Context, teamName string) (teammodel.Settings, error)
 	}
 
 	userRepository interface {
 		GetByID(ctx context.Context, userID string) (usermodel.User, error)
 		GetByTeam(ctx context.Context, teamName string) ([]usermodel.User, error)
 	}
+
+	outboxRepository interface {
+		Add(ctx context.Context, event eventmodel.Event) error
+	}
+
+	auditRepository interface {
+		Append(ctx context.Context, entry auditmodel.Entry) error
+	}
+
+	txManager interface {
+		WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	}
 )
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+
+	teammodel "avito-intern-test/internal/model/team"
+)
+
+type ReviewerCandidate struct {
+	UserID   string
+	TeamName string
+	Workload int
+}
+
+type ReviewerSelector interface {
+	Select(candidates []ReviewerCandidate, limit int) []string
+}
+
+type ReviewerSelectors map[teammodel.ReviewerStrategy]ReviewerSelector
+
+func NewReviewerSelectors(r *rand.Rand) ReviewerSelectors {
+	return ReviewerSelectors{
+		teammodel.ReviewerStrategyRandom:      NewRandomSelector(rand.New(rand.NewSource(r.Int63()))),
+		teammodel.ReviewerStrategyRoundRobin:  NewRoundRobinSelector(),
+		teammodel.ReviewerStrategyLeastLoaded: NewLeastLoadedSelector(rand.New(rand.NewSource(r.Int63()))),
+		teammodel.ReviewerStrategyWeighted:    NewWeightedSelector(rand.New(rand.NewSource(r.Int63()))),
+	}
+}
+
+func (s ReviewerSelectors) For(strategy teammodel.ReviewerStrategy) ReviewerSelector {
+	if selector, ok := s[strategy]; ok {
+		return selector
+	}
+	return s[teammodel.DefaultReviewerStrategy]
+}
+
+type lockedRand struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func (l *lockedRand) Shuffle(n int, swap func(i, j int)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rand.Shuffle(n, swap)
+}
+
+func (l *lockedRand) Float64() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rand.Float64()
+}
+
+type RandomSelector struct {
+	rand *lockedRand
+}
+
+func NewRandomSelector(r *rand.Rand) *RandomSelector {
+	return &RandomSelector{rand: &lockedRand{rand: r}}
+}
+
+func (s *RandomSelector) Select(candidates []ReviewerCandidate, limit int) []string {
+	tmp := make([]ReviewerCandidate, len(candidates))
+	copy(tmp, candidates)
+
+	s.rand.Shuffle(len(tmp), func(i, j int) {
+		tmp[i], tmp[j] = tmp[j], tmp[i]
+	})
+
+	return firstIDs(tmp, limit)
+}
+
+type LeastLoadedSelector struct {
+	rand *lockedRand
+}
+
+func NewLeastLoadedSelector(r *rand.Rand) *LeastLoadedSelector {
+	return &LeastLoadedSelector{rand: &lockedRand{rand: r}}
+}
+
+func (s *LeastLoadedSelector) Select(candidates []ReviewerCandidate, limit int) []string {
+	tmp := make([]ReviewerCandidate, len(candidates))
+	copy(tmp, candidates)
+
+	s.rand.Shuffle(len(tmp), func(i, j int) {
+		tmp[i], tmp[j] = tmp[j], tmp[i]
+	})
+	sort.SliceStable(tmp, func(i, j int) bool {
+		return tmp[i].Workload < tmp[j].Workload
+	})
+
+	return firstIDs(tmp, limit)
+}
+
+// WeightedSelector draws candidates without replacement with a probability
+// inversely proportional to their open review workload.
+type WeightedSelector struct {
+	rand *lockedRand
+}
+
+func NewWeightedSelector(r *rand.Rand) *WeightedSelector {
+	return &WeightedSelector{rand: &lockedRand{rand: r}}
+}
+
+func (s *WeightedSelector) Select(candidates []ReviewerCandidate, limit int) []string {
+	if len(candidates) == 0 || limit <= 0 {
+		return nil
+	}
+
+	tmp := make([]ReviewerCandidate, len(candidates))
+	copy(tmp, candidates)
+
+	result := make([]string, 0, limit)
+	for len(result) < limit && len(tmp) > 0 {
+		var total float64
+		for _, c := range tmp {
+			total += weight(c)
+		}
+
+		x := s.rand.Float64() * total
+		idx := len(tmp) - 1
+		for i, c := range tmp {
+			x -= weight(c)
+			if x < 0 {
+				idx = i
+				break
+			}
+		}
+
+		result = append(result, tmp[idx].UserID)
+		tmp = append(tmp[:idx], tmp[idx+1:]...)
+	}
+
+	return result
+}
+
+func weight(c ReviewerCandidate) float64 {
+	return 1 / float64(1+c.Workload)
+}
+
+// RoundRobinSelector walks team members in user_id order, remembering
+// where it stopped for every team.
+type RoundRobinSelector struct {
+	mu   sync.Mutex
+	last map[string]string
+}
+
+func NewRoundRobinSelector() *RoundRobinSelector {
+	return &RoundRobinSelector{last: map[string]string{}}
+}
+
+func (s *RoundRobinSelector) Select(candidates []ReviewerCandidate, limit int) []string {
+	if len(candidates) == 0 || limit <= 0 {
+		return nil
+	}
+
+	tmp := make([]ReviewerCandidate, len(candidates))
+	copy(tmp, candidates)
+	sort.Slice(tmp, func(i, j int) bool {
+		return tmp[i].UserID < tmp[j].UserID
+	})
+	team := tmp[0].TeamName
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	last := s.last[team]
+	start := sort.Search(len(tmp), func(i int) bool {
+		return tmp[i].UserID > last
+	})
+
+	n := min(limit, len(tmp))
+	result := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		result = append(result, tmp[(start+i)%len(tmp)].UserID)
+	}
+	s.last[team] = result[n-1]
+
+	return result
+}
+
+func firstIDs(candidates []ReviewerCandidate, limit int) []string {
+	if len(candidates) == 0 || limit <= 0 {
+		return nil
+	}
+	result := make([]string, 0, min(limit, len(candidates)))
+	for i := 0; i < limit && i < len(candidates); i++ {
+		result = append(result, candidates[i].UserID)
+	}
+	return result
+}
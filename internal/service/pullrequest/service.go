@@ -3,35 +3,59 @@ package service
 import (
 	"context"
 	"fmt"
-	"math/rand"
+	"slices"
 	"time"
 
 	"avito-intern-test/internal/core"
+	"avito-intern-test/internal/metrics"
+	auditmodel "avito-intern-test/internal/model/audit"
+	eventmodel "avito-intern-test/internal/model/event"
 	prmodel "avito-intern-test/internal/model/pullrequest"
+	teammodel "avito-intern-test/internal/model/team"
 	usermodel "avito-intern-test/internal/model/user"
+	"avito-intern-test/internal/tracing"
 )
 
 type PRService struct {
+	txManager             txManager
 	userRepository        userRepository
 	teamRepository        teamRepository
 	pullRequestRepository pullrequestRepository
-	rand                  *rand.Rand
+	selectors             ReviewerSelectors
+	outboxRepository      outboxRepository
+	auditRepository       auditRepository
 }
 
 func NewPRService(
+	txManager txManager,
 	userRepository userRepository,
 	teamRepository teamRepository,
 	pullRequestRepository pullrequestRepository,
+	selectors ReviewerSelectors,
+	outboxRepository outboxRepository,
+	auditRepository auditRepository,
 ) *PRService {
 	return &PRService{
+		txManager:             txManager,
 		userRepository:        userRepository,
 		teamRepository:        teamRepository,
 		pullRequestRepository: pullRequestRepository,
-		rand:                  rand.New(rand.NewSource(time.Now().UnixNano())),
+		selectors:             selectors,
+		outboxRepository:      outboxRepository,
+		auditRepository:       auditRepository,
 	}
 }
 
-func (s *PRService) CreatePR(ctx context.Context, pullRequestID string, pullRequestName string, authorID string) (*prmodel.PullRequest, error) {
+func (s *PRService) CreatePR(
+	ctx context.Context,
+	pullRequestID string,
+	pullRequestName string,
+	authorID string,
+	draft bool,
+) (_ *prmodel.PullRequest, err error) {
+	ctx, span := tracing.Start(ctx, "PRService.CreatePR")
+	defer func() { tracing.End(span, err) }()
+
 	exists, err := s.pullRequestRepository.Exists(ctx, pullRequestID)
 	if err != nil {
 		return nil, fmt.Errorf("check PR exists: %w", err)
@@ -57,43 +81,60 @@ func (s *PRService) CreatePR(ctx context.Context, pullRequestID string, pullRequ
 		return nil, core.Throw(core.ErrorNotFound, "author team not found")
 	}
 
-	users, err := s.userRepository.GetByTeam(ctx, author.TeamName)
-	if err != nil {
-		return nil, fmt.Errorf("get team members: %w", err)
-	}
-
-	var candidates []usermodel.User
-	for _, u := range users {
-		if u.UserID == authorID {
-			continue
-		}
-		if !u.IsActive {
-			continue
+	status := prmodel.PullRequestStatusOpen
+	var reviewers, fallbacks []string
+	if draft {
+		status = prmodel.PullRequestStatusDraft
+	} else {
+		reviewers, fallbacks, err = s.initialReviewers(ctx, author)
+		if err != nil {
+			return nil, err
 		}
-		candidates = append(candidates, u)
 	}
 
-	reviewers := chooseReviewers(candidates, 2, s.rand)
-
 	now := time.Now().UTC()
 	pr := prmodel.PullRequest{
 		PullRequestID:     pullRequestID,
 		PullRequestName:   pullRequestName,
 		AuthorID:          authorID,
-		Status:            prmodel.PullRequestStatusOpen,
+		Status:            status,
 		AssignedReviewers: reviewers,
 		CreatedAt:         now,
 		MergedAt:          nil,
+		FallbackTeams:     fallbacks,
 	}
 
-	if err := s.pullRequestRepository.Create(ctx, pr); err != nil {
-		return nil, fmt.Errorf("create PR: %w", err)
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.pullRequestRepository.Create(ctx, pr); err != nil {
+			return fmt.Errorf("create PR: %w", err)
+		}
+		if err := s.auditRepository.Append(ctx, auditmodel.New(
+			ctx, auditmodel.ActionPullRequestCreate, auditmodel.EntityPullRequest, pr.PullRequestID, nil, pr,
+		)); err != nil {
+			return err
+		}
+		if err := s.record(ctx, eventmodel.TypePullRequestCreated, prPayload(pr)); err != nil {
+			return err
+		}
+		if len(pr.AssignedReviewers) > 0 {
+			return s.record(ctx, eventmodel.TypeReviewersAssigned, prPayload(pr))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !draft {
+		metrics.ReviewersAssigned.Observe(float64(len(reviewers)))
 	}
 
 	return &pr, nil
 }
 
-func (s *PRService) MergePR(ctx context.Context, id string) (*prmodel.PullRequest, error) {
+func (s *PRService) MergePR(ctx context.Context, id string) (_ *prmodel.PullRequest, err error) {
+	ctx, span := tracing.Start(ctx, "PRService.MergePR")
+	defer func() { tracing.End(span, err) }()
+
 	pr, err := s.pullRequestRepository.GetByID(ctx, id)
 	if err != nil {
 		return nil, core.Throw(core.ErrorNotFound, "pr not found")
@@ -103,18 +144,212 @@ func (s *PRService) MergePR(ctx context.Context, id string) (*prmodel.PullReques
 		return &pr, nil
 	}
 
+	before := snapshot(pr)
+	if err := transition(&pr, prmodel.PullRequestStatusMerged); err != nil {
+		return nil, err
+	}
+	if err := s.checkApprovals(ctx, pr); err != nil {
+		return nil, err
+	}
 	now := time.Now().UTC()
-	pr.Status = prmodel.PullRequestStatusMerged
 	pr.MergedAt = &now
 
-	if err := s.pullRequestRepository.Update(ctx, pr); err != nil {
-		return nil, fmt.Errorf("update PR: %w", err)
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.pullRequestRepository.Update(ctx, pr); err != nil {
+			return fmt.Errorf("update PR: %w", err)
+		}
+		if err := s.auditRepository.Append(ctx, auditmodel.New(
+			ctx, auditmodel.ActionPullRequestMerge, auditmodel.EntityPullRequest, pr.PullRequestID, before, pr,
+		)); err != nil {
+			return err
+		}
+		return s.record(ctx, eventmodel.TypePullRequestMerged, prPayload(pr))
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &pr, nil
 }
 
-func (s *PRService) ReassignReviewer(ctx context.Context, prID, oldUserID string) (*prmodel.PullRequest, string, error) {
+func (s *PRService) SubmitReview(
+	ctx context.Context,
+	prID string,
+	reviewerID string,
+	verdict prmodel.ReviewVerdict,
+) (_ *prmodel.PullRequest, err error) {
+	ctx, span := tracing.Start(ctx, "PRService.SubmitReview")
+	defer func() { tracing.End(span, err) }()
+
+	pr, err := s.pullRequestRepository.GetByID(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("get PR: %w", err)
+	}
+
+	if pr.Status == prmodel.PullRequestStatusMerged {
+		return nil, core.Throw(core.ErrorPRMerged, "cannot review merged PR")
+	}
+	if pr.Status != prmodel.PullRequestStatusOpen {
+		return nil, core.NewDomainError(core.ErrorPRNotOpen, "cannot review PR that is not open").
+			WithDetails(map[string]any{"pull_request_id": prID, "status": string(pr.Status)})
+	}
+	if !slices.Contains(pr.AssignedReviewers, reviewerID) {
+		return nil, core.NewDomainError(core.ErrorNotAssigned, "reviewer is not assigned to this PR").
+			WithDetails(map[string]any{"pull_request_id": prID, "user_id": reviewerID})
+	}
+
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.pullRequestRepository.SetVerdict(ctx, prID, reviewerID, verdict); err != nil {
+			return fmt.Errorf("set verdict: %w", err)
+		}
+		return s.auditRepository.Append(ctx, auditmodel.New(
+			ctx, auditmodel.ActionPullRequestReview, auditmodel.EntityPullRequest, prID, nil, map[string]string{
+				"user_id": reviewerID,
+				"verdict": string(verdict),
+			},
+		))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+func (s *PRService) MarkReady(ctx context.Context, id string) (_ *prmodel.PullRequest, err error) {
+	ctx, span := tracing.Start(ctx, "PRService.MarkReady")
+	defer func() { tracing.End(span, err) }()
+
+	pr, err := s.pullRequestRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get PR: %w", err)
+	}
+
+	if pr.Status == prmodel.PullRequestStatusOpen {
+		return &pr, nil
+	}
+
+	before := snapshot(pr)
+	if err := transition(&pr, prmodel.PullRequestStatusOpen); err != nil {
+		return nil, err
+	}
+	missing := len(pr.AssignedReviewers) == 0
+	if err := s.assignIfMissing(ctx, &pr); err != nil {
+		return nil, err
+	}
+	assigned := missing && len(pr.AssignedReviewers) > 0
+
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.pullRequestRepository.Update(ctx, pr); err != nil {
+			return fmt.Errorf("update PR: %w", err)
+		}
+		if err := s.auditRepository.Append(ctx, auditmodel.New(
+			ctx, auditmodel.ActionPullRequestReady, auditmodel.EntityPullRequest, pr.PullRequestID, before, pr,
+		)); err != nil {
+			return err
+		}
+		if assigned {
+			return s.record(ctx, eventmodel.TypeReviewersAssigned, prPayload(pr))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if missing {
+		metrics.ReviewersAssigned.Observe(float64(len(pr.AssignedReviewers)))
+	}
+
+	return &pr, nil
+}
+
+func (s *PRService) ClosePR(ctx context.Context, id string) (_ *prmodel.PullRequest, err error) {
+	ctx, span := tracing.Start(ctx, "PRService.ClosePR")
+	defer func() { tracing.End(span, err) }()
+
+	pr, err := s.pullRequestRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get PR: %w", err)
+	}
+
+	if pr.Status == prmodel.PullRequestStatusClosed {
+		return &pr, nil
+	}
+
+	before := snapshot(pr)
+	if err := transition(&pr, prmodel.PullRequestStatusClosed); err != nil {
+		return nil, err
+	}
+
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.pullRequestRepository.Update(ctx, pr); err != nil {
+			return fmt.Errorf("update PR: %w", err)
+		}
+		return s.auditRepository.Append(ctx, auditmodel.New(
+			ctx, auditmodel.ActionPullRequestClose, auditmodel.EntityPullRequest, pr.PullRequestID, before, pr,
+		))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+func (s *PRService) ReopenPR(ctx context.Context, id string) (_ *prmodel.PullRequest, err error) {
+	ctx, span := tracing.Start(ctx, "PRService.ReopenPR")
+	defer func() { tracing.End(span, err) }()
+
+	pr, err := s.pullRequestRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get PR: %w", err)
+	}
+
+	if pr.Status == prmodel.PullRequestStatusOpen {
+		return &pr, nil
+	}
+	if pr.Status != prmodel.PullRequestStatusClosed {
+		return nil, invalidTransition(pr, prmodel.PullRequestStatusOpen)
+	}
+
+	before := snapshot(pr)
+	if err := transition(&pr, prmodel.PullRequestStatusOpen); err != nil {
+		return nil, err
+	}
+	missing := len(pr.AssignedReviewers) == 0
+	if err := s.assignIfMissing(ctx, &pr); err != nil {
+		return nil, err
+	}
+	assigned := missing && len(pr.AssignedReviewers) > 0
+
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.pullRequestRepository.Update(ctx, pr); err != nil {
+			return fmt.Errorf("update PR: %w", err)
+		}
+		if err := s.auditRepository.Append(ctx, auditmodel.New(
+			ctx, auditmodel.ActionPullRequestReopen, auditmodel.EntityPullRequest, pr.PullRequestID, before, pr,
+		)); err != nil {
+			return err
+		}
+		if assigned {
+			return s.record(ctx, eventmodel.TypeReviewersAssigned, prPayload(pr))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if missing {
+		metrics.ReviewersAssigned.Observe(float64(len(pr.AssignedReviewers)))
+	}
+
+	return &pr, nil
+}
+
+func (s *PRService) ReassignReviewer(ctx context.Context, prID, oldUserID string) (_ *prmodel.PullRequest, _ string, err error) {
+	ctx, span := tracing.Start(ctx, "PRService.ReassignReviewer")
+	defer func() { tracing.End(span, err) }()
+
 	pr, err := s.pullRequestRepository.GetByID(ctx, prID)
 	if err != nil {
 		return nil, "", fmt.Errorf("get PR: %w", err)
@@ -123,6 +358,10 @@ func (s *PRService) ReassignReviewer(ctx context.Context, prID, oldUserID string
 	if pr.Status == prmodel.PullRequestStatusMerged {
 		return nil, "", core.Throw(core.ErrorPRMerged, "cannot reassign on merged PR")
 	}
+	if pr.Status != prmodel.PullRequestStatusOpen {
+		return nil, "", core.NewDomainError(core.ErrorPRNotOpen, "cannot reassign on PR that is not open").
+			WithDetails(map[string]any{"pull_request_id": prID, "status": string(pr.Status)})
+	}
 
 	idx := -1
 	for i, id := range pr.AssignedReviewers {
@@ -132,7 +371,8 @@ func (s *PRService) ReassignReviewer(ctx context.Context, prID, oldUserID string
 		}
 	}
 	if idx == -1 {
-		return nil, "", core.Throw(core.ErrorNotAssigned, "reviewer is not assigned to this PR")
+		return nil, "", core.NewDomainError(core.ErrorNotAssigned, "reviewer is not assigned to this PR").
+			WithDetails(map[string]any{"pull_request_id": prID, "user_id": oldUserID})
 	}
 
 	oldUser, err := s.userRepository.GetByID(ctx, oldUserID)
@@ -143,74 +383,482 @@ func (s *PRService) ReassignReviewer(ctx context.Context, prID, oldUserID string
 	if oldUser.TeamName == "" {
 		return nil, "", core.Throw(core.ErrorNotFound, "reviewer team not found")
 	}
+	if err := core.AuthorizeTeam(ctx, oldUser.TeamName); err != nil {
+		return nil, "", err
+	}
 
-	users, err := s.userRepository.GetByTeam(ctx, oldUser.TeamName)
+	settings, err := s.teamRepository.GetSettings(ctx, oldUser.TeamName)
 	if err != nil {
-		return nil, "", fmt.Errorf("get team members: %w", err)
+		return nil, "", fmt.Errorf("get team settings: %w", err)
 	}
 
-	current := make(map[string]struct{}, len(pr.AssignedReviewers))
-	for _, id := range pr.AssignedReviewers {
-		current[id] = struct{}{}
-	}
-	delete(current, oldUserID)
 	authorID := pr.AuthorID
+	missing := max(0, settings.ReviewersRequired()-len(pr.AssignedReviewers))
+	picked, fallbacks, err := s.drawReviewers(ctx, oldUser.TeamName, settings, 1+missing, func(u usermodel.User) bool {
+		return u.UserID == oldUserID || u.UserID == authorID || slices.Contains(pr.AssignedReviewers, u.UserID)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if len(picked) == 0 {
+		metrics.NoCandidate.WithLabelValues(oldUser.TeamName).Inc()
+		return nil, "", core.NewDomainError(core.ErrorNoCandidate, "no active replacement candidate in team").
+			WithDetails(map[string]any{
+				"pull_request_id": prID,
+				"team_name":       oldUser.TeamName,
+				"fallback_teams":  settings.FallbackTeams,
+			})
+	}
+	newUser := picked[0]
 
-	var candidates []usermodel.User
-	for _, u := range users {
-		if !u.IsActive {
-			continue
+	before := snapshot(pr)
+	pr.AssignedReviewers[idx] = newUser
+	pr.AssignedReviewers = append(pr.AssignedReviewers, picked[1:]...)
+	pr.FallbackTeams = fallbacks
+
+	payload := prPayload(pr)
+	payload.OldReviewerID = oldUserID
+	payload.NewReviewerID = newUser
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.pullRequestRepository.ReplaceReviewer(ctx, pr, oldUserID, newUser); err != nil {
+			return fmt.Errorf("update PR after reassign: %w", err)
 		}
-		if u.UserID == oldUserID {
-			continue
+		if err := s.auditRepository.Append(ctx, auditmodel.New(
+			ctx, auditmodel.ActionPullRequestReassign, auditmodel.EntityPullRequest, prID, before, pr,
+		)); err != nil {
+			return err
 		}
-		if u.UserID == authorID {
-			continue
+		return s.record(ctx, eventmodel.TypeReviewerReassigned, payload)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	metrics.CountReassignments(oldUser.TeamName, 1)
+
+	return &pr, newUser, nil
+}
+
+func (s *PRService) GetPR(ctx context.Context, prID string) (_ prmodel.PullRequestDetails, err error) {
+	ctx, span := tracing.Start(ctx, "PRService.GetPR")
+	defer func() { tracing.End(span, err) }()
+
+	pr, err := s.pullRequestRepository.GetByID(ctx, prID)
+	if err != nil {
+		return prmodel.PullRequestDetails{}, fmt.Errorf("get PR: %w", err)
+	}
+
+	author, err := s.userRepository.GetByID(ctx, pr.AuthorID)
+	if err != nil {
+		return prmodel.PullRequestDetails{}, fmt.Errorf("get author: %w", err)
+	}
+
+	history, err := s.pullRequestRepository.History(ctx, prID)
+	if err != nil {
+		return prmodel.PullRequestDetails{}, fmt.Errorf("get reviewer history: %w", err)
+	}
+	active := make([]prmodel.ReviewerAssignment, 0, len(pr.AssignedReviewers))
+	for _, a := range history {
+		if a.ReplacedAt == nil {
+			active = append(active, a)
 		}
-		if _, exists := current[u.UserID]; exists {
-			continue
+	}
+
+	return prmodel.PullRequestDetails{
+		PullRequest: pr,
+		AuthorTeam:  author.TeamName,
+		Reviewers:   active,
+	}, nil
+}
+
+func (s *PRService) GetHistory(ctx context.Context, prID string) (_ []prmodel.ReviewerAssignment, err error) {
+	ctx, span := tracing.Start(ctx, "PRService.GetHistory")
+	defer func() { tracing.End(span, err) }()
+
+	if _, err := s.pullRequestRepository.GetByID(ctx, prID); err != nil {
+		return nil, fmt.Errorf("get PR: %w", err)
+	}
+
+	history, err := s.pullRequestRepository.History(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("get reviewer history: %w", err)
+	}
+	return history, nil
+}
+
+func (s *PRService) ListPullRequests(ctx context.Context, filter prmodel.ListFilter) (_ prmodel.ListPage, err error) {
+	ctx, span := tracing.Start(ctx, "PRService.ListPullRequests")
+	defer func() { tracing.End(span, err) }()
+
+	if filter.TeamName != "" {
+		exists, err := s.teamRepository.Exists(ctx, filter.TeamName)
+		if err != nil {
+			return prmodel.ListPage{}, fmt.Errorf("check team exists: %w", err)
+		}
+		if !exists {
+			return prmodel.ListPage{}, core.NewDomainError(core.ErrorNotFound, "team not found").
+				WithDetails(map[string]any{"team_name": filter.TeamName})
 		}
-		candidates = append(candidates, u)
+	}
+	if filter.Sort == "" {
+		filter.Sort = prmodel.ListSortCreatedDesc
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = prmodel.DefaultReviewPageLimit
+	}
+	if filter.Limit > prmodel.MaxReviewPageLimit {
+		filter.Limit = prmodel.MaxReviewPageLimit
 	}
 
-	if len(candidates) == 0 {
-		return nil, "", core.Throw(core.ErrorNoCandidate, "no active replacement candidate in team")
+	page, err := s.pullRequestRepository.List(ctx, filter)
+	if err != nil {
+		return prmodel.ListPage{}, fmt.Errorf("list PRs: %w", err)
 	}
+	return page, nil
+}
 
-	newUser := chooseReviewers(candidates, 1, s.rand)[0]
+// PlanReviewerRelease computes replacements for every open review held by
+// the given users without touching storage. Leaving users are never picked
+// as replacements; reviews without a candidate are reported as unassigned.
+func (s *PRService) PlanReviewerRelease(ctx context.Context, userIDs []string) (_ prmodel.RebalanceReport, err error) {
+	ctx, span := tracing.Start(ctx, "PRService.PlanReviewerRelease")
+	defer func() { tracing.End(span, err) }()
 
-	pr.AssignedReviewers[idx] = newUser
+	var report prmodel.RebalanceReport
+	if len(userIDs) == 0 {
+		return report, nil
+	}
 
-	if err := s.pullRequestRepository.Update(ctx, pr); err != nil {
-		return nil, "", fmt.Errorf("update PR after reassign: %w", err)
+	leaving := make(map[string]usermodel.User, len(userIDs))
+	for _, id := range userIDs {
+		user, err := s.userRepository.GetByID(ctx, id)
+		if err != nil {
+			return report, fmt.Errorf("get reviewer: %w", err)
+		}
+		leaving[id] = user
 	}
 
-	return &pr, newUser, nil
+	prs, err := s.pullRequestRepository.OpenReviewsOf(ctx, userIDs)
+	if err != nil {
+		return report, fmt.Errorf("get open reviews: %w", err)
+	}
+
+	pools := map[string]*reviewerPool{}
+	for _, pr := range prs {
+		for idx, reviewerID := range pr.AssignedReviewers {
+			user, ok := leaving[reviewerID]
+			if !ok {
+				continue
+			}
+
+			var picked string
+			if user.TeamName != "" {
+				pool, ok := pools[user.TeamName]
+				if !ok {
+					pool, err = s.loadReviewerPool(ctx, user.TeamName)
+					if err != nil {
+						return report, err
+					}
+					pools[user.TeamName] = pool
+				}
+				picked = pool.pick(s.selectors, pr, leaving)
+			}
+
+			if picked == "" {
+				report.Unassigned = append(report.Unassigned, prmodel.UnassignedReview{
+					PullRequestID: pr.PullRequestID,
+					UserID:        reviewerID,
+				})
+				continue
+			}
+
+			pr.AssignedReviewers[idx] = picked
+			report.Moved = append(report.Moved, prmodel.ReviewerMove{
+				PullRequestID: pr.PullRequestID,
+				FromUserID:    reviewerID,
+				ToUserID:      picked,
+			})
+		}
+	}
+
+	return report, nil
 }
 
-func chooseReviewers(users []usermodel.User, limit int, r *rand.Rand) []string {
-	if len(users) == 0 || limit <= 0 {
-		return nil
+// reviewerPool caches a team's members and their workload while a release
+// is planned, so that every PR does not cost extra round trips.
+type reviewerPool struct {
+	settings teammodel.Settings
+	tiers    [][]usermodel.User
+	workload map[string]int
+}
+
+func (s *PRService) loadReviewerPool(ctx context.Context, teamName string) (*reviewerPool, error) {
+	settings, err := s.teamRepository.GetSettings(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("get team settings: %w", err)
+	}
+
+	var tiers [][]usermodel.User
+	var ids []string
+	for _, team := range append([]string{teamName}, settings.FallbackTeams...) {
+		members, err := s.userRepository.GetByTeam(ctx, team)
+		if err != nil {
+			return nil, fmt.Errorf("get team members: %w", err)
+		}
+		for _, u := range members {
+			ids = append(ids, u.UserID)
+		}
+		tiers = append(tiers, members)
+	}
+	workload, err := s.pullRequestRepository.OpenReviewCounts(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("get reviewers workload: %w", err)
+	}
+
+	if workload == nil {
+		workload = map[string]int{}
+	}
+
+	return &reviewerPool{settings: settings, tiers: tiers, workload: workload}, nil
+}
+
+func (p *reviewerPool) pick(
+	selectors ReviewerSelectors,
+	pr prmodel.PullRequest,
+	leaving map[string]usermodel.User,
+) string {
+	for _, members := range p.tiers {
+		var candidates []ReviewerCandidate
+		for _, u := range members {
+			if !u.IsActive {
+				continue
+			}
+			if _, ok := leaving[u.UserID]; ok {
+				continue
+			}
+			if u.UserID == pr.AuthorID {
+				continue
+			}
+			if slices.Contains(pr.AssignedReviewers, u.UserID) {
+				continue
+			}
+			candidates = append(candidates, ReviewerCandidate{
+				UserID:   u.UserID,
+				TeamName: u.TeamName,
+				Workload: p.workload[u.UserID],
+			})
+		}
+
+		picked := selectors.For(p.settings.ReviewerStrategy).Select(candidates, 1)
+		if len(picked) > 0 {
+			p.workload[picked[0]]++
+			return picked[0]
+		}
+	}
+	return ""
+}
+
+func (s *PRService) initialReviewers(ctx context.Context, author usermodel.User) ([]string, []string, error) {
+	settings, err := s.teamRepository.GetSettings(ctx, author.TeamName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get team settings: %w", err)
 	}
-	if len(users) <= limit {
-		result := make([]string, 0, len(users))
+
+	return s.drawReviewers(ctx, author.TeamName, settings, settings.ReviewersRequired(), func(u usermodel.User) bool {
+		return u.UserID == author.UserID
+	})
+}
+
+// drawReviewers fills up to limit slots from the home team and, once it is
+// exhausted, from its fallback teams in the configured order. It returns the
+// fallback teams that contributed reviewers.
+func (s *PRService) drawReviewers(
+	ctx context.Context,
+	homeTeam string,
+	settings teammodel.Settings,
+	limit int,
+	skip func(u usermodel.User) bool,
+) ([]string, []string, error) {
+	var reviewers, fallbacks []string
+
+	teams := append([]string{homeTeam}, settings.FallbackTeams...)
+	for i, team := range teams {
+		if len(reviewers) >= limit {
+			break
+		}
+
+		users, err := s.userRepository.GetByTeam(ctx, team)
+		if err != nil {
+			return nil, nil, fmt.Errorf("get team members: %w", err)
+		}
+
+		var candidates []usermodel.User
 		for _, u := range users {
-			result = append(result, u.UserID)
+			if !u.IsActive {
+				continue
+			}
+			if skip(u) {
+				continue
+			}
+			if slices.Contains(reviewers, u.UserID) {
+				continue
+			}
+			candidates = append(candidates, u)
+		}
+
+		picked, err := s.pickReviewers(ctx, settings, candidates, limit-len(reviewers))
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(picked) > 0 && i > 0 {
+			fallbacks = append(fallbacks, team)
 		}
-		return result
+		reviewers = append(reviewers, picked...)
 	}
 
-	tmp := make([]usermodel.User, len(users))
-	copy(tmp, users)
+	return reviewers, fallbacks, nil
+}
+
+func (s *PRService) assignIfMissing(ctx context.Context, pr *prmodel.PullRequest) error {
+	if len(pr.AssignedReviewers) > 0 {
+		return nil
+	}
+
+	author, err := s.userRepository.GetByID(ctx, pr.AuthorID)
+	if err != nil {
+		return fmt.Errorf("get author: %w", err)
+	}
+
+	reviewers, fallbacks, err := s.initialReviewers(ctx, author)
+	if err != nil {
+		return err
+	}
+	pr.AssignedReviewers = reviewers
+	pr.FallbackTeams = fallbacks
+	return nil
+}
+
+func (s *PRService) checkApprovals(ctx context.Context, pr prmodel.PullRequest) error {
+	author, err := s.userRepository.GetByID(ctx, pr.AuthorID)
+	if err != nil {
+		return fmt.Errorf("get author: %w", err)
+	}
+
+	settings, err := s.teamRepository.GetSettings(ctx, author.TeamName)
+	if err != nil {
+		return fmt.Errorf("get team settings: %w", err)
+	}
+	if settings.RequiredApprovals <= 0 {
+		return nil
+	}
 
-	r.Shuffle(len(tmp), func(i, j int) {
-		tmp[i], tmp[j] = tmp[j], tmp[i]
+	approvals, err := s.pullRequestRepository.CountApprovals(ctx, pr.PullRequestID)
+	if err != nil {
+		return fmt.Errorf("count approvals: %w", err)
+	}
+	if approvals < settings.RequiredApprovals {
+		return core.NewDomainError(core.ErrorNotApproved, "PR does not have enough approvals").
+			WithDetails(map[string]any{
+				"pull_request_id": pr.PullRequestID,
+				"approvals":       approvals,
+				"required":        settings.RequiredApprovals,
+			})
+	}
+	return nil
+}
+
+// RecordReviewerMoves emits reviewer_reassigned for moves applied by a bulk
+// release, with the same payload ReassignReviewer records. It must run in
+// the transaction that applied them.
+func (s *PRService) RecordReviewerMoves(ctx context.Context, moves []prmodel.ReviewerMove) (err error) {
+	ctx, span := tracing.Start(ctx, "PRService.RecordReviewerMoves")
+	defer func() { tracing.End(span, err) }()
+
+	for _, move := range moves {
+		pr, err := s.pullRequestRepository.GetByID(ctx, move.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("get PR: %w", err)
+		}
+		payload := prPayload(pr)
+		payload.OldReviewerID = move.FromUserID
+		payload.NewReviewerID = move.ToUserID
+		if err := s.record(ctx, eventmodel.TypeReviewerReassigned, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// record writes the event to the outbox. It must run in the transaction of
+// the change it describes.
+func (s *PRService) record(ctx context.Context, t eventmodel.Type, payload eventmodel.PullRequestPayload) error {
+	if err := s.outboxRepository.Add(ctx, eventmodel.New(t, payload)); err != nil {
+		return fmt.Errorf("record %s event: %w", t, err)
+	}
+	return nil
+}
+
+// snapshot copies the PR so later in-place edits do not leak into it.
+func snapshot(pr prmodel.PullRequest) prmodel.PullRequest {
+	pr.AssignedReviewers = slices.Clone(pr.AssignedReviewers)
+	return pr
+}
+
+func prPayload(pr prmodel.PullRequest) eventmodel.PullRequestPayload {
+	return eventmodel.PullRequestPayload{
+		PullRequestID:     pr.PullRequestID,
+		PullRequestName:   pr.PullRequestName,
+		AuthorID:          pr.AuthorID,
+		Status:            string(pr.Status),
+		AssignedReviewers: pr.AssignedReviewers,
+		MergedAt:          pr.MergedAt,
+	}
+}
+
+func transition(pr *prmodel.PullRequest, to prmodel.PullRequestStatus) error {
+	if !pr.Status.CanTransitionTo(to) {
+		return invalidTransition(*pr, to)
+	}
+	pr.Status = to
+	return nil
+}
+
+func invalidTransition(pr prmodel.PullRequest, to prmodel.PullRequestStatus) error {
+	return core.NewDomainError(
+		core.ErrorInvalidTransition,
+		fmt.Sprintf("cannot move PR from %s to %s", pr.Status, to),
+	).WithDetails(map[string]any{
+		"pull_request_id": pr.PullRequestID,
+		"from":            string(pr.Status),
+		"to":              string(to),
 	})
+}
 
-	result := make([]string, 0, limit)
-	for i := 0; i < limit && i < len(tmp); i++ {
-		result = append(result, tmp[i].UserID)
+func (s *PRService) pickReviewers(
+	ctx context.Context,
+	settings teammodel.Settings,
+	users []usermodel.User,
+	limit int,
+) ([]string, error) {
+	if len(users) == 0 || limit <= 0 {
+		return nil, nil
+	}
+
+	candidates := make([]ReviewerCandidate, 0, len(users))
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		candidates = append(candidates, ReviewerCandidate{UserID: u.UserID, TeamName: u.TeamName})
+		ids = append(ids, u.UserID)
+	}
+
+	if len(candidates) > limit {
+		workload, err := s.pullRequestRepository.OpenReviewCounts(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("get reviewers workload: %w", err)
+		}
+		for i := range candidates {
+			candidates[i].Workload = workload[candidates[i].UserID]
+		}
 	}
 
-	return result
+	return s.selectors.For(settings.ReviewerStrategy).Select(candidates, limit), nil
 }
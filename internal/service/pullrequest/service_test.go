@@ -2,19 +2,75 @@ package service
 
 import (
 	"context"
+	"errors"
 	"math/rand"
+	"reflect"
+	"slices"
+	"sort"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
 	"avito-intern-test/internal/core"
+	"avito-intern-test/internal/metrics"
+	auditmodel "avito-intern-test/internal/model/audit"
+	eventmodel "avito-intern-test/internal/model/event"
 	prmodel "avito-intern-test/internal/model/pullrequest"
+	teammodel "avito-intern-test/internal/model/team"
 	usermodel "avito-intern-test/internal/model/user"
+	prrepo "avito-intern-test/internal/repository/pullrequest"
 )
 
+type txMock struct {
+	calls int
+}
+
+func (m *txMock) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	return fn(ctx)
+}
+
+type outboxMock struct {
+	events []eventmodel.Event
+	err    error
+}
+
+func (m *outboxMock) Add(_ context.Context, event eventmodel.Event) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.events = append(m.events, event)
+	return nil
+}
+
+type auditMock struct {
+	entries []auditmodel.Entry
+}
+
+func (m *auditMock) Append(_ context.Context, entry auditmodel.Entry) error {
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func (m *outboxMock) types() []eventmodel.Type {
+	out := make([]eventmodel.Type, 0, len(m.events))
+	for _, e := range m.events {
+		out = append(out, e.Type)
+	}
+	return out
+}
+
 type prRepoMock struct {
 	exists    bool
 	existsErr error
+	getErr    error
 	storage   map[string]prmodel.PullRequest
+	workload  map[string]int
+	history   map[string][]prmodel.ReviewerAssignment
+	verdicts  map[string]prmodel.ReviewVerdict
+	listed    prmodel.ListFilter
 }
 
 func (m *prRepoMock) Exists(ctx context.Context, prID string) (bool, error) {
@@ -28,12 +84,12 @@ func (m *prRepoMock) Create(ctx context.Context, pr prmodel.PullRequest) error {
 	return nil
 }
 func (m *prRepoMock) GetByID(ctx context.Context, prID string) (prmodel.PullRequest, error) {
-	if m.storage == nil {
-		return prmodel.PullRequest{}, context.Canceled
+	if m.getErr != nil {
+		return prmodel.PullRequest{}, m.getErr
 	}
 	pr, ok := m.storage[prID]
 	if !ok {
-		return prmodel.PullRequest{}, context.Canceled
+		return prmodel.PullRequest{}, prrepo.ErrPullRequestNotFound
 	}
 	return pr, nil
 }
@@ -45,13 +101,81 @@ func (m *prRepoMock) Update(ctx context.Context, pr prmodel.PullRequest) error {
 	return nil
 }
 
+func (m *prRepoMock) ReplaceReviewer(ctx context.Context, pr prmodel.PullRequest, oldUserID, newUserID string) error {
+	if m.history == nil {
+		m.history = map[string][]prmodel.ReviewerAssignment{}
+	}
+	now := time.Now().UTC()
+	for i, a := range m.history[pr.PullRequestID] {
+		if a.UserID == oldUserID && a.ReplacedAt == nil {
+			m.history[pr.PullRequestID][i].ReplacedAt = &now
+			m.history[pr.PullRequestID][i].ReplacedBy = &newUserID
+		}
+	}
+	m.history[pr.PullRequestID] = append(m.history[pr.PullRequestID], prmodel.ReviewerAssignment{
+		UserID:     newUserID,
+		AssignedAt: now,
+	})
+	return m.Update(ctx, pr)
+}
+func (m *prRepoMock) History(ctx context.Context, prID string) ([]prmodel.ReviewerAssignment, error) {
+	return m.history[prID], nil
+}
+func (m *prRepoMock) SetVerdict(ctx context.Context, prID, userID string, verdict prmodel.ReviewVerdict) error {
+	if m.verdicts == nil {
+		m.verdicts = map[string]prmodel.ReviewVerdict{}
+	}
+	m.verdicts[prID+"/"+userID] = verdict
+	return nil
+}
+func (m *prRepoMock) CountApprovals(ctx context.Context, prID string) (int, error) {
+	count := 0
+	for _, id := range m.storage[prID].AssignedReviewers {
+		if m.verdicts[prID+"/"+id] == prmodel.ReviewVerdictApproved {
+			count++
+		}
+	}
+	return count, nil
+}
+func (m *prRepoMock) OpenReviewsOf(ctx context.Context, userIDs []string) ([]prmodel.PullRequest, error) {
+	var result []prmodel.PullRequest
+	for _, pr := range m.storage {
+		if pr.Status != prmodel.PullRequestStatusOpen {
+			continue
+		}
+		for _, id := range userIDs {
+			if slices.Contains(pr.AssignedReviewers, id) {
+				pr.AssignedReviewers = slices.Clone(pr.AssignedReviewers)
+				result = append(result, pr)
+				break
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].PullRequestID < result[j].PullRequestID
+	})
+	return result, nil
+}
+func (m *prRepoMock) OpenReviewCounts(ctx context.Context, userIDs []string) (map[string]int, error) {
+	return m.workload, nil
+}
+
+func (m *prRepoMock) List(ctx context.Context, filter prmodel.ListFilter) (prmodel.ListPage, error) {
+	m.listed = filter
+	return prmodel.ListPage{}, nil
+}
+
 type teamRepoMockForPR struct {
-	exists bool
+	exists   bool
+	settings teammodel.Settings
 }
 
 func (t *teamRepoMockForPR) Exists(ctx context.Context, teamName string) (bool, error) {
 	return t.exists, nil
 }
+func (t *teamRepoMockForPR) GetSettings(ctx context.Context, teamName string) (teammodel.Settings, error) {
+	return t.settings, nil
+}
 
 type userRepoMockForPR struct {
 	users  map[string]usermodel.User
@@ -90,10 +214,10 @@ func TestPRService_CreatePR_Success(t *testing.T) {
 			},
 		},
 	}
-	svc := NewPRService(ur, tr, prr)
-	svc.rand = rand.New(rand.NewSource(1))
+	events := &outboxMock{}
+	svc := NewPRService(&txMock{}, ur, tr, prr, NewReviewerSelectors(rand.New(rand.NewSource(1))), events, &auditMock{})
 
-	pr, err := svc.CreatePR(context.Background(), "pr-1", "Test", "a1")
+	pr, err := svc.CreatePR(context.Background(), "pr-1", "Test", "a1", false)
 	if err != nil {
 		t.Fatalf("unexpected err: %v", err)
 	}
@@ -103,14 +227,40 @@ func TestPRService_CreatePR_Success(t *testing.T) {
 	if len(pr.AssignedReviewers) == 0 {
 		t.Fatalf("expected reviewers assigned")
 	}
+	want := []eventmodel.Type{eventmodel.TypePullRequestCreated, eventmodel.TypeReviewersAssigned}
+	if !slices.Equal(events.types(), want) {
+		t.Fatalf("unexpected events: %v", events.types())
+	}
+	payload := events.events[1].Data.(eventmodel.PullRequestPayload)
+	if payload.PullRequestID != "pr-1" || len(payload.AssignedReviewers) != len(pr.AssignedReviewers) {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestPRService_CreatePR_OutboxFailureFailsRequest(t *testing.T) {
+	prr := &prRepoMock{}
+	ur := &userRepoMockForPR{
+		users:  map[string]usermodel.User{"a1": {UserID: "a1", TeamName: "backend", IsActive: true}},
+		byTeam: map[string][]usermodel.User{"backend": {{UserID: "a1", TeamName: "backend", IsActive: true}}},
+	}
+	tx := &txMock{}
+	outbox := &outboxMock{err: errors.New("outbox down")}
+	svc := NewPRService(tx, ur, &teamRepoMockForPR{exists: true}, prr, NewReviewerSelectors(rand.New(rand.NewSource(1))), outbox, &auditMock{})
+
+	if _, err := svc.CreatePR(context.Background(), "pr-1", "Test", "a1", true); err == nil {
+		t.Fatalf("expected outbox failure to fail the transaction")
+	}
+	if tx.calls != 1 {
+		t.Fatalf("expected change and event in one transaction, got %d", tx.calls)
+	}
 }
 
 func TestPRService_CreatePR_AlreadyExists(t *testing.T) {
 	prr := &prRepoMock{exists: true}
 	tr := &teamRepoMockForPR{exists: true}
 	ur := &userRepoMockForPR{}
-	svc := NewPRService(ur, tr, prr)
-	_, err := svc.CreatePR(context.Background(), "pr-1", "Test", "a1")
+	svc := NewPRService(&txMock{}, ur, tr, prr, NewReviewerSelectors(rand.New(rand.NewSource(1))), &outboxMock{}, &auditMock{})
+	_, err := svc.CreatePR(context.Background(), "pr-1", "Test", "a1", false)
 	if err == nil || !strings.Contains(err.Error(), core.ErrorPRExists) {
 		t.Fatalf("expected PR_EXISTS, got %v", err)
 	}
@@ -118,11 +268,14 @@ func TestPRService_CreatePR_AlreadyExists(t *testing.T) {
 
 func TestPRService_MergePR_Idempotent(t *testing.T) {
 	prr := &prRepoMock{storage: map[string]prmodel.PullRequest{
-		"pr-1": {PullRequestID: "pr-1", Status: prmodel.PullRequestStatusOpen},
+		"pr-1": {PullRequestID: "pr-1", AuthorID: "a1", Status: prmodel.PullRequestStatusOpen},
 	}}
 	tr := &teamRepoMockForPR{}
-	ur := &userRepoMockForPR{}
-	svc := NewPRService(ur, tr, prr)
+	ur := &userRepoMockForPR{users: map[string]usermodel.User{
+		"a1": {UserID: "a1", TeamName: "backend", IsActive: true},
+	}}
+	events := &outboxMock{}
+	svc := NewPRService(&txMock{}, ur, tr, prr, NewReviewerSelectors(rand.New(rand.NewSource(1))), events, &auditMock{})
 	pr, err := svc.MergePR(context.Background(), "pr-1")
 	if err != nil {
 		t.Fatalf("unexpected: %v", err)
@@ -137,4 +290,645 @@ func TestPRService_MergePR_Idempotent(t *testing.T) {
 	if pr.Status != prmodel.PullRequestStatusMerged {
 		t.Fatalf("expected merged again")
 	}
+	if !slices.Equal(events.types(), []eventmodel.Type{eventmodel.TypePullRequestMerged}) {
+		t.Fatalf("repeated merge must not emit again: %v", events.types())
+	}
+}
+
+func TestPRService_CreatePR_PrefersLeastLoadedReviewers(t *testing.T) {
+	prr := &prRepoMock{workload: map[string]int{"r1": 5, "r2": 0, "r3": 1}}
+	tr := &teamRepoMockForPR{exists: true}
+	ur := &userRepoMockForPR{
+		users: map[string]usermodel.User{
+			"a1": {UserID: "a1", TeamName: "backend", IsActive: true},
+		},
+		byTeam: map[string][]usermodel.User{
+			"backend": {
+				{UserID: "a1", TeamName: "backend", IsActive: true},
+				{UserID: "r1", TeamName: "backend", IsActive: true},
+				{UserID: "r2", TeamName: "backend", IsActive: true},
+				{UserID: "r3", TeamName: "backend", IsActive: true},
+			},
+		},
+	}
+	svc := NewPRService(&txMock{}, ur, tr, prr, NewReviewerSelectors(rand.New(rand.NewSource(1))), &outboxMock{}, &auditMock{})
+
+	pr, err := svc.CreatePR(context.Background(), "pr-1", "Test", "a1", false)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	got := map[string]bool{}
+	for _, id := range pr.AssignedReviewers {
+		got[id] = true
+	}
+	if len(got) != 2 || !got["r2"] || !got["r3"] {
+		t.Fatalf("expected least loaded r2 and r3, got %v", pr.AssignedReviewers)
+	}
+}
+
+func TestPRService_ReassignReviewer_PrefersLeastLoaded(t *testing.T) {
+	prr := &prRepoMock{
+		storage: map[string]prmodel.PullRequest{
+			"pr-1": {
+				PullRequestID:     "pr-1",
+				AuthorID:          "a1",
+				Status:            prmodel.PullRequestStatusOpen,
+				AssignedReviewers: []string{"r1", "r2"},
+			},
+		},
+		workload: map[string]int{"r3": 4, "r4": 2, "r5": 3},
+	}
+	tr := &teamRepoMockForPR{exists: true}
+	ur := &userRepoMockForPR{
+		users: map[string]usermodel.User{
+			"r1": {UserID: "r1", TeamName: "backend", IsActive: true},
+		},
+		byTeam: map[string][]usermodel.User{
+			"backend": {
+				{UserID: "a1", TeamName: "backend", IsActive: true},
+				{UserID: "r1", TeamName: "backend", IsActive: true},
+				{UserID: "r2", TeamName: "backend", IsActive: true},
+				{UserID: "r3", TeamName: "backend", IsActive: true},
+				{UserID: "r4", TeamName: "backend", IsActive: true},
+				{UserID: "r5", TeamName: "backend", IsActive: true},
+			},
+		},
+	}
+	events := &outboxMock{}
+	audit := &auditMock{}
+	svc := NewPRService(&txMock{}, ur, tr, prr, NewReviewerSelectors(rand.New(rand.NewSource(1))), events, audit)
+
+	reassigned := metrics.Reassignments.WithLabelValues("backend")
+	reassignedBefore := testutil.ToFloat64(reassigned)
+
+	ctx := core.WithActor(context.Background(), "lead")
+	_, newUser, err := svc.ReassignReviewer(ctx, "pr-1", "r1")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if newUser != "r4" {
+		t.Fatalf("expected r4, got %s", newUser)
+	}
+	if got := testutil.ToFloat64(reassigned) - reassignedBefore; got != 1 {
+		t.Fatalf("expected one reassignment recorded, got %v", got)
+	}
+	if len(events.events) != 1 || events.events[0].Type != eventmodel.TypeReviewerReassigned {
+		t.Fatalf("unexpected events: %v", events.types())
+	}
+	payload := events.events[0].Data.(eventmodel.PullRequestPayload)
+	if payload.OldReviewerID != "r1" || payload.NewReviewerID != "r4" {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+	if len(audit.entries) != 1 {
+		t.Fatalf("expected one audit entry, got %d", len(audit.entries))
+	}
+	entry := audit.entries[0]
+	before := entry.Before.(prmodel.PullRequest)
+	after := entry.After.(prmodel.PullRequest)
+	if entry.Actor != "lead" || entry.Action != auditmodel.ActionPullRequestReassign || entry.EntityID != "pr-1" ||
+		!slices.Contains(before.AssignedReviewers, "r1") || slices.Contains(after.AssignedReviewers, "r1") {
+		t.Fatalf("unexpected audit entry: %+v", entry)
+	}
+}
+
+func TestPRService_CreatePR_UsesTeamStrategy(t *testing.T) {
+	prr := &prRepoMock{workload: map[string]int{"r1": 0, "r2": 0, "r3": 0}}
+	tr := &teamRepoMockForPR{
+		exists:   true,
+		settings: teammodel.Settings{ReviewerStrategy: teammodel.ReviewerStrategyRoundRobin},
+	}
+	ur := &userRepoMockForPR{
+		users: map[string]usermodel.User{
+			"a1": {UserID: "a1", TeamName: "backend", IsActive: true},
+		},
+		byTeam: map[string][]usermodel.User{
+			"backend": {
+				{UserID: "a1", TeamName: "backend", IsActive: true},
+				{UserID: "r1", TeamName: "backend", IsActive: true},
+				{UserID: "r2", TeamName: "backend", IsActive: true},
+				{UserID: "r3", TeamName: "backend", IsActive: true},
+			},
+		},
+	}
+	svc := NewPRService(&txMock{}, ur, tr, prr, NewReviewerSelectors(rand.New(rand.NewSource(1))), &outboxMock{}, &auditMock{})
+
+	first, err := svc.CreatePR(context.Background(), "pr-1", "Test", "a1", false)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	second, err := svc.CreatePR(context.Background(), "pr-2", "Test", "a1", false)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !reflect.DeepEqual(first.AssignedReviewers, []string{"r1", "r2"}) {
+		t.Fatalf("unexpected first reviewers: %v", first.AssignedReviewers)
+	}
+	if !reflect.DeepEqual(second.AssignedReviewers, []string{"r3", "r1"}) {
+		t.Fatalf("unexpected second reviewers: %v", second.AssignedReviewers)
+	}
+}
+
+func TestReviewerSelectors_PickDistinctCandidates(t *testing.T) {
+	candidates := []ReviewerCandidate{
+		{UserID: "u1", TeamName: "backend", Workload: 3},
+		{UserID: "u2", TeamName: "backend", Workload: 0},
+		{UserID: "u3", TeamName: "backend", Workload: 1},
+		{UserID: "u4", TeamName: "backend", Workload: 7},
+	}
+	for strategy, selector := range NewReviewerSelectors(rand.New(rand.NewSource(1))) {
+		got := selector.Select(candidates, 2)
+		if len(got) != 2 || got[0] == got[1] {
+			t.Fatalf("%s: expected 2 distinct reviewers, got %v", strategy, got)
+		}
+		if got := selector.Select(nil, 2); len(got) != 0 {
+			t.Fatalf("%s: expected no reviewers for empty candidates, got %v", strategy, got)
+		}
+	}
+}
+
+func TestWeightedSelector_FavoursIdleReviewers(t *testing.T) {
+	selector := NewWeightedSelector(rand.New(rand.NewSource(1)))
+	candidates := []ReviewerCandidate{
+		{UserID: "idle", Workload: 0},
+		{UserID: "busy", Workload: 20},
+	}
+	picks := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		picks[selector.Select(candidates, 1)[0]]++
+	}
+	if picks["idle"] <= picks["busy"]*5 {
+		t.Fatalf("expected idle reviewer to dominate, got %v", picks)
+	}
+}
+
+func TestPRService_CreatePR_HonoursRequiredReviewers(t *testing.T) {
+	ur := &userRepoMockForPR{
+		users: map[string]usermodel.User{
+			"a1": {UserID: "a1", TeamName: "security", IsActive: true},
+		},
+		byTeam: map[string][]usermodel.User{
+			"security": {
+				{UserID: "a1", TeamName: "security", IsActive: true},
+				{UserID: "r1", TeamName: "security", IsActive: true},
+				{UserID: "r2", TeamName: "security", IsActive: true},
+				{UserID: "r3", TeamName: "security", IsActive: true},
+				{UserID: "r4", TeamName: "security", IsActive: true},
+			},
+		},
+	}
+	for _, required := range []int{1, 3} {
+		tr := &teamRepoMockForPR{exists: true, settings: teammodel.Settings{RequiredReviewers: required}}
+		svc := NewPRService(&txMock{}, ur, tr, &prRepoMock{}, NewReviewerSelectors(rand.New(rand.NewSource(1))), &outboxMock{}, &auditMock{})
+		pr, err := svc.CreatePR(context.Background(), "pr-1", "Test", "a1", false)
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		if len(pr.AssignedReviewers) != required {
+			t.Fatalf("expected %d reviewers, got %v", required, pr.AssignedReviewers)
+		}
+	}
+}
+
+func TestPRService_ReassignReviewer_TopsUpToRequiredReviewers(t *testing.T) {
+	prr := &prRepoMock{
+		storage: map[string]prmodel.PullRequest{
+			"pr-1": {
+				PullRequestID:     "pr-1",
+				AuthorID:          "a1",
+				Status:            prmodel.PullRequestStatusOpen,
+				AssignedReviewers: []string{"r1"},
+			},
+		},
+	}
+	tr := &teamRepoMockForPR{exists: true, settings: teammodel.Settings{RequiredReviewers: 3}}
+	ur := &userRepoMockForPR{
+		users: map[string]usermodel.User{
+			"r1": {UserID: "r1", TeamName: "security", IsActive: true},
+		},
+		byTeam: map[string][]usermodel.User{
+			"security": {
+				{UserID: "a1", TeamName: "security", IsActive: true},
+				{UserID: "r1", TeamName: "security", IsActive: true},
+				{UserID: "r2", TeamName: "security", IsActive: true},
+				{UserID: "r3", TeamName: "security", IsActive: true},
+				{UserID: "r4", TeamName: "security", IsActive: true},
+			},
+		},
+	}
+	svc := NewPRService(&txMock{}, ur, tr, prr, NewReviewerSelectors(rand.New(rand.NewSource(1))), &outboxMock{}, &auditMock{})
+
+	pr, newUser, err := svc.ReassignReviewer(context.Background(), "pr-1", "r1")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(pr.AssignedReviewers) != 3 || pr.AssignedReviewers[0] != newUser {
+		t.Fatalf("unexpected reviewers: %v (replaced by %s)", pr.AssignedReviewers, newUser)
+	}
+	for _, id := range pr.AssignedReviewers {
+		if id == "r1" || id == "a1" {
+			t.Fatalf("unexpected reviewer %s in %v", id, pr.AssignedReviewers)
+		}
+	}
+}
+
+func TestPRService_GetHistory_KeepsReplacedReviewers(t *testing.T) {
+	assignedAt := time.Now().UTC().Add(-time.Hour)
+	prr := &prRepoMock{
+		storage: map[string]prmodel.PullRequest{
+			"pr-1": {
+				PullRequestID:     "pr-1",
+				AuthorID:          "a1",
+				Status:            prmodel.PullRequestStatusOpen,
+				AssignedReviewers: []string{"r1"},
+			},
+		},
+		history: map[string][]prmodel.ReviewerAssignment{
+			"pr-1": {{UserID: "r1", AssignedAt: assignedAt}},
+		},
+	}
+	tr := &teamRepoMockForPR{exists: true, settings: teammodel.Settings{RequiredReviewers: 1}}
+	ur := &userRepoMockForPR{
+		users: map[string]usermodel.User{
+			"r1": {UserID: "r1", TeamName: "backend", IsActive: true},
+		},
+		byTeam: map[string][]usermodel.User{
+			"backend": {
+				{UserID: "a1", TeamName: "backend", IsActive: true},
+				{UserID: "r1", TeamName: "backend", IsActive: true},
+				{UserID: "r2", TeamName: "backend", IsActive: true},
+			},
+		},
+	}
+	svc := NewPRService(&txMock{}, ur, tr, prr, NewReviewerSelectors(rand.New(rand.NewSource(1))), &outboxMock{}, &auditMock{})
+
+	if _, _, err := svc.ReassignReviewer(context.Background(), "pr-1", "r1"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	history, err := svc.GetHistory(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 assignments, got %+v", history)
+	}
+	if history[0].ReplacedAt == nil || history[0].ReplacedBy == nil || *history[0].ReplacedBy != "r2" {
+		t.Fatalf("expected r1 to be replaced by r2, got %+v", history[0])
+	}
+	if history[1].UserID != "r2" || history[1].ReplacedAt != nil {
+		t.Fatalf("expected r2 to be active, got %+v", history[1])
+	}
+}
+
+func TestPRService_GetHistory_NotFound(t *testing.T) {
+	svc := NewPRService(&txMock{}, &userRepoMockForPR{}, &teamRepoMockForPR{}, &prRepoMock{}, NewReviewerSelectors(rand.New(rand.NewSource(1))), &outboxMock{}, &auditMock{})
+	_, err := svc.GetHistory(context.Background(), "missing")
+	if !errors.Is(err, prrepo.ErrPullRequestNotFound) {
+		t.Fatalf("expected NOT_FOUND, got %v", err)
+	}
+}
+
+func TestPRService_GetPR(t *testing.T) {
+	now := time.Now().UTC()
+	approved := prmodel.ReviewVerdictApproved
+	prr := &prRepoMock{
+		storage: map[string]prmodel.PullRequest{
+			"pr-1": {PullRequestID: "pr-1", AuthorID: "a1", Status: prmodel.PullRequestStatusOpen, AssignedReviewers: []string{"r2"}},
+		},
+		history: map[string][]prmodel.ReviewerAssignment{
+			"pr-1": {
+				{UserID: "r1", AssignedAt: now, ReplacedAt: &now},
+				{UserID: "r2", AssignedAt: now, Verdict: &approved, ReviewedAt: &now},
+			},
+		},
+	}
+	ur := &userRepoMockForPR{users: map[string]usermodel.User{"a1": {UserID: "a1", TeamName: "backend"}}}
+	svc := NewPRService(&txMock{}, ur, &teamRepoMockForPR{}, prr, NewReviewerSelectors(rand.New(rand.NewSource(1))), &outboxMock{}, &auditMock{})
+
+	pr, err := svc.GetPR(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if pr.AuthorTeam != "backend" || len(pr.Reviewers) != 1 || pr.Reviewers[0].UserID != "r2" || pr.Reviewers[0].Verdict == nil {
+		t.Fatalf("expected only the active approved assignment, got %+v", pr)
+	}
+
+	if _, err := svc.GetPR(context.Background(), "missing"); !errors.Is(err, prrepo.ErrPullRequestNotFound) {
+		t.Fatalf("expected NOT_FOUND, got %v", err)
+	}
+
+	prr.getErr = context.DeadlineExceeded
+	_, err = svc.GetPR(context.Background(), "pr-1")
+	if _, ok := core.AsDomainError(err); ok || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the repository failure to pass through, got %v", err)
+	}
+}
+
+func newStateMachineService(status prmodel.PullRequestStatus, reviewers []string) (*PRService, *prRepoMock) {
+	prr := &prRepoMock{
+		storage: map[string]prmodel.PullRequest{
+			"pr-1": {
+				PullRequestID:     "pr-1",
+				AuthorID:          "a1",
+				Status:            status,
+				AssignedReviewers: reviewers,
+			},
+		},
+	}
+	ur := &userRepoMockForPR{
+		users: map[string]usermodel.User{
+			"a1": {UserID: "a1", TeamName: "backend", IsActive: true},
+			"r1": {UserID: "r1", TeamName: "backend", IsActive: true},
+		},
+		byTeam: map[string][]usermodel.User{
+			"backend": {
+				{UserID: "a1", TeamName: "backend", IsActive: true},
+				{UserID: "r1", TeamName: "backend", IsActive: true},
+				{UserID: "r2", TeamName: "backend", IsActive: true},
+			},
+		},
+	}
+	svc := NewPRService(&txMock{}, ur, &teamRepoMockForPR{exists: true}, prr, NewReviewerSelectors(rand.New(rand.NewSource(1))), &outboxMock{}, &auditMock{})
+	return svc, prr
+}
+
+func TestPRService_CreatePR_DraftHasNoReviewers(t *testing.T) {
+	svc, _ := newStateMachineService(prmodel.PullRequestStatusOpen, nil)
+	pr, err := svc.CreatePR(context.Background(), "pr-2", "Draft", "a1", true)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if pr.Status != prmodel.PullRequestStatusDraft || len(pr.AssignedReviewers) != 0 {
+		t.Fatalf("unexpected draft: %+v", pr)
+	}
+}
+
+func TestPRService_MarkReady_AssignsReviewers(t *testing.T) {
+	svc, prr := newStateMachineService(prmodel.PullRequestStatusDraft, nil)
+	pr, err := svc.MarkReady(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if pr.Status != prmodel.PullRequestStatusOpen || len(pr.AssignedReviewers) != 2 {
+		t.Fatalf("unexpected pr: %+v", pr)
+	}
+	if len(prr.storage["pr-1"].AssignedReviewers) != 2 {
+		t.Fatalf("expected reviewers to be stored")
+	}
+}
+
+func TestPRService_StateMachine(t *testing.T) {
+	tests := []struct {
+		name   string
+		from   prmodel.PullRequestStatus
+		action func(*PRService) (*prmodel.PullRequest, error)
+		want   prmodel.PullRequestStatus
+		code   string
+	}{
+		{"close open", prmodel.PullRequestStatusOpen, func(s *PRService) (*prmodel.PullRequest, error) {
+			return s.ClosePR(context.Background(), "pr-1")
+		}, prmodel.PullRequestStatusClosed, ""},
+		{"close draft", prmodel.PullRequestStatusDraft, func(s *PRService) (*prmodel.PullRequest, error) {
+			return s.ClosePR(context.Background(), "pr-1")
+		}, prmodel.PullRequestStatusClosed, ""},
+		{"reopen closed", prmodel.PullRequestStatusClosed, func(s *PRService) (*prmodel.PullRequest, error) {
+			return s.ReopenPR(context.Background(), "pr-1")
+		}, prmodel.PullRequestStatusOpen, ""},
+		{"close merged", prmodel.PullRequestStatusMerged, func(s *PRService) (*prmodel.PullRequest, error) {
+			return s.ClosePR(context.Background(), "pr-1")
+		}, "", core.ErrorInvalidTransition},
+		{"merge draft", prmodel.PullRequestStatusDraft, func(s *PRService) (*prmodel.PullRequest, error) {
+			return s.MergePR(context.Background(), "pr-1")
+		}, "", core.ErrorInvalidTransition},
+		{"merge closed", prmodel.PullRequestStatusClosed, func(s *PRService) (*prmodel.PullRequest, error) {
+			return s.MergePR(context.Background(), "pr-1")
+		}, "", core.ErrorInvalidTransition},
+		{"reopen draft", prmodel.PullRequestStatusDraft, func(s *PRService) (*prmodel.PullRequest, error) {
+			return s.ReopenPR(context.Background(), "pr-1")
+		}, "", core.ErrorInvalidTransition},
+		{"ready merged", prmodel.PullRequestStatusMerged, func(s *PRService) (*prmodel.PullRequest, error) {
+			return s.MarkReady(context.Background(), "pr-1")
+		}, "", core.ErrorInvalidTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newStateMachineService(tt.from, []string{"r1"})
+			pr, err := tt.action(svc)
+			if tt.code != "" {
+				domainErr, ok := core.AsDomainError(err)
+				if !ok || domainErr.Code != tt.code {
+					t.Fatalf("expected %s, got %v", tt.code, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if pr.Status != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, pr.Status)
+			}
+		})
+	}
+}
+
+func TestPRService_ReassignReviewer_RejectsClosedPR(t *testing.T) {
+	svc, _ := newStateMachineService(prmodel.PullRequestStatusClosed, []string{"r1"})
+	_, _, err := svc.ReassignReviewer(context.Background(), "pr-1", "r1")
+	domainErr, ok := core.AsDomainError(err)
+	if !ok || domainErr.Code != core.ErrorPRNotOpen {
+		t.Fatalf("expected PR_NOT_OPEN, got %v", err)
+	}
+}
+
+func TestPRService_MergePR_RequiresApprovals(t *testing.T) {
+	svc, _ := newStateMachineService(prmodel.PullRequestStatusOpen, []string{"r1", "r2"})
+	svc.teamRepository = &teamRepoMockForPR{exists: true, settings: teammodel.Settings{RequiredApprovals: 2}}
+	ctx := context.Background()
+
+	if _, err := svc.SubmitReview(ctx, "pr-1", "r1", prmodel.ReviewVerdictApproved); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if _, err := svc.SubmitReview(ctx, "pr-1", "r2", prmodel.ReviewVerdictChangesRequested); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	_, err := svc.MergePR(ctx, "pr-1")
+	domainErr, ok := core.AsDomainError(err)
+	if !ok || domainErr.Code != core.ErrorNotApproved {
+		t.Fatalf("expected NOT_APPROVED, got %v", err)
+	}
+
+	if _, err := svc.SubmitReview(ctx, "pr-1", "r2", prmodel.ReviewVerdictApproved); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	pr, err := svc.MergePR(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if pr.Status != prmodel.PullRequestStatusMerged {
+		t.Fatalf("expected merged, got %s", pr.Status)
+	}
+}
+
+func TestPRService_SubmitReview_NotAssigned(t *testing.T) {
+	svc, _ := newStateMachineService(prmodel.PullRequestStatusOpen, []string{"r1"})
+	_, err := svc.SubmitReview(context.Background(), "pr-1", "r2", prmodel.ReviewVerdictApproved)
+	domainErr, ok := core.AsDomainError(err)
+	if !ok || domainErr.Code != core.ErrorNotAssigned {
+		t.Fatalf("expected NOT_ASSIGNED, got %v", err)
+	}
+}
+
+func TestPRService_PlanReviewerRelease(t *testing.T) {
+	prr := &prRepoMock{storage: map[string]prmodel.PullRequest{
+		"pr-1": {PullRequestID: "pr-1", AuthorID: "a1", Status: prmodel.PullRequestStatusOpen, AssignedReviewers: []string{"r1", "r2"}},
+		"pr-2": {PullRequestID: "pr-2", AuthorID: "r3", Status: prmodel.PullRequestStatusOpen, AssignedReviewers: []string{"r1", "r2"}},
+		"pr-3": {PullRequestID: "pr-3", AuthorID: "a1", Status: prmodel.PullRequestStatusMerged, AssignedReviewers: []string{"r1"}},
+	}}
+	ur := &userRepoMockForPR{
+		users: map[string]usermodel.User{
+			"r1": {UserID: "r1", TeamName: "backend", IsActive: true},
+		},
+		byTeam: map[string][]usermodel.User{
+			"backend": {
+				{UserID: "a1", TeamName: "backend", IsActive: false},
+				{UserID: "r1", TeamName: "backend", IsActive: true},
+				{UserID: "r2", TeamName: "backend", IsActive: true},
+				{UserID: "r3", TeamName: "backend", IsActive: true},
+			},
+		},
+	}
+	svc := NewPRService(&txMock{}, ur, &teamRepoMockForPR{exists: true}, prr, NewReviewerSelectors(rand.New(rand.NewSource(1))), &outboxMock{}, &auditMock{})
+
+	report, err := svc.PlanReviewerRelease(context.Background(), []string{"r1"})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	want := []prmodel.ReviewerMove{{PullRequestID: "pr-1", FromUserID: "r1", ToUserID: "r3"}}
+	if !reflect.DeepEqual(report.Moved, want) {
+		t.Fatalf("unexpected moves: %+v", report.Moved)
+	}
+	wantUnassigned := []prmodel.UnassignedReview{{PullRequestID: "pr-2", UserID: "r1"}}
+	if !reflect.DeepEqual(report.Unassigned, wantUnassigned) {
+		t.Fatalf("unexpected unassigned: %+v", report.Unassigned)
+	}
+	if got := prr.storage["pr-1"].AssignedReviewers; !reflect.DeepEqual(got, []string{"r1", "r2"}) {
+		t.Fatalf("planning must not touch storage, got %v", got)
+	}
+}
+
+func newFallbackService(prr *prRepoMock) *PRService {
+	ur := &userRepoMockForPR{
+		users: map[string]usermodel.User{
+			"a1": {UserID: "a1", TeamName: "backend", IsActive: true},
+			"r1": {UserID: "r1", TeamName: "backend", IsActive: true},
+		},
+		byTeam: map[string][]usermodel.User{
+			"backend": {
+				{UserID: "a1", TeamName: "backend", IsActive: true},
+				{UserID: "r1", TeamName: "backend", IsActive: true},
+				{UserID: "r2", TeamName: "backend", IsActive: false},
+			},
+			"platform": {
+				{UserID: "p1", TeamName: "platform", IsActive: true},
+			},
+			"infra": {
+				{UserID: "i1", TeamName: "infra", IsActive: true},
+				{UserID: "i2", TeamName: "infra", IsActive: true},
+			},
+		},
+	}
+	tr := &teamRepoMockForPR{exists: true, settings: teammodel.Settings{
+		RequiredReviewers: 3,
+		FallbackTeams:     []string{"platform", "infra"},
+	}}
+	return NewPRService(&txMock{}, ur, tr, prr, NewReviewerSelectors(rand.New(rand.NewSource(1))), &outboxMock{}, &auditMock{})
+}
+
+func TestPRService_CreatePR_FallsBackToPartnerTeams(t *testing.T) {
+	svc := newFallbackService(&prRepoMock{})
+
+	pr, err := svc.CreatePR(context.Background(), "pr-1", "Test", "a1", false)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(pr.AssignedReviewers) != 3 || pr.AssignedReviewers[0] != "r1" || pr.AssignedReviewers[1] != "p1" {
+		t.Fatalf("expected home reviewer first, then fallbacks in order, got %v", pr.AssignedReviewers)
+	}
+	if !reflect.DeepEqual(pr.FallbackTeams, []string{"platform", "infra"}) {
+		t.Fatalf("expected both fallback teams reported, got %v", pr.FallbackTeams)
+	}
+}
+
+func TestPRService_RecordReviewerMoves(t *testing.T) {
+	prr := &prRepoMock{storage: map[string]prmodel.PullRequest{
+		"pr-1": {
+			PullRequestID:     "pr-1",
+			PullRequestName:   "feature",
+			AuthorID:          "a1",
+			Status:            prmodel.PullRequestStatusOpen,
+			AssignedReviewers: []string{"r3", "r2"},
+		},
+	}}
+	outbox := &outboxMock{}
+	svc := NewPRService(&txMock{}, &userRepoMockForPR{}, &teamRepoMockForPR{}, prr, NewReviewerSelectors(rand.New(rand.NewSource(1))), outbox, &auditMock{})
+
+	err := svc.RecordReviewerMoves(context.Background(), []prmodel.ReviewerMove{
+		{PullRequestID: "pr-1", FromUserID: "r1", ToUserID: "r3"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(outbox.events) != 1 || outbox.events[0].Type != eventmodel.TypeReviewerReassigned {
+		t.Fatalf("expected one reviewer_reassigned event, got %+v", outbox.events)
+	}
+	want := prPayload(prr.storage["pr-1"])
+	want.OldReviewerID = "r1"
+	want.NewReviewerID = "r3"
+	if got := outbox.events[0].Data.(eventmodel.PullRequestPayload); !reflect.DeepEqual(got, want) {
+		t.Fatalf("payload = %+v, want %+v", got, want)
+	}
+
+	err = svc.RecordReviewerMoves(context.Background(), []prmodel.ReviewerMove{{PullRequestID: "missing"}})
+	if !errors.Is(err, prrepo.ErrPullRequestNotFound) {
+		t.Fatalf("expected ErrPullRequestNotFound, got %v", err)
+	}
+}
+
+func TestPRService_ReassignReviewer_UsesFallbackTeam(t *testing.T) {
+	prr := &prRepoMock{storage: map[string]prmodel.PullRequest{
+		"pr-1": {
+			PullRequestID:     "pr-1",
+			AuthorID:          "a1",
+			Status:            prmodel.PullRequestStatusOpen,
+			AssignedReviewers: []string{"r1", "i1", "i2"},
+		},
+	}}
+	svc := newFallbackService(prr)
+
+	pr, newUser, err := svc.ReassignReviewer(context.Background(), "pr-1", "r1")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if newUser != "p1" {
+		t.Fatalf("expected p1 from platform, got %s", newUser)
+	}
+	if !reflect.DeepEqual(pr.FallbackTeams, []string{"platform"}) {
+		t.Fatalf("expected platform fallback reported, got %v", pr.FallbackTeams)
+	}
+}
+
+func TestPRService_ListPullRequests(t *testing.T) {
+	prr := &prRepoMock{}
+	svc := NewPRService(&txMock{}, &userRepoMockForPR{}, &teamRepoMockForPR{exists: false}, prr, NewReviewerSelectors(rand.New(rand.NewSource(1))), &outboxMock{}, &auditMock{})
+
+	_, err := svc.ListPullRequests(context.Background(), prmodel.ListFilter{TeamName: "ghost"})
+	if de, ok := core.AsDomainError(err); !ok || de.Code != core.ErrorNotFound {
+		t.Fatalf("expected NOT_FOUND for unknown team, got %v", err)
+	}
+
+	if _, err := svc.ListPullRequests(context.Background(), prmodel.ListFilter{Limit: 500}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if prr.listed.Sort != prmodel.ListSortCreatedDesc || prr.listed.Limit != prmodel.MaxReviewPageLimit {
+		t.Fatalf("expected default sort and capped limit, got %+v", prr.listed)
+	}
 }
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	statsmodel "avito-intern-test/internal/model/stats"
+)
+
+type statsRepository interface {
+	UserStats(ctx context.Context, filter statsmodel.Filter) ([]statsmodel.UserStats, error)
+	PullRequestStats(ctx context.Context, filter statsmodel.Filter) ([]statsmodel.PullRequestStats, error)
+}
+
+type teamRepository interface {
+	Exists(ctx context.Context, teamName string) (bool, error)
+}
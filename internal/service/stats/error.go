@@ -0,0 +1,7 @@
+package service
+
+import "avito-intern-test/internal/core"
+
+var (
+	ErrTeamNotFound = core.Throw(core.ErrorNotFound, "team not found")
+)
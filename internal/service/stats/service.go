@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	prmodel "avito-intern-test/internal/model/pullrequest"
+	statsmodel "avito-intern-test/internal/model/stats"
+)
+
+type StatsService struct {
+	statsRepository statsRepository
+	teamRepository  teamRepository
+}
+
+func NewStatsService(
+	statsRepository statsRepository,
+	teamRepository teamRepository,
+) *StatsService {
+	return &StatsService{
+		statsRepository: statsRepository,
+		teamRepository:  teamRepository,
+	}
+}
+
+func (s *StatsService) GetStats(ctx context.Context, filter statsmodel.Filter) (statsmodel.Stats, error) {
+	if filter.TeamName != "" {
+		exists, err := s.teamRepository.Exists(ctx, filter.TeamName)
+		if err != nil {
+			return statsmodel.Stats{}, fmt.Errorf("check team exists: %w", err)
+		}
+		if !exists {
+			return statsmodel.Stats{}, ErrTeamNotFound
+		}
+	}
+
+	users, err := s.statsRepository.UserStats(ctx, filter)
+	if err != nil {
+		return statsmodel.Stats{}, fmt.Errorf("get user stats: %w", err)
+	}
+
+	prs, err := s.statsRepository.PullRequestStats(ctx, filter)
+	if err != nil {
+		return statsmodel.Stats{}, fmt.Errorf("get PR stats: %w", err)
+	}
+
+	return statsmodel.Stats{
+		Users:        users,
+		PullRequests: prs,
+		Teams:        aggregateTeams(users, prs),
+	}, nil
+}
+
+func aggregateTeams(users []statsmodel.UserStats, prs []statsmodel.PullRequestStats) []statsmodel.TeamStats {
+	byTeam := map[string]*statsmodel.TeamStats{}
+	team := func(name string) *statsmodel.TeamStats {
+		t, ok := byTeam[name]
+		if !ok {
+			t = &statsmodel.TeamStats{TeamName: name}
+			byTeam[name] = t
+		}
+		return t
+	}
+
+	for _, u := range users {
+		t := team(u.TeamName)
+		t.Members++
+		if u.IsActive {
+			t.ActiveMembers++
+		}
+		t.TotalAssignments += u.TotalAssignments
+		t.OpenAssignments += u.OpenAssignments
+		t.MergedAssignments += u.MergedAssignments
+	}
+
+	for _, pr := range prs {
+		t := team(pr.TeamName)
+		t.PullRequests++
+		switch prmodel.PullRequestStatus(pr.Status) {
+		case prmodel.PullRequestStatusOpen:
+			t.OpenPullRequests++
+		case prmodel.PullRequestStatusMerged:
+			t.MergedPullRequests++
+		}
+	}
+
+	result := make([]statsmodel.TeamStats, 0, len(byTeam))
+	for _, t := range byTeam {
+		result = append(result, *t)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TeamName < result[j].TeamName
+	})
+	return result
+}
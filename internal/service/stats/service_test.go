@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"avito-intern-test/internal/core"
+	statsmodel "avito-intern-test/internal/model/stats"
+)
+
+type statsRepoMock struct {
+	users  []statsmodel.UserStats
+	prs    []statsmodel.PullRequestStats
+	filter statsmodel.Filter
+}
+
+func (m *statsRepoMock) UserStats(_ context.Context, filter statsmodel.Filter) ([]statsmodel.UserStats, error) {
+	m.filter = filter
+	return m.users, nil
+}
+
+func (m *statsRepoMock) PullRequestStats(_ context.Context, filter statsmodel.Filter) ([]statsmodel.PullRequestStats, error) {
+	return m.prs, nil
+}
+
+type teamRepoMock struct {
+	exists bool
+}
+
+func (m *teamRepoMock) Exists(_ context.Context, teamName string) (bool, error) {
+	return m.exists, nil
+}
+
+func TestStatsService_GetStats_AggregatesTeams(t *testing.T) {
+	repo := &statsRepoMock{
+		users: []statsmodel.UserStats{
+			{UserID: "u1", TeamName: "backend", IsActive: true, TotalAssignments: 3, OpenAssignments: 1, MergedAssignments: 2},
+			{UserID: "u2", TeamName: "backend", IsActive: false, TotalAssignments: 1, MergedAssignments: 1},
+			{UserID: "u3", TeamName: "frontend", IsActive: true},
+		},
+		prs: []statsmodel.PullRequestStats{
+			{PullRequestID: "pr-1", TeamName: "backend", Status: "OPEN"},
+			{PullRequestID: "pr-2", TeamName: "backend", Status: "MERGED"},
+		},
+	}
+	svc := NewStatsService(repo, &teamRepoMock{exists: true})
+
+	stats, err := svc.GetStats(context.Background(), statsmodel.Filter{})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(stats.Teams) != 2 {
+		t.Fatalf("expected 2 teams, got %+v", stats.Teams)
+	}
+	backend := stats.Teams[0]
+	want := statsmodel.TeamStats{
+		TeamName:           "backend",
+		Members:            2,
+		ActiveMembers:      1,
+		TotalAssignments:   4,
+		OpenAssignments:    1,
+		MergedAssignments:  3,
+		PullRequests:       2,
+		OpenPullRequests:   1,
+		MergedPullRequests: 1,
+	}
+	if backend != want {
+		t.Fatalf("unexpected backend stats: %+v", backend)
+	}
+}
+
+func TestStatsService_GetStats_UnknownTeam(t *testing.T) {
+	svc := NewStatsService(&statsRepoMock{}, &teamRepoMock{exists: false})
+	_, err := svc.GetStats(context.Background(), statsmodel.Filter{TeamName: "nope"})
+	derr, ok := core.AsDomainError(err)
+	if !ok || derr.Code != core.ErrorNotFound {
+		t.Fatalf("expected NOT_FOUND, got %v", err)
+	}
+}
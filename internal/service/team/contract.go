@@ -3,17 +3,42 @@ package service
 import (
 	"context"
 
+	auditmodel "avito-intern-test/internal/model/audit"
+	eventmodel "avito-intern-test/internal/model/event"
+	prmodel "avito-intern-test/internal/model/pullrequest"
 	teammodel "avito-intern-test/internal/model/team"
 	usermodel "avito-intern-test/internal/model/user"
 )
 
+type txManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type teamRepository interface {
 	GetTeamMembers(ctx context.Context, teamName string) ([]usermodel.User, error)
 	Exists(ctx context.Context, teamName string) (bool, error)
 	Create(ctx context.Context, teamName string) (*teammodel.Team, error)
+	GetSettings(ctx context.Context, teamName string) (teammodel.Settings, error)
+	UpdateSettings(ctx context.Context, teamName string, settings teammodel.Settings) (teammodel.Settings, error)
+	Rename(ctx context.Context, teamName, newName string) error
+	Delete(ctx context.Context, teamName, targetTeam string, moves []prmodel.ReviewerMove) ([]usermodel.User, error)
 }
 
 type userRepository interface {
 	CreateOrUpdate(ctx context.Context, user usermodel.User) error
 	GetByID(ctx context.Context, userID string) (usermodel.User, error)
+	Deactivate(ctx context.Context, userIDs []string, moves []prmodel.ReviewerMove) ([]usermodel.User, error)
+}
+
+type reviewerPlanner interface {
+	PlanReviewerRelease(ctx context.Context, userIDs []string) (prmodel.RebalanceReport, error)
+	RecordReviewerMoves(ctx context.Context, moves []prmodel.ReviewerMove) error
+}
+
+type outboxRepository interface {
+	Add(ctx context.Context, event eventmodel.Event) error
+}
+
+type auditRepository interface {
+	Append(ctx context.Context, entry auditmodel.Entry) error
 }
@@ -1,8 +1,8 @@
 package service
 
-import "errors"
+import "avito-intern-test/internal/core"
 
 var (
-	ErrTeamAlreadyExists = errors.New("team already exists")
-	ErrTeamNotFound      = errors.New("team not found")
+	ErrTeamAlreadyExists = core.Throw(core.ErrorTeamExists, "team_name already exists")
+	ErrTeamNotFound      = core.Throw(core.ErrorNotFound, "resource not found")
 )
@@ -3,32 +3,53 @@ package service
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"avito-intern-test/internal/core"
+	"avito-intern-test/internal/metrics"
+	auditmodel "avito-intern-test/internal/model/audit"
+	eventmodel "avito-intern-test/internal/model/event"
+	prmodel "avito-intern-test/internal/model/pullrequest"
 	teammodel "avito-intern-test/internal/model/team"
 	usermodel "avito-intern-test/internal/model/user"
+	"avito-intern-test/internal/tracing"
 )
 
 type TeamService struct {
-	teamRepository teamRepository
-	userRepository userRepository
+	txManager        txManager
+	teamRepository   teamRepository
+	userRepository   userRepository
+	reviewerPlanner  reviewerPlanner
+	outboxRepository outboxRepository
+	auditRepository  auditRepository
 }
 
 func NewTeamService(
+	txManager txManager,
 	teamRepository teamRepository,
 	userRepository userRepository,
+	reviewerPlanner reviewerPlanner,
+	outboxRepository outboxRepository,
+	auditRepository auditRepository,
 ) *TeamService {
 	return &TeamService{
-		teamRepository: teamRepository,
-		userRepository: userRepository,
+		txManager:        txManager,
+		teamRepository:   teamRepository,
+		userRepository:   userRepository,
+		reviewerPlanner:  reviewerPlanner,
+		outboxRepository: outboxRepository,
+		auditRepository:  auditRepository,
 	}
 }
 
 func (s *TeamService) GetTeamMembers(
 	ctx context.Context,
 	teamName string,
-) ([]usermodel.User, error) {
+) (_ []usermodel.User, err error) {
+	ctx, span := tracing.Start(ctx, "TeamService.GetTeamMembers")
+	defer func() { tracing.End(span, err) }()
+
 	exists, _ := s.teamRepository.Exists(ctx, teamName)
 	if !exists {
 		return nil, ErrTeamNotFound
@@ -44,7 +65,11 @@ func (s *TeamService) CreateWithMembers(
 	ctx context.Context,
 	teamName string,
 	members []usermodel.User,
-) (*teammodel.Team, error) {
+	update teammodel.SettingsUpdate,
+) (_ *teammodel.Team, err error) {
+	ctx, span := tracing.Start(ctx, "TeamService.CreateWithMembers")
+	defer func() { tracing.End(span, err) }()
+
 	for _, m := range members {
 		existing, err := s.userRepository.GetByID(ctx, m.UserID)
 		if err == nil {
@@ -57,42 +82,323 @@ func (s *TeamService) CreateWithMembers(
 		}
 	}
 
-	exists, _ := s.teamRepository.Exists(ctx, teamName)
 	var createdTeam *teammodel.Team
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		exists, _ := s.teamRepository.Exists(ctx, teamName)
+		if !exists {
+			t, err := s.teamRepository.Create(ctx, teamName)
+			if err != nil {
+				return fmt.Errorf("create team: %w", err)
+			}
+			createdTeam = t
+			if err := s.record(ctx, eventmodel.TypeTeamCreated, eventmodel.TeamPayload{TeamName: teamName}); err != nil {
+				return err
+			}
+		} else {
+			settings, err := s.teamRepository.GetSettings(ctx, teamName)
+			if err != nil {
+				return fmt.Errorf("get team settings: %w", err)
+			}
+			createdTeam = &teammodel.Team{
+				Name:      teamName,
+				CreatedAt: time.Now(),
+				Settings:  settings,
+			}
+		}
+
+		if !update.IsEmpty() {
+			if err := s.checkFallbackTeams(ctx, update); err != nil {
+				return err
+			}
+			settings, err := s.teamRepository.UpdateSettings(ctx, teamName, createdTeam.Settings.Apply(update))
+			if err != nil {
+				return fmt.Errorf("update team settings: %w", err)
+			}
+			createdTeam.Settings = settings
+		}
+
+		for _, m := range members {
+			if existing, err := s.userRepository.GetByID(ctx, m.UserID); err == nil {
+				existing.Username = m.Username
+				existing.IsActive = m.IsActive
+				existing.TeamName = teamName
+				existing.CreatedAt = time.Now()
+				if err := s.userRepository.CreateOrUpdate(ctx, existing); err != nil {
+					return fmt.Errorf("create or update user %s: %w", m.UserID, err)
+				}
+			} else {
+				newUser := usermodel.User{
+					UserID:    m.UserID,
+					Username:  m.Username,
+					TeamName:  teamName,
+					IsActive:  m.IsActive,
+					CreatedAt: time.Now(),
+				}
+				if err := s.userRepository.CreateOrUpdate(ctx, newUser); err != nil {
+					return fmt.Errorf("create or update user %s: %w", m.UserID, err)
+				}
+			}
+			if err := s.record(ctx, eventmodel.TypeTeamMemberAdded, eventmodel.TeamMemberPayload{
+				TeamName: teamName,
+				UserID:   m.UserID,
+				Username: m.Username,
+				IsActive: m.IsActive,
+			}); err != nil {
+				return err
+			}
+		}
+		return s.auditRepository.Append(ctx, auditmodel.New(
+			ctx, auditmodel.ActionTeamCreate, auditmodel.EntityTeam, teamName, nil, map[string]any{
+				"settings": createdTeam.Settings,
+				"members":  members,
+			},
+		))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return createdTeam, nil
+}
+
+func (s *TeamService) GetSettings(
+	ctx context.Context,
+	teamName string,
+) (_ teammodel.Settings, err error) {
+	ctx, span := tracing.Start(ctx, "TeamService.GetSettings")
+	defer func() { tracing.End(span, err) }()
+
+	exists, _ := s.teamRepository.Exists(ctx, teamName)
 	if !exists {
-		t, err := s.teamRepository.Create(ctx, teamName)
+		return teammodel.Settings{}, ErrTeamNotFound
+	}
+	return s.teamRepository.GetSettings(ctx, teamName)
+}
+
+func (s *TeamService) UpdateSettings(
+	ctx context.Context,
+	teamName string,
+	update teammodel.SettingsUpdate,
+) (_ teammodel.Settings, err error) {
+	ctx, span := tracing.Start(ctx, "TeamService.UpdateSettings")
+	defer func() { tracing.End(span, err) }()
+
+	if err := core.AuthorizeTeam(ctx, teamName); err != nil {
+		return teammodel.Settings{}, err
+	}
+	current, err := s.GetSettings(ctx, teamName)
+	if err != nil {
+		return teammodel.Settings{}, err
+	}
+	if err := s.checkFallbackTeams(ctx, update); err != nil {
+		return teammodel.Settings{}, err
+	}
+	var updated teammodel.Settings
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		updated, err = s.teamRepository.UpdateSettings(ctx, teamName, current.Apply(update))
 		if err != nil {
-			return nil, fmt.Errorf("create team: %w", err)
+			return fmt.Errorf("update team settings: %w", err)
 		}
-		createdTeam = t
-	} else {
-		createdTeam = &teammodel.Team{
-			Name:      teamName,
-			CreatedAt: time.Now(),
+		return s.auditRepository.Append(ctx, auditmodel.New(
+			ctx, auditmodel.ActionTeamUpdateSettings, auditmodel.EntityTeam, teamName, current, updated,
+		))
+	})
+	if err != nil {
+		return teammodel.Settings{}, err
+	}
+	return updated, nil
+}
+
+func (s *TeamService) checkFallbackTeams(ctx context.Context, update teammodel.SettingsUpdate) error {
+	if update.FallbackTeams == nil {
+		return nil
+	}
+	for _, team := range *update.FallbackTeams {
+		exists, _ := s.teamRepository.Exists(ctx, team)
+		if !exists {
+			return core.NewDomainError(core.ErrorNotFound, "fallback team not found").
+				WithDetails(map[string]any{"team_name": team})
 		}
 	}
+	return nil
+}
 
+func (s *TeamService) DeactivateUsers(
+	ctx context.Context,
+	teamName string,
+	userIDs []string,
+) (_ []usermodel.User, _ prmodel.RebalanceReport, err error) {
+	ctx, span := tracing.Start(ctx, "TeamService.DeactivateUsers")
+	defer func() { tracing.End(span, err) }()
+
+	if err := core.AuthorizeTeam(ctx, teamName); err != nil {
+		return nil, prmodel.RebalanceReport{}, err
+	}
+	exists, _ := s.teamRepository.Exists(ctx, teamName)
+	if !exists {
+		return nil, prmodel.RebalanceReport{}, ErrTeamNotFound
+	}
+
+	members, err := s.teamRepository.GetTeamMembers(ctx, teamName)
+	if err != nil {
+		return nil, prmodel.RebalanceReport{}, fmt.Errorf("get team members: %w", err)
+	}
+	inTeam := make(map[string]usermodel.User, len(members))
 	for _, m := range members {
-		if existing, err := s.userRepository.GetByID(ctx, m.UserID); err == nil {
-			existing.Username = m.Username
-			existing.IsActive = m.IsActive
-			existing.TeamName = teamName
-			existing.CreatedAt = time.Now()
-			if err := s.userRepository.CreateOrUpdate(ctx, existing); err != nil {
-				return nil, fmt.Errorf("create or update user %s: %w", m.UserID, err)
-			}
+		inTeam[m.UserID] = m
+	}
+
+	ids := slices.Clone(userIDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	var missing []string
+	before := make([]usermodel.User, 0, len(ids))
+	for _, id := range ids {
+		if m, ok := inTeam[id]; ok {
+			before = append(before, m)
 		} else {
-			newUser := usermodel.User{
-				UserID:    m.UserID,
-				Username:  m.Username,
-				TeamName:  teamName,
-				IsActive:  m.IsActive,
-				CreatedAt: time.Now(),
-			}
-			if err := s.userRepository.CreateOrUpdate(ctx, newUser); err != nil {
-				return nil, fmt.Errorf("create or update user %s: %w", m.UserID, err)
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, prmodel.RebalanceReport{}, core.NewDomainError(core.ErrorNotFound, "users not found in team").
+			WithDetails(map[string]any{"team_name": teamName, "user_ids": missing})
+	}
+
+	report, err := s.reviewerPlanner.PlanReviewerRelease(ctx, ids)
+	if err != nil {
+		return nil, prmodel.RebalanceReport{}, fmt.Errorf("plan reviewer release: %w", err)
+	}
+
+	var users []usermodel.User
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		users, err = s.userRepository.Deactivate(ctx, ids, report.Moved)
+		if err != nil {
+			return err
+		}
+		if err := s.reviewerPlanner.RecordReviewerMoves(ctx, report.Moved); err != nil {
+			return err
+		}
+		return s.auditRepository.Append(ctx, auditmodel.New(
+			ctx, auditmodel.ActionTeamDeactivateUsers, auditmodel.EntityTeam, teamName, before, map[string]any{
+				"users":        users,
+				"reassignment": report,
+			},
+		))
+	})
+	if err != nil {
+		return nil, prmodel.RebalanceReport{}, err
+	}
+	metrics.CountReassignments(teamName, len(report.Moved))
+	return users, report, nil
+}
+
+func (s *TeamService) RenameTeam(ctx context.Context, teamName, newName string) (err error) {
+	ctx, span := tracing.Start(ctx, "TeamService.RenameTeam")
+	defer func() { tracing.End(span, err) }()
+
+	exists, _ := s.teamRepository.Exists(ctx, teamName)
+	if !exists {
+		return ErrTeamNotFound
+	}
+	taken, _ := s.teamRepository.Exists(ctx, newName)
+	if taken {
+		return ErrTeamAlreadyExists
+	}
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.teamRepository.Rename(ctx, teamName, newName); err != nil {
+			return err
+		}
+		return s.auditRepository.Append(ctx, auditmodel.New(
+			ctx, auditmodel.ActionTeamRename, auditmodel.EntityTeam, teamName,
+			map[string]string{"team_name": teamName},
+			map[string]string{"team_name": newName},
+		))
+	})
+}
+
+// DeleteTeam dissolves the team. Members move to targetTeam when it is set;
+// otherwise they are deactivated and their open reviews are handed over.
+func (s *TeamService) DeleteTeam(
+	ctx context.Context,
+	teamName string,
+	targetTeam string,
+) (_ []usermodel.User, _ prmodel.RebalanceReport, err error) {
+	ctx, span := tracing.Start(ctx, "TeamService.DeleteTeam")
+	defer func() { tracing.End(span, err) }()
+
+	exists, _ := s.teamRepository.Exists(ctx, teamName)
+	if !exists {
+		return nil, prmodel.RebalanceReport{}, ErrTeamNotFound
+	}
+
+	var report prmodel.RebalanceReport
+	if targetTeam != "" {
+		exists, _ := s.teamRepository.Exists(ctx, targetTeam)
+		if !exists {
+			return nil, prmodel.RebalanceReport{}, core.NewDomainError(core.ErrorNotFound, "target team not found").
+				WithDetails(map[string]any{"team_name": targetTeam})
+		}
+	} else {
+		members, err := s.teamRepository.GetTeamMembers(ctx, teamName)
+		if err != nil {
+			return nil, prmodel.RebalanceReport{}, fmt.Errorf("get team members: %w", err)
+		}
+		ids := make([]string, 0, len(members))
+		for _, m := range members {
+			ids = append(ids, m.UserID)
+		}
+
+		report, err = s.reviewerPlanner.PlanReviewerRelease(ctx, ids)
+		if err != nil {
+			return nil, prmodel.RebalanceReport{}, fmt.Errorf("plan reviewer release: %w", err)
+		}
+	}
+
+	var users []usermodel.User
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		users, err = s.teamRepository.Delete(ctx, teamName, targetTeam, report.Moved)
+		if err != nil {
+			return err
+		}
+		if err := s.reviewerPlanner.RecordReviewerMoves(ctx, report.Moved); err != nil {
+			return err
+		}
+		if targetTeam != "" {
+			for _, u := range users {
+				err := s.record(ctx, eventmodel.TypeTeamMemberAdded, eventmodel.TeamMemberPayload{
+					TeamName: u.TeamName,
+					UserID:   u.UserID,
+					Username: u.Username,
+					IsActive: u.IsActive,
+				})
+				if err != nil {
+					return err
+				}
 			}
 		}
+		return s.auditRepository.Append(ctx, auditmodel.New(
+			ctx, auditmodel.ActionTeamDelete, auditmodel.EntityTeam, teamName,
+			map[string]string{"team_name": teamName},
+			map[string]any{
+				"target_team":  targetTeam,
+				"users":        users,
+				"reassignment": report,
+			},
+		))
+	})
+	if err != nil {
+		return nil, prmodel.RebalanceReport{}, err
 	}
-	return createdTeam, nil
+	metrics.CountReassignments(teamName, len(report.Moved))
+	return users, report, nil
+}
+
+func (s *TeamService) record(ctx context.Context, t eventmodel.Type, payload any) error {
+	if err := s.outboxRepository.Add(ctx, eventmodel.New(t, payload)); err != nil {
+		return fmt.Errorf("record %s event: %w", t, err)
+	}
+	return nil
 }
@@ -2,13 +2,20 @@ package service
 
 import (
 	"context"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
 	"avito-intern-test/internal/core"
+	"avito-intern-test/internal/metrics"
+	auditmodel "avito-intern-test/internal/model/audit"
+	eventmodel "avito-intern-test/internal/model/event"
+	prmodel "avito-intern-test/internal/model/pullrequest"
 	teammodel "avito-intern-test/internal/model/team"
 	usermodel "avito-intern-test/internal/model/user"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 type teamRepoMock struct {
@@ -18,12 +25,21 @@ type teamRepoMock struct {
 	createErr  error
 	members    []usermodel.User
 	membersErr error
+	settings   teammodel.Settings
+	deleted    string
+	renamed    string
+	existing   map[string]bool
+	target     string
+	moves      []prmodel.ReviewerMove
 }
 
 func (m *teamRepoMock) GetTeamMembers(ctx context.Context, teamName string) ([]usermodel.User, error) {
 	return m.members, m.membersErr
 }
 func (m *teamRepoMock) Exists(ctx context.Context, teamName string) (bool, error) {
+	if m.existing != nil {
+		return m.existing[teamName], m.existsErr
+	}
 	return m.existsResp, m.existsErr
 }
 func (m *teamRepoMock) Create(ctx context.Context, teamName string) (*teammodel.Team, error) {
@@ -34,10 +50,33 @@ func (m *teamRepoMock) Create(ctx context.Context, teamName string) (*teammodel.
 	return m.created, m.createErr
 }
 
+func (m *teamRepoMock) GetSettings(ctx context.Context, teamName string) (teammodel.Settings, error) {
+	return m.settings, nil
+}
+func (m *teamRepoMock) UpdateSettings(ctx context.Context, teamName string, settings teammodel.Settings) (teammodel.Settings, error) {
+	m.settings = settings
+	return m.settings, nil
+}
+
+func (m *teamRepoMock) Rename(ctx context.Context, teamName, newName string) error {
+	m.renamed = teamName + "->" + newName
+	return nil
+}
+
+func (m *teamRepoMock) Delete(
+	ctx context.Context,
+	teamName, targetTeam string,
+	moves []prmodel.ReviewerMove,
+) ([]usermodel.User, error) {
+	m.deleted, m.target, m.moves = teamName, targetTeam, moves
+	return m.members, nil
+}
+
 type userRepoMock struct {
 	usersByID        map[string]usermodel.User
 	createOrUpdateFn func(user usermodel.User) error
 	getErr           error
+	moves            []prmodel.ReviewerMove
 }
 
 func (m *userRepoMock) CreateOrUpdate(_ context.Context, user usermodel.User) error {
@@ -64,16 +103,77 @@ func (m *userRepoMock) GetByID(_ context.Context, userID string) (usermodel.User
 	return u, nil
 }
 
+func (m *userRepoMock) Deactivate(
+	_ context.Context,
+	userIDs []string,
+	moves []prmodel.ReviewerMove,
+) ([]usermodel.User, error) {
+	m.moves = moves
+	users := make([]usermodel.User, 0, len(userIDs))
+	for _, id := range userIDs {
+		u := m.usersByID[id]
+		u.IsActive = false
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+type txMock struct {
+	calls int
+	err   error
+}
+
+func (m *txMock) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	m.err = fn(ctx)
+	return m.err
+}
+
+type outboxMock struct {
+	events []eventmodel.Event
+}
+
+func (m *outboxMock) Add(_ context.Context, event eventmodel.Event) error {
+	m.events = append(m.events, event)
+	return nil
+}
+
+type auditMock struct {
+	entries []auditmodel.Entry
+}
+
+func (m *auditMock) Append(_ context.Context, entry auditmodel.Entry) error {
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+type plannerMock struct {
+	report   prmodel.RebalanceReport
+	ids      []string
+	recorded []prmodel.ReviewerMove
+}
+
+func (m *plannerMock) PlanReviewerRelease(_ context.Context, userIDs []string) (prmodel.RebalanceReport, error) {
+	m.ids = userIDs
+	return m.report, nil
+}
+
+func (m *plannerMock) RecordReviewerMoves(_ context.Context, moves []prmodel.ReviewerMove) error {
+	m.recorded = append(m.recorded, moves...)
+	return nil
+}
+
 func TestTeamService_CreateWithMembers_SuccessCreateNewTeam(t *testing.T) {
 	tr := &teamRepoMock{existsResp: false}
 	ur := &userRepoMock{usersByID: map[string]usermodel.User{}}
-	svc := NewTeamService(tr, ur)
+	outbox := &outboxMock{}
+	svc := NewTeamService(&txMock{}, tr, ur, &plannerMock{}, outbox, &auditMock{})
 
 	members := []usermodel.User{
 		{UserID: "u1", Username: "Alice", IsActive: true},
 		{UserID: "u2", Username: "Bob", IsActive: true},
 	}
-	team, err := svc.CreateWithMembers(context.Background(), "backend", members)
+	team, err := svc.CreateWithMembers(context.Background(), "backend", members, teammodel.SettingsUpdate{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -86,6 +186,14 @@ func TestTeamService_CreateWithMembers_SuccessCreateNewTeam(t *testing.T) {
 	if ur.usersByID["u1"].TeamName != "backend" {
 		t.Fatalf("user u1 team not set")
 	}
+	types := make([]eventmodel.Type, 0, len(outbox.events))
+	for _, e := range outbox.events {
+		types = append(types, e.Type)
+	}
+	want := []eventmodel.Type{eventmodel.TypeTeamCreated, eventmodel.TypeTeamMemberAdded, eventmodel.TypeTeamMemberAdded}
+	if !reflect.DeepEqual(types, want) {
+		t.Fatalf("unexpected events: %v", types)
+	}
 }
 
 func TestTeamService_CreateWithMembers_FailsIfUserInAnotherTeam(t *testing.T) {
@@ -95,10 +203,10 @@ func TestTeamService_CreateWithMembers_FailsIfUserInAnotherTeam(t *testing.T) {
 			"u1": {UserID: "u1", Username: "Alice", TeamName: "payments", IsActive: true},
 		},
 	}
-	svc := NewTeamService(tr, ur)
+	svc := NewTeamService(&txMock{}, tr, ur, &plannerMock{}, &outboxMock{}, &auditMock{})
 	_, err := svc.CreateWithMembers(context.Background(), "backend", []usermodel.User{
 		{UserID: "u1", Username: "Alice", IsActive: true},
-	})
+	}, teammodel.SettingsUpdate{})
 	if err == nil {
 		t.Fatalf("expected error, got nil")
 	}
@@ -110,7 +218,7 @@ func TestTeamService_CreateWithMembers_FailsIfUserInAnotherTeam(t *testing.T) {
 func TestTeamService_GetTeamMembers_NotFound(t *testing.T) {
 	tr := &teamRepoMock{existsResp: false}
 	ur := &userRepoMock{}
-	svc := NewTeamService(tr, ur)
+	svc := NewTeamService(&txMock{}, tr, ur, &plannerMock{}, &outboxMock{}, &auditMock{})
 	_, err := svc.GetTeamMembers(context.Background(), "unknown")
 	if err == nil {
 		t.Fatalf("expected error, got nil")
@@ -119,3 +227,215 @@ func TestTeamService_GetTeamMembers_NotFound(t *testing.T) {
 		t.Fatalf("expected ErrTeamNotFound, got %v", err)
 	}
 }
+
+func TestTeamService_UpdateSettings_KeepsUnsetFields(t *testing.T) {
+	tr := &teamRepoMock{
+		existsResp: true,
+		settings:   teammodel.Settings{ReviewerStrategy: teammodel.ReviewerStrategyLeastLoaded},
+	}
+	svc := NewTeamService(&txMock{}, tr, &userRepoMock{}, &plannerMock{}, &outboxMock{}, &auditMock{})
+
+	got, err := svc.UpdateSettings(context.Background(), "backend", teammodel.SettingsUpdate{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ReviewerStrategy != teammodel.ReviewerStrategyLeastLoaded {
+		t.Fatalf("expected strategy to stay, got %+v", got)
+	}
+
+	strategy := teammodel.ReviewerStrategyWeighted
+	got, err = svc.UpdateSettings(context.Background(), "backend", teammodel.SettingsUpdate{ReviewerStrategy: &strategy})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ReviewerStrategy != teammodel.ReviewerStrategyWeighted {
+		t.Fatalf("expected weighted strategy, got %+v", got)
+	}
+}
+
+func TestTeamService_UpdateSettings_NotFound(t *testing.T) {
+	svc := NewTeamService(&txMock{}, &teamRepoMock{existsResp: false}, &userRepoMock{}, &plannerMock{}, &outboxMock{}, &auditMock{})
+	_, err := svc.UpdateSettings(context.Background(), "unknown", teammodel.SettingsUpdate{})
+	if err != ErrTeamNotFound {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+}
+
+func TestTeamService_UpdateSettings_ForeignTeamLead(t *testing.T) {
+	tr := &teamRepoMock{existsResp: true}
+	svc := NewTeamService(&txMock{}, tr, &userRepoMock{}, &plannerMock{}, &outboxMock{}, &auditMock{})
+	lead := core.Principal{Name: "lead", Role: core.RoleTeamLead, TeamName: "frontend"}
+	ctx := core.WithPrincipal(context.Background(), lead)
+
+	_, err := svc.UpdateSettings(ctx, "backend", teammodel.SettingsUpdate{})
+	if de, ok := core.AsDomainError(err); !ok || de.Code != core.ErrorForbidden {
+		t.Fatalf("expected FORBIDDEN, got %v", err)
+	}
+	if _, err := svc.UpdateSettings(ctx, "frontend", teammodel.SettingsUpdate{}); err != nil {
+		t.Fatalf("own team: unexpected error: %v", err)
+	}
+}
+
+func TestTeamService_CreateWithMembers_AppliesSettings(t *testing.T) {
+	tr := &teamRepoMock{existsResp: false}
+	svc := NewTeamService(&txMock{}, tr, &userRepoMock{}, &plannerMock{}, &outboxMock{}, &auditMock{})
+
+	required := 3
+	team, err := svc.CreateWithMembers(context.Background(), "security", nil, teammodel.SettingsUpdate{
+		RequiredReviewers: &required,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if team.Settings.RequiredReviewers != 3 || tr.settings.RequiredReviewers != 3 {
+		t.Fatalf("expected required reviewers to be stored, got %+v", team.Settings)
+	}
+}
+
+func TestTeamService_DeactivateUsers(t *testing.T) {
+	tr := &teamRepoMock{existsResp: true, members: []usermodel.User{
+		{UserID: "u1", TeamName: "t"},
+		{UserID: "u2", TeamName: "t"},
+		{UserID: "u3", TeamName: "t"},
+	}}
+	ur := &userRepoMock{usersByID: map[string]usermodel.User{
+		"u1": {UserID: "u1", TeamName: "t", IsActive: true},
+		"u2": {UserID: "u2", TeamName: "t", IsActive: true},
+	}}
+	planner := &plannerMock{report: prmodel.RebalanceReport{
+		Moved: []prmodel.ReviewerMove{{PullRequestID: "p1", FromUserID: "u1", ToUserID: "u3"}},
+	}}
+	outbox := &outboxMock{}
+	svc := NewTeamService(&txMock{}, tr, ur, planner, outbox, &auditMock{})
+
+	reassigned := metrics.Reassignments.WithLabelValues("t")
+	reassignedBefore := testutil.ToFloat64(reassigned)
+
+	users, report, err := svc.DeactivateUsers(context.Background(), "t", []string{"u2", "u1", "u2"})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(users) != 2 || users[0].IsActive || users[1].IsActive {
+		t.Fatalf("unexpected users: %+v", users)
+	}
+	if len(planner.ids) != 2 || planner.ids[0] != "u1" || planner.ids[1] != "u2" {
+		t.Fatalf("expected deduplicated ids, got %v", planner.ids)
+	}
+	if len(report.Moved) != 1 || len(ur.moves) != 1 {
+		t.Fatalf("expected planned moves to be applied, got %+v", ur.moves)
+	}
+	if len(planner.recorded) != 1 || planner.recorded[0] != report.Moved[0] {
+		t.Fatalf("expected the applied move recorded, got %+v", planner.recorded)
+	}
+	if got := testutil.ToFloat64(reassigned) - reassignedBefore; got != 1 {
+		t.Fatalf("expected one reassignment counted, got %v", got)
+	}
+}
+
+func TestTeamService_DeactivateUsers_ForeignUser(t *testing.T) {
+	tr := &teamRepoMock{existsResp: true, members: []usermodel.User{{UserID: "u1", TeamName: "t"}}}
+	svc := NewTeamService(&txMock{}, tr, &userRepoMock{}, &plannerMock{}, &outboxMock{}, &auditMock{})
+
+	_, _, err := svc.DeactivateUsers(context.Background(), "t", []string{"u1", "x9"})
+	derr, ok := core.AsDomainError(err)
+	if !ok || derr.Code != core.ErrorNotFound {
+		t.Fatalf("expected NOT_FOUND, got %v", err)
+	}
+	if !reflect.DeepEqual(derr.Details["user_ids"], []string{"x9"}) {
+		t.Fatalf("unexpected details: %+v", derr.Details)
+	}
+}
+
+func TestTeamService_DeleteTeam_MovesMembers(t *testing.T) {
+	tr := &teamRepoMock{existsResp: true, members: []usermodel.User{{UserID: "u1", TeamName: "new", IsActive: true}}}
+	planner := &plannerMock{}
+	outbox := &outboxMock{}
+	svc := NewTeamService(&txMock{}, tr, &userRepoMock{}, planner, outbox, &auditMock{})
+
+	if _, _, err := svc.DeleteTeam(context.Background(), "old", "new"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if tr.deleted != "old" || tr.target != "new" {
+		t.Fatalf("unexpected delete call: %q -> %q", tr.deleted, tr.target)
+	}
+	if planner.ids != nil {
+		t.Fatalf("moving members must not plan reassignments")
+	}
+	if len(outbox.events) != 1 || outbox.events[0].Type != eventmodel.TypeTeamMemberAdded {
+		t.Fatalf("expected member_added for the target team, got %+v", outbox.events)
+	}
+}
+
+func TestTeamService_DeleteTeam_DeactivatesMembers(t *testing.T) {
+	tr := &teamRepoMock{existsResp: true, members: []usermodel.User{
+		{UserID: "u1", TeamName: "old", IsActive: true},
+		{UserID: "u2", TeamName: "old", IsActive: false},
+	}}
+	planner := &plannerMock{report: prmodel.RebalanceReport{
+		Unassigned: []prmodel.UnassignedReview{{PullRequestID: "p1", UserID: "u1"}},
+		Moved:      []prmodel.ReviewerMove{{PullRequestID: "p2", FromUserID: "u1", ToUserID: "x1"}},
+	}}
+	outbox := &outboxMock{}
+	svc := NewTeamService(&txMock{}, tr, &userRepoMock{}, planner, outbox, &auditMock{})
+
+	_, report, err := svc.DeleteTeam(context.Background(), "old", "")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !reflect.DeepEqual(planner.ids, []string{"u1", "u2"}) {
+		t.Fatalf("expected all members released, got %v", planner.ids)
+	}
+	if len(report.Unassigned) != 1 || len(tr.moves) != 1 {
+		t.Fatalf("expected planned moves to be applied, got %+v", tr.moves)
+	}
+	if len(planner.recorded) != 1 || planner.recorded[0].ToUserID != "x1" {
+		t.Fatalf("expected the move recorded, got %+v", planner.recorded)
+	}
+}
+
+func TestTeamService_RenameTeam(t *testing.T) {
+	tr := &teamRepoMock{existing: map[string]bool{"old": true, "taken": true}}
+	audit := &auditMock{}
+	svc := NewTeamService(&txMock{}, tr, &userRepoMock{}, &plannerMock{}, &outboxMock{}, audit)
+
+	if err := svc.RenameTeam(context.Background(), "old", "taken"); err != ErrTeamAlreadyExists {
+		t.Fatalf("expected ErrTeamAlreadyExists, got %v", err)
+	}
+	if err := svc.RenameTeam(context.Background(), "missing", "new"); err != ErrTeamNotFound {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+	ctx := core.WithActor(context.Background(), "admin")
+	if err := svc.RenameTeam(ctx, "old", "new"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if tr.renamed != "old->new" {
+		t.Fatalf("unexpected rename call: %q", tr.renamed)
+	}
+	if len(audit.entries) != 1 || audit.entries[0].Actor != "admin" ||
+		audit.entries[0].Action != auditmodel.ActionTeamRename || audit.entries[0].EntityID != "old" {
+		t.Fatalf("unexpected audit entries: %+v", audit.entries)
+	}
+}
+
+func TestTeamService_CreateWithMembers_FailsAsUnit(t *testing.T) {
+	tr := &teamRepoMock{existsResp: false}
+	ur := &userRepoMock{createOrUpdateFn: func(u usermodel.User) error {
+		if u.UserID == "u2" {
+			return context.DeadlineExceeded
+		}
+		return nil
+	}}
+	tx := &txMock{}
+	svc := NewTeamService(tx, tr, ur, &plannerMock{}, &outboxMock{}, &auditMock{})
+
+	_, err := svc.CreateWithMembers(context.Background(), "backend", []usermodel.User{
+		{UserID: "u1", Username: "a", IsActive: true},
+		{UserID: "u2", Username: "b", IsActive: true},
+	}, teammodel.SettingsUpdate{})
+	if err == nil || !strings.Contains(err.Error(), "u2") {
+		t.Fatalf("expected member error, got %v", err)
+	}
+	if tx.calls != 1 || tx.err == nil {
+		t.Fatalf("expected failure inside one transaction, calls=%d err=%v", tx.calls, tx.err)
+	}
+}
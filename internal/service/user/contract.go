@@ -3,16 +3,42 @@ package service
 import (
 	"context"
 
+	auditmodel "avito-intern-test/internal/model/audit"
+	eventmodel "avito-intern-test/internal/model/event"
 	prmodel "avito-intern-test/internal/model/pullrequest"
 	usermodel "avito-intern-test/internal/model/user"
 )
 
+type txManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type userRepository interface {
 	GetByID(ctx context.Context, userID string) (usermodel.User, error)
-	GetReviewerPRs(ctx context.Context, ReviewerID string) ([]string, error)
+	GetProfile(ctx context.Context, userID string) (usermodel.Profile, error)
+	List(ctx context.Context, filter usermodel.ListFilter) (usermodel.Page, error)
 	SetIsActive(ctx context.Context, userID string, flag bool) (usermodel.User, error)
+	Deactivate(ctx context.Context, userIDs []string, moves []prmodel.ReviewerMove) ([]usermodel.User, error)
+	MoveToTeam(ctx context.Context, userID, teamName string, moves []prmodel.ReviewerMove) (usermodel.User, error)
+}
+
+type teamRepository interface {
+	Exists(ctx context.Context, teamName string) (bool, error)
 }
 
 type pullRequestRepository interface {
-	GetMany(ctx context.Context, PRIDs []string) ([]prmodel.PullRequest, error)
+	ReviewerPage(ctx context.Context, filter prmodel.ReviewFilter) (prmodel.ReviewPage, error)
+}
+
+type reviewerPlanner interface {
+	PlanReviewerRelease(ctx context.Context, userIDs []string) (prmodel.RebalanceReport, error)
+	RecordReviewerMoves(ctx context.Context, moves []prmodel.ReviewerMove) error
+}
+
+type outboxRepository interface {
+	Add(ctx context.Context, event eventmodel.Event) error
+}
+
+type auditRepository interface {
+	Append(ctx context.Context, entry auditmodel.Entry) error
 }
@@ -2,49 +2,239 @@ package service
 
 import (
 	"context"
+	"fmt"
 
 	"avito-intern-test/internal/core"
+	"avito-intern-test/internal/metrics"
+	auditmodel "avito-intern-test/internal/model/audit"
+	eventmodel "avito-intern-test/internal/model/event"
 	prmodel "avito-intern-test/internal/model/pullrequest"
 	usermodel "avito-intern-test/internal/model/user"
+	"avito-intern-test/internal/tracing"
 )
 
 type UserService struct {
+	txManager             txManager
 	userRepository        userRepository
 	pullRequestRepository pullRequestRepository
+	teamRepository        teamRepository
+	reviewerPlanner       reviewerPlanner
+	outboxRepository      outboxRepository
+	auditRepository       auditRepository
 }
 
 func NewUserService(
+	txManager txManager,
 	userRepository userRepository,
 	pullRequestRepository pullRequestRepository,
+	teamRepository teamRepository,
+	reviewerPlanner reviewerPlanner,
+	outboxRepository outboxRepository,
+	auditRepository auditRepository,
 ) *UserService {
 	return &UserService{
+		txManager:             txManager,
 		userRepository:        userRepository,
 		pullRequestRepository: pullRequestRepository,
+		teamRepository:        teamRepository,
+		reviewerPlanner:       reviewerPlanner,
+		outboxRepository:      outboxRepository,
+		auditRepository:       auditRepository,
 	}
 }
 
-func (s *UserService) SetIsActive(ctx context.Context, userID string, flag bool) (usermodel.User, error) {
-	user, err := s.userRepository.SetIsActive(ctx, userID, flag)
+func (s *UserService) SetIsActive(
+	ctx context.Context,
+	userID string,
+	flag bool,
+	reassign bool,
+) (_ usermodel.User, _ *prmodel.RebalanceReport, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.SetIsActive")
+	defer func() { tracing.End(span, err) }()
+
+	before, err := s.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return usermodel.User{}, nil, err
+	}
+
+	if flag || !reassign {
+		var user usermodel.User
+		err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+			var err error
+			user, err = s.userRepository.SetIsActive(ctx, userID, flag)
+			if err != nil {
+				return err
+			}
+			return s.auditRepository.Append(ctx, auditmodel.New(
+				ctx, auditmodel.ActionUserSetIsActive, auditmodel.EntityUser, userID, before, user,
+			))
+		})
+		if err != nil {
+			return usermodel.User{}, nil, err
+		}
+		return user, nil, nil
+	}
+
+	report, err := s.reviewerPlanner.PlanReviewerRelease(ctx, []string{userID})
+	if err != nil {
+		return usermodel.User{}, nil, fmt.Errorf("plan reviewer release: %w", err)
+	}
+
+	var users []usermodel.User
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		users, err = s.userRepository.Deactivate(ctx, []string{userID}, report.Moved)
+		if err != nil {
+			return err
+		}
+		if err := s.reviewerPlanner.RecordReviewerMoves(ctx, report.Moved); err != nil {
+			return err
+		}
+		return s.auditRepository.Append(ctx, auditmodel.New(
+			ctx, auditmodel.ActionUserSetIsActive, auditmodel.EntityUser, userID, before, map[string]any{
+				"user":         users[0],
+				"reassignment": report,
+			},
+		))
+	})
 	if err != nil {
-		return usermodel.User{}, err
+		return usermodel.User{}, nil, err
 	}
-	return user, nil
+	metrics.CountReassignments(before.TeamName, len(report.Moved))
+	return users[0], &report, nil
 }
 
-func (s *UserService) GetReviewerPRs(
+// MoveTeam transfers the user to another team. With reassign their open
+// reviews are handed to the old team; otherwise they keep them.
+func (s *UserService) MoveTeam(
 	ctx context.Context,
-	ReviewerID string,
-) ([]prmodel.PullRequest, error) {
-	if _, err := s.userRepository.GetByID(ctx, ReviewerID); err != nil {
-		return nil, core.Throw(core.ErrorNotFound, "user not found")
+	userID string,
+	teamName string,
+	reassign bool,
+) (_ usermodel.User, _ *prmodel.RebalanceReport, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.MoveTeam")
+	defer func() { tracing.End(span, err) }()
+
+	user, err := s.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return usermodel.User{}, nil, err
+	}
+
+	exists, _ := s.teamRepository.Exists(ctx, teamName)
+	if !exists {
+		return usermodel.User{}, nil, core.NewDomainError(core.ErrorNotFound, "team not found").
+			WithDetails(map[string]any{"team_name": teamName})
+	}
+	if user.TeamName == teamName {
+		return usermodel.User{}, nil, core.Throw(core.ErrorUserExists, "user already in team")
+	}
+
+	var report *prmodel.RebalanceReport
+	var moves []prmodel.ReviewerMove
+	if reassign {
+		planned, err := s.reviewerPlanner.PlanReviewerRelease(ctx, []string{userID})
+		if err != nil {
+			return usermodel.User{}, nil, fmt.Errorf("plan reviewer release: %w", err)
+		}
+		report = &planned
+		moves = planned.Moved
 	}
-	reviewerPRIDs, err := s.userRepository.GetReviewerPRs(ctx, ReviewerID)
+
+	var moved usermodel.User
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		moved, err = s.userRepository.MoveToTeam(ctx, userID, teamName, moves)
+		if err != nil {
+			return err
+		}
+		if err := s.reviewerPlanner.RecordReviewerMoves(ctx, moves); err != nil {
+			return err
+		}
+		err = s.record(ctx, eventmodel.TypeTeamMemberAdded, eventmodel.TeamMemberPayload{
+			TeamName: moved.TeamName,
+			UserID:   moved.UserID,
+			Username: moved.Username,
+			IsActive: moved.IsActive,
+		})
+		if err != nil {
+			return err
+		}
+		after := map[string]any{"user": moved}
+		if report != nil {
+			after["reassignment"] = report
+		}
+		return s.auditRepository.Append(ctx, auditmodel.New(
+			ctx, auditmodel.ActionUserMoveTeam, auditmodel.EntityUser, userID, user, after,
+		))
+	})
 	if err != nil {
-		return nil, err
+		return usermodel.User{}, nil, err
 	}
-	PRs, err := s.pullRequestRepository.GetMany(ctx, reviewerPRIDs)
+	metrics.CountReassignments(user.TeamName, len(moves))
+	return moved, report, nil
+}
+
+func (s *UserService) GetUser(ctx context.Context, userID string) (_ usermodel.Profile, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetUser")
+	defer func() { tracing.End(span, err) }()
+
+	profile, err := s.userRepository.GetProfile(ctx, userID)
 	if err != nil {
-		return nil, err
+		return usermodel.Profile{}, err
+	}
+	return profile, nil
+}
+
+func (s *UserService) ListUsers(ctx context.Context, filter usermodel.ListFilter) (_ usermodel.Page, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.ListUsers")
+	defer func() { tracing.End(span, err) }()
+
+	if filter.TeamName != "" {
+		exists, _ := s.teamRepository.Exists(ctx, filter.TeamName)
+		if !exists {
+			return usermodel.Page{}, core.NewDomainError(core.ErrorNotFound, "team not found").
+				WithDetails(map[string]any{"team_name": filter.TeamName})
+		}
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = usermodel.DefaultListLimit
+	}
+	if filter.Limit > usermodel.MaxListLimit {
+		filter.Limit = usermodel.MaxListLimit
+	}
+
+	page, err := s.userRepository.List(ctx, filter)
+	if err != nil {
+		return usermodel.Page{}, err
+	}
+	return page, nil
+}
+
+func (s *UserService) GetReviewerPRs(
+	ctx context.Context,
+	filter prmodel.ReviewFilter,
+) (_ prmodel.ReviewPage, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetReviewerPRs")
+	defer func() { tracing.End(span, err) }()
+
+	if _, err := s.userRepository.GetByID(ctx, filter.ReviewerID); err != nil {
+		return prmodel.ReviewPage{}, core.Throw(core.ErrorNotFound, "user not found")
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = prmodel.DefaultReviewPageLimit
+	}
+	if filter.Limit > prmodel.MaxReviewPageLimit {
+		filter.Limit = prmodel.MaxReviewPageLimit
+	}
+
+	page, err := s.pullRequestRepository.ReviewerPage(ctx, filter)
+	if err != nil {
+		return prmodel.ReviewPage{}, err
+	}
+	return page, nil
+}
+
+func (s *UserService) record(ctx context.Context, t eventmodel.Type, payload any) error {
+	if err := s.outboxRepository.Add(ctx, eventmodel.New(t, payload)); err != nil {
+		return fmt.Errorf("record %s event: %w", t, err)
 	}
-	return PRs, nil
+	return nil
 }
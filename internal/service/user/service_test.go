@@ -4,23 +4,35 @@ import (
 	"context"
 	"testing"
 
+	"avito-intern-test/internal/core"
+	"avito-intern-test/internal/metrics"
+	auditmodel "avito-intern-test/internal/model/audit"
+	eventmodel "avito-intern-test/internal/model/event"
 	prmodel "avito-intern-test/internal/model/pullrequest"
 	usermodel "avito-intern-test/internal/model/user"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 type userRepoMockForUserService struct {
 	setResp usermodel.User
 	setErr  error
-	prIDs   []string
-	prErr   error
+	moves   []prmodel.ReviewerMove
+	movedTo string
+	listed  usermodel.ListFilter
 }
 
 func (m *userRepoMockForUserService) GetByID(ctx context.Context, userID string) (usermodel.User, error) {
 	return usermodel.User{UserID: userID, Username: "x", TeamName: "t", IsActive: true}, nil
 }
 
-func (m *userRepoMockForUserService) GetReviewerPRs(ctx context.Context, ReviewerID string) ([]string, error) {
-	return m.prIDs, m.prErr
+func (m *userRepoMockForUserService) GetProfile(ctx context.Context, userID string) (usermodel.Profile, error) {
+	return usermodel.Profile{User: usermodel.User{UserID: userID, TeamName: "t", IsActive: true}, OpenReviews: 2}, nil
+}
+
+func (m *userRepoMockForUserService) List(ctx context.Context, filter usermodel.ListFilter) (usermodel.Page, error) {
+	m.listed = filter
+	return usermodel.Page{Users: []usermodel.User{{UserID: "u1"}}}, nil
 }
 
 func (m *userRepoMockForUserService) SetIsActive(ctx context.Context, userID string, flag bool) (usermodel.User, error) {
@@ -29,45 +41,240 @@ func (m *userRepoMockForUserService) SetIsActive(ctx context.Context, userID str
 	return m.setResp, m.setErr
 }
 
+func (m *userRepoMockForUserService) Deactivate(
+	ctx context.Context,
+	userIDs []string,
+	moves []prmodel.ReviewerMove,
+) ([]usermodel.User, error) {
+	m.moves = moves
+	users := make([]usermodel.User, 0, len(userIDs))
+	for _, id := range userIDs {
+		users = append(users, usermodel.User{UserID: id, TeamName: "t", IsActive: false})
+	}
+	return users, nil
+}
+
+func (m *userRepoMockForUserService) MoveToTeam(
+	ctx context.Context,
+	userID, teamName string,
+	moves []prmodel.ReviewerMove,
+) (usermodel.User, error) {
+	m.moves = moves
+	m.movedTo = teamName
+	return usermodel.User{UserID: userID, TeamName: teamName, IsActive: true}, nil
+}
+
+type teamRepoMockForUserService struct {
+	exists bool
+}
+
+func (m *teamRepoMockForUserService) Exists(ctx context.Context, teamName string) (bool, error) {
+	return m.exists, nil
+}
+
+type txMock struct {
+	calls int
+}
+
+func (m *txMock) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	return fn(ctx)
+}
+
+type auditMock struct {
+	entries []auditmodel.Entry
+}
+
+func (m *auditMock) Append(_ context.Context, entry auditmodel.Entry) error {
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+type outboxMock struct {
+	events []eventmodel.Event
+}
+
+func (m *outboxMock) Add(_ context.Context, event eventmodel.Event) error {
+	m.events = append(m.events, event)
+	return nil
+}
+
+type plannerMock struct {
+	report   prmodel.RebalanceReport
+	calls    int
+	recorded []prmodel.ReviewerMove
+}
+
+func (m *plannerMock) PlanReviewerRelease(ctx context.Context, userIDs []string) (prmodel.RebalanceReport, error) {
+	m.calls++
+	return m.report, nil
+}
+
+func (m *plannerMock) RecordReviewerMoves(_ context.Context, moves []prmodel.ReviewerMove) error {
+	m.recorded = append(m.recorded, moves...)
+	return nil
+}
+
 type prRepoMockForUserService struct {
-	prs  []prmodel.PullRequest
-	err  error
-	reqs []string
+	page   prmodel.ReviewPage
+	err    error
+	filter prmodel.ReviewFilter
 }
 
-func (m *prRepoMockForUserService) GetMany(ctx context.Context, PRIDs []string) ([]prmodel.PullRequest, error) {
-	m.reqs = append(m.reqs, PRIDs...)
-	return m.prs, m.err
+func (m *prRepoMockForUserService) ReviewerPage(ctx context.Context, filter prmodel.ReviewFilter) (prmodel.ReviewPage, error) {
+	m.filter = filter
+	return m.page, m.err
 }
 
 func TestUserService_SetIsActive(t *testing.T) {
 	ur := &userRepoMockForUserService{}
 	prr := &prRepoMockForUserService{}
-	svc := NewUserService(ur, prr)
+	planner := &plannerMock{}
+	tx := &txMock{}
+	audit := &auditMock{}
+	svc := NewUserService(tx, ur, prr, &teamRepoMockForUserService{exists: true}, planner, &outboxMock{}, audit)
 
-	user, err := svc.SetIsActive(context.Background(), "u1", false)
+	ctx := core.WithActor(context.Background(), "alice")
+	user, report, err := svc.SetIsActive(ctx, "u1", false, false)
 	if err != nil {
 		t.Fatalf("unexpected err: %v", err)
 	}
 	if user.UserID != "u1" || user.IsActive != false {
 		t.Fatalf("unexpected user result: %+v", user)
 	}
+	if report != nil || planner.calls != 0 {
+		t.Fatalf("expected no reassignment without the flag")
+	}
+	if tx.calls != 1 || len(audit.entries) != 1 {
+		t.Fatalf("expected the change audited in one transaction: tx=%d entries=%d", tx.calls, len(audit.entries))
+	}
+	entry := audit.entries[0]
+	if entry.Actor != "alice" || entry.Action != auditmodel.ActionUserSetIsActive ||
+		!entry.Before.(usermodel.User).IsActive || entry.After.(usermodel.User).IsActive {
+		t.Fatalf("unexpected audit entry: %+v", entry)
+	}
+}
+
+func TestUserService_SetIsActive_Reassign(t *testing.T) {
+	ur := &userRepoMockForUserService{}
+	planner := &plannerMock{report: prmodel.RebalanceReport{
+		Moved:      []prmodel.ReviewerMove{{PullRequestID: "p1", FromUserID: "u1", ToUserID: "u2"}},
+		Unassigned: []prmodel.UnassignedReview{{PullRequestID: "p2", UserID: "u1"}},
+	}}
+	outbox := &outboxMock{}
+	svc := NewUserService(&txMock{}, ur, &prRepoMockForUserService{}, &teamRepoMockForUserService{exists: true}, planner, outbox, &auditMock{})
+
+	reassigned := metrics.Reassignments.WithLabelValues("t")
+	reassignedBefore := testutil.ToFloat64(reassigned)
+
+	user, report, err := svc.SetIsActive(context.Background(), "u1", false, true)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if user.IsActive {
+		t.Fatalf("expected inactive user")
+	}
+	if report == nil || len(report.Unassigned) != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if len(ur.moves) != 1 || ur.moves[0].ToUserID != "u2" {
+		t.Fatalf("expected planned moves to be applied, got %+v", ur.moves)
+	}
+	if len(planner.recorded) != 1 || planner.recorded[0] != report.Moved[0] {
+		t.Fatalf("expected the applied move recorded, got %+v", planner.recorded)
+	}
+	if got := testutil.ToFloat64(reassigned) - reassignedBefore; got != 1 {
+		t.Fatalf("expected one reassignment counted, got %v", got)
+	}
 }
 
 func TestUserService_GetReviewerPRs(t *testing.T) {
-	ur := &userRepoMockForUserService{prIDs: []string{"p1", "p2"}}
+	ur := &userRepoMockForUserService{}
 	prr := &prRepoMockForUserService{
-		prs: []prmodel.PullRequest{
+		page: prmodel.ReviewPage{PullRequests: []prmodel.PullRequest{
 			{PullRequestID: "p1"},
 			{PullRequestID: "p2"},
-		},
+		}},
 	}
-	svc := NewUserService(ur, prr)
-	prs, err := svc.GetReviewerPRs(context.Background(), "u5")
+	svc := NewUserService(&txMock{}, ur, prr, &teamRepoMockForUserService{exists: true}, &plannerMock{}, &outboxMock{}, &auditMock{})
+	page, err := svc.GetReviewerPRs(context.Background(), prmodel.ReviewFilter{ReviewerID: "u5"})
 	if err != nil {
 		t.Fatalf("unexpected err: %v", err)
 	}
-	if len(prs) != 2 {
-		t.Fatalf("expected 2 PRs, got %d", len(prs))
+	if len(page.PullRequests) != 2 {
+		t.Fatalf("expected 2 PRs, got %d", len(page.PullRequests))
+	}
+	if prr.filter.Limit != prmodel.DefaultReviewPageLimit {
+		t.Fatalf("expected default limit, got %d", prr.filter.Limit)
+	}
+
+	_, _ = svc.GetReviewerPRs(context.Background(), prmodel.ReviewFilter{ReviewerID: "u5", Limit: 1000})
+	if prr.filter.Limit != prmodel.MaxReviewPageLimit {
+		t.Fatalf("expected limit to be capped, got %d", prr.filter.Limit)
+	}
+}
+
+func TestUserService_MoveTeam(t *testing.T) {
+	ur := &userRepoMockForUserService{}
+	planner := &plannerMock{report: prmodel.RebalanceReport{
+		Moved: []prmodel.ReviewerMove{{PullRequestID: "p1", FromUserID: "u1", ToUserID: "u2"}},
+	}}
+	outbox := &outboxMock{}
+	svc := NewUserService(&txMock{}, ur, &prRepoMockForUserService{}, &teamRepoMockForUserService{exists: true}, planner, outbox, &auditMock{})
+
+	user, report, err := svc.MoveTeam(context.Background(), "u1", "other", false)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if user.TeamName != "other" || report != nil || planner.calls != 0 || ur.moves != nil {
+		t.Fatalf("keeping reviews must not reassign: %+v %+v", user, report)
+	}
+	if len(outbox.events) != 1 || outbox.events[0].Type != eventmodel.TypeTeamMemberAdded ||
+		outbox.events[0].Data.(eventmodel.TeamMemberPayload).TeamName != "other" {
+		t.Fatalf("expected member_added for the new team, got %+v", outbox.events)
+	}
+
+	_, report, err = svc.MoveTeam(context.Background(), "u1", "other", true)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if report == nil || len(ur.moves) != 1 {
+		t.Fatalf("expected reviews handed over, got %+v", ur.moves)
+	}
+	if len(planner.recorded) != 1 || len(outbox.events) != 2 {
+		t.Fatalf("expected the handed over review recorded, got %+v %+v", planner.recorded, outbox.events)
+	}
+}
+
+func TestUserService_MoveTeam_SameTeam(t *testing.T) {
+	svc := NewUserService(
+		&txMock{},
+		&userRepoMockForUserService{},
+		&prRepoMockForUserService{},
+		&teamRepoMockForUserService{exists: true},
+		&plannerMock{},
+		&outboxMock{},
+		&auditMock{},
+	)
+	_, _, err := svc.MoveTeam(context.Background(), "u1", "t", false)
+	derr, ok := core.AsDomainError(err)
+	if !ok || derr.Code != core.ErrorUserExists {
+		t.Fatalf("expected USER_EXISTS, got %v", err)
+	}
+}
+
+func TestUserService_ListUsers(t *testing.T) {
+	ur := &userRepoMockForUserService{}
+	svc := NewUserService(&txMock{}, ur, &prRepoMockForUserService{}, &teamRepoMockForUserService{exists: false}, &plannerMock{}, &outboxMock{}, &auditMock{})
+
+	if _, err := svc.ListUsers(context.Background(), usermodel.ListFilter{TeamName: "ghost"}); err == nil {
+		t.Fatalf("expected unknown team to fail")
+	}
+	page, err := svc.ListUsers(context.Background(), usermodel.ListFilter{})
+	if err != nil || len(page.Users) != 1 {
+		t.Fatalf("unexpected result: %+v %v", page, err)
+	}
+	if ur.listed.Limit != usermodel.DefaultListLimit {
+		t.Fatalf("expected default limit, got %d", ur.listed.Limit)
 	}
 }
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+
+	auditmodel "avito-intern-test/internal/model/audit"
+	eventmodel "avito-intern-test/internal/model/event"
+	webhookmodel "avito-intern-test/internal/model/webhook"
+)
+
+type (
+	txManager interface {
+		WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	}
+
+	webhookRepository interface {
+		Create(ctx context.Context, wh webhookmodel.Webhook) (webhookmodel.Webhook, error)
+		GetByID(ctx context.Context, id int64) (webhookmodel.Webhook, error)
+		List(ctx context.Context) ([]webhookmodel.Webhook, error)
+		Update(ctx context.Context, wh webhookmodel.Webhook) (webhookmodel.Webhook, error)
+		Delete(ctx context.Context, id int64) error
+		Deliveries(ctx context.Context, webhookID int64, limit int) ([]webhookmodel.Delivery, error)
+	}
+
+	deliveryRepository interface {
+		ListSubscribed(ctx context.Context, t eventmodel.Type) ([]webhookmodel.Webhook, error)
+		LogDelivery(ctx context.Context, d webhookmodel.Delivery) error
+		DeliveredWebhooks(ctx context.Context, eventID string) ([]int64, error)
+	}
+
+	auditRepository interface {
+		Append(ctx context.Context, entry auditmodel.Entry) error
+	}
+)
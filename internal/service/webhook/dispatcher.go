@@ -0,0 +1,205 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	eventmodel "avito-intern-test/internal/model/event"
+	webhookmodel "avito-intern-test/internal/model/webhook"
+)
+
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+type DispatcherConfig struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Timeout        time.Duration
+}
+
+var DefaultDispatcherConfig = DispatcherConfig{
+	MaxAttempts:    5,
+	InitialBackoff: time.Second,
+	MaxBackoff:     time.Minute,
+	Timeout:        10 * time.Second,
+}
+
+// Dispatcher delivers events to subscribed webhooks, retrying failed
+// attempts with exponential backoff.
+type Dispatcher struct {
+	deliveryRepository deliveryRepository
+	client             *http.Client
+	cfg                DispatcherConfig
+	wg                 sync.WaitGroup
+}
+
+func NewDispatcher(deliveryRepository deliveryRepository, client *http.Client, cfg DispatcherConfig) *Dispatcher {
+	if client == nil {
+		client = &http.Client{Timeout: cfg.Timeout}
+	}
+	return &Dispatcher{
+		deliveryRepository: deliveryRepository,
+		client:             client,
+		cfg:                cfg,
+	}
+}
+
+// Publish delivers the event to every subscriber that has not received it
+// yet and returns once all of them are done. It fails if any subscriber is
+// still owed the event, so the caller can offer it again later; subscribers
+// that already acknowledged it are skipped then.
+func (d *Dispatcher) Publish(ctx context.Context, event eventmodel.Event) error {
+	d.wg.Add(1)
+	defer d.wg.Done()
+
+	webhooks, err := d.deliveryRepository.ListSubscribed(ctx, event.Type)
+	if err != nil {
+		return fmt.Errorf("list subscribed webhooks: %w", err)
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+	delivered, err := d.deliveryRepository.DeliveredWebhooks(ctx, event.ID)
+	if err != nil {
+		return fmt.Errorf("list delivered webhooks: %w", err)
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed []int64
+	)
+	for _, wh := range webhooks {
+		if slices.Contains(delivered, wh.ID) {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !d.deliver(ctx, wh, event, body) {
+				mu.Lock()
+				failed = append(failed, wh.ID)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		slices.Sort(failed)
+		return fmt.Errorf("delivery to webhooks %v failed", failed)
+	}
+	return nil
+}
+
+// Wait blocks until every Publish call in flight has returned.
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// deliver reports whether the webhook is done with the event: it accepted
+// it, or rejected it in a way that retrying will not change.
+func (d *Dispatcher) deliver(ctx context.Context, wh webhookmodel.Webhook, event eventmodel.Event, body []byte) bool {
+	backoff := d.cfg.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		delivery := d.send(ctx, wh, event, body)
+		delivery.Attempt = attempt
+		if err := d.deliveryRepository.LogDelivery(ctx, delivery); err != nil {
+			log.Printf("webhook %d: log delivery of %s: %v", wh.ID, event.ID, err)
+		}
+		if delivery.Success || !retryable(delivery) {
+			return true
+		}
+		if attempt == d.cfg.MaxAttempts {
+			return false
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, d.cfg.MaxBackoff)
+	}
+}
+
+func (d *Dispatcher) send(
+	ctx context.Context,
+	wh webhookmodel.Webhook,
+	event eventmodel.Event,
+	body []byte,
+) webhookmodel.Delivery {
+	delivery := webhookmodel.Delivery{
+		WebhookID: wh.ID,
+		EventID:   event.ID,
+		EventType: event.Type,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.URL, bytes.NewReader(body))
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(event.Type))
+	req.Header.Set(HeaderDelivery, event.ID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(wh.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	_ = resp.Body.Close()
+
+	delivery.StatusCode = &resp.StatusCode
+	delivery.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !delivery.Success {
+		delivery.Error = fmt.Sprintf("unexpected status %d", resp.StatusCode)
+	}
+	return delivery
+}
+
+// Sign returns the value of the signature header: an HMAC-SHA256 over the
+// timestamp and the body, so a captured payload cannot be replayed later
+// under a new timestamp.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryable reports whether a failed attempt may succeed later. Client
+// errors other than timeouts and rate limits will not.
+func retryable(d webhookmodel.Delivery) bool {
+	if d.StatusCode == nil {
+		return true
+	}
+	code := *d.StatusCode
+	return code >= 500 || code == http.StatusRequestTimeout || code == http.StatusTooManyRequests
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	auditmodel "avito-intern-test/internal/model/audit"
+	webhookmodel "avito-intern-test/internal/model/webhook"
+)
+
+const deliveriesLimit = 100
+
+type WebhookService struct {
+	txManager         txManager
+	webhookRepository webhookRepository
+	auditRepository   auditRepository
+}
+
+func NewWebhookService(
+	txManager txManager,
+	webhookRepository webhookRepository,
+	auditRepository auditRepository,
+) *WebhookService {
+	return &WebhookService{
+		txManager:         txManager,
+		webhookRepository: webhookRepository,
+		auditRepository:   auditRepository,
+	}
+}
+
+// CreateWebhook stores an active subscription with a freshly generated
+// signing secret. The secret is only ever returned here.
+func (s *WebhookService) CreateWebhook(ctx context.Context, wh webhookmodel.Webhook) (webhookmodel.Webhook, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return webhookmodel.Webhook{}, fmt.Errorf("generate webhook secret: %w", err)
+	}
+	wh.Secret = hex.EncodeToString(secret)
+	wh.IsActive = true
+
+	var created webhookmodel.Webhook
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.webhookRepository.Create(ctx, wh)
+		if err != nil {
+			return err
+		}
+		return s.auditRepository.Append(ctx, auditmodel.New(
+			ctx, auditmodel.ActionWebhookCreate, auditmodel.EntityWebhook, strconv.FormatInt(created.ID, 10),
+			nil, created,
+		))
+	})
+	if err != nil {
+		return webhookmodel.Webhook{}, err
+	}
+	return created, nil
+}
+
+func (s *WebhookService) GetWebhook(ctx context.Context, id int64) (webhookmodel.Webhook, error) {
+	return s.webhookRepository.GetByID(ctx, id)
+}
+
+func (s *WebhookService) ListWebhooks(ctx context.Context) ([]webhookmodel.Webhook, error) {
+	return s.webhookRepository.List(ctx)
+}
+
+func (s *WebhookService) UpdateWebhook(
+	ctx context.Context,
+	id int64,
+	update webhookmodel.Update,
+) (webhookmodel.Webhook, error) {
+	wh, err := s.webhookRepository.GetByID(ctx, id)
+	if err != nil {
+		return webhookmodel.Webhook{}, err
+	}
+
+	var updated webhookmodel.Webhook
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		updated, err = s.webhookRepository.Update(ctx, wh.Apply(update))
+		if err != nil {
+			return err
+		}
+		return s.auditRepository.Append(ctx, auditmodel.New(
+			ctx, auditmodel.ActionWebhookUpdate, auditmodel.EntityWebhook, strconv.FormatInt(id, 10), wh, updated,
+		))
+	})
+	if err != nil {
+		return webhookmodel.Webhook{}, err
+	}
+	return updated, nil
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id int64) error {
+	wh, err := s.webhookRepository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.webhookRepository.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.auditRepository.Append(ctx, auditmodel.New(
+			ctx, auditmodel.ActionWebhookDelete, auditmodel.EntityWebhook, strconv.FormatInt(id, 10), wh, nil,
+		))
+	})
+}
+
+func (s *WebhookService) Deliveries(ctx context.Context, id int64) ([]webhookmodel.Delivery, error) {
+	if _, err := s.webhookRepository.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.webhookRepository.Deliveries(ctx, id, deliveriesLimit)
+}
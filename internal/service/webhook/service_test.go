@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"avito-intern-test/internal/core"
+	auditmodel "avito-intern-test/internal/model/audit"
+	eventmodel "avito-intern-test/internal/model/event"
+	webhookmodel "avito-intern-test/internal/model/webhook"
+)
+
+type webhookRepoMock struct {
+	mu         sync.Mutex
+	webhooks   map[int64]webhookmodel.Webhook
+	deliveries []webhookmodel.Delivery
+}
+
+func newWebhookRepoMock(webhooks ...webhookmodel.Webhook) *webhookRepoMock {
+	m := &webhookRepoMock{webhooks: map[int64]webhookmodel.Webhook{}}
+	for _, wh := range webhooks {
+		m.webhooks[wh.ID] = wh
+	}
+	return m
+}
+
+func (m *webhookRepoMock) Create(_ context.Context, wh webhookmodel.Webhook) (webhookmodel.Webhook, error) {
+	wh.ID = int64(len(m.webhooks) + 1)
+	m.webhooks[wh.ID] = wh
+	return wh, nil
+}
+
+func (m *webhookRepoMock) GetByID(_ context.Context, id int64) (webhookmodel.Webhook, error) {
+	wh, ok := m.webhooks[id]
+	if !ok {
+		return webhookmodel.Webhook{}, core.Throw(core.ErrorNotFound, "webhook not found")
+	}
+	return wh, nil
+}
+
+func (m *webhookRepoMock) List(context.Context) ([]webhookmodel.Webhook, error) {
+	out := make([]webhookmodel.Webhook, 0, len(m.webhooks))
+	for _, wh := range m.webhooks {
+		out = append(out, wh)
+	}
+	return out, nil
+}
+
+func (m *webhookRepoMock) Update(_ context.Context, wh webhookmodel.Webhook) (webhookmodel.Webhook, error) {
+	m.webhooks[wh.ID] = wh
+	return wh, nil
+}
+
+func (m *webhookRepoMock) Delete(_ context.Context, id int64) error {
+	delete(m.webhooks, id)
+	return nil
+}
+
+func (m *webhookRepoMock) Deliveries(context.Context, int64, int) ([]webhookmodel.Delivery, error) {
+	return m.deliveries, nil
+}
+
+func (m *webhookRepoMock) ListSubscribed(_ context.Context, t eventmodel.Type) ([]webhookmodel.Webhook, error) {
+	var out []webhookmodel.Webhook
+	for _, wh := range m.webhooks {
+		if wh.Subscribed(t) {
+			out = append(out, wh)
+		}
+	}
+	return out, nil
+}
+
+func (m *webhookRepoMock) DeliveredWebhooks(_ context.Context, eventID string) ([]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var ids []int64
+	for _, d := range m.deliveries {
+		if d.EventID == eventID && d.Success {
+			ids = append(ids, d.WebhookID)
+		}
+	}
+	return ids, nil
+}
+
+func (m *webhookRepoMock) LogDelivery(_ context.Context, d webhookmodel.Delivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries = append(m.deliveries, d)
+	return nil
+}
+
+type txMock struct{}
+
+func (txMock) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type auditMock struct {
+	entries []auditmodel.Entry
+}
+
+func (m *auditMock) Append(_ context.Context, entry auditmodel.Entry) error {
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+var testDispatcherConfig = DispatcherConfig{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     5 * time.Millisecond,
+	Timeout:        time.Second,
+}
+
+func TestDispatcher_SignsAndRetries(t *testing.T) {
+	var calls atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		want := Sign("secret", r.Header.Get(HeaderTimestamp), body)
+		if r.Header.Get(HeaderSignature) != want {
+			t.Errorf("bad signature %q, want %q", r.Header.Get(HeaderSignature), want)
+		}
+		if r.Header.Get(HeaderEvent) != string(eventmodel.TypePullRequestMerged) {
+			t.Errorf("unexpected event header %q", r.Header.Get(HeaderEvent))
+		}
+		var ev eventmodel.Event
+		if err := json.Unmarshal(body, &ev); err != nil || ev.ID != r.Header.Get(HeaderDelivery) {
+			t.Errorf("unexpected payload %s: %v", body, err)
+		}
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	repo := newWebhookRepoMock(
+		webhookmodel.Webhook{ID: 1, URL: receiver.URL, Secret: "secret", IsActive: true,
+			Events: []eventmodel.Type{eventmodel.TypePullRequestMerged}},
+		webhookmodel.Webhook{ID: 2, URL: receiver.URL, Secret: "other", IsActive: true,
+			Events: []eventmodel.Type{eventmodel.TypeReviewersAssigned}},
+	)
+	d := NewDispatcher(repo, receiver.Client(), testDispatcherConfig)
+
+	ev := eventmodel.New(eventmodel.TypePullRequestMerged, eventmodel.PullRequestPayload{PullRequestID: "pr-1"})
+	if err := d.Publish(context.Background(), ev); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	if calls.Load() != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls.Load())
+	}
+	if len(repo.deliveries) != 3 {
+		t.Fatalf("expected every attempt logged, got %+v", repo.deliveries)
+	}
+	last := repo.deliveries[2]
+	if !last.Success || last.Attempt != 3 || *last.StatusCode != http.StatusNoContent || last.WebhookID != 1 {
+		t.Fatalf("unexpected final delivery: %+v", last)
+	}
+	if repo.deliveries[0].Success || repo.deliveries[0].Error == "" {
+		t.Fatalf("first attempt must be logged as failed: %+v", repo.deliveries[0])
+	}
+}
+
+func TestDispatcher_GivesUp(t *testing.T) {
+	var calls atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	repo := newWebhookRepoMock(webhookmodel.Webhook{ID: 1, URL: receiver.URL, IsActive: true,
+		Events: []eventmodel.Type{eventmodel.TypePullRequestMerged}})
+	d := NewDispatcher(repo, receiver.Client(), testDispatcherConfig)
+
+	if err := d.Publish(context.Background(), eventmodel.New(eventmodel.TypePullRequestMerged, nil)); err == nil {
+		t.Fatal("expected an error so the event is offered again")
+	}
+	if calls.Load() != int32(testDispatcherConfig.MaxAttempts) || len(repo.deliveries) != testDispatcherConfig.MaxAttempts {
+		t.Fatalf("expected %d attempts, got %d", testDispatcherConfig.MaxAttempts, calls.Load())
+	}
+}
+
+func TestDispatcher_SkipsDeliveredWebhooks(t *testing.T) {
+	var calls atomic.Int32
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer up.Close()
+
+	events := []eventmodel.Type{eventmodel.TypePullRequestMerged}
+	repo := newWebhookRepoMock(
+		webhookmodel.Webhook{ID: 1, URL: up.URL, IsActive: true, Events: events},
+		webhookmodel.Webhook{ID: 2, URL: down.URL, IsActive: true, Events: events},
+	)
+	d := NewDispatcher(repo, nil, testDispatcherConfig)
+
+	ev := eventmodel.New(eventmodel.TypePullRequestMerged, nil)
+	for range 2 {
+		if err := d.Publish(context.Background(), ev); err == nil {
+			t.Fatal("expected an error while webhook 2 is down")
+		}
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("acknowledged webhook must receive the event once, got %d", calls.Load())
+	}
+}
+
+func TestDispatcher_NoRetryOnClientError(t *testing.T) {
+	var calls atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer receiver.Close()
+
+	repo := newWebhookRepoMock(webhookmodel.Webhook{ID: 1, URL: receiver.URL, IsActive: true,
+		Events: []eventmodel.Type{eventmodel.TypePullRequestMerged}})
+	d := NewDispatcher(repo, receiver.Client(), testDispatcherConfig)
+
+	if err := d.Publish(context.Background(), eventmodel.New(eventmodel.TypePullRequestMerged, nil)); err != nil {
+		t.Fatalf("a permanent rejection must not be retried: %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected a single attempt, got %d", calls.Load())
+	}
+}
+
+func TestWebhookService_CreateAndUpdate(t *testing.T) {
+	repo := newWebhookRepoMock()
+	audit := &auditMock{}
+	svc := NewWebhookService(txMock{}, repo, audit)
+
+	wh, err := svc.CreateWebhook(context.Background(), webhookmodel.Webhook{
+		URL:    "http://example.test/hook",
+		Events: []eventmodel.Type{eventmodel.TypePullRequestMerged},
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if len(wh.Secret) != 64 || !wh.IsActive {
+		t.Fatalf("expected generated secret and active webhook: %+v", wh)
+	}
+
+	inactive := false
+	updated, err := svc.UpdateWebhook(context.Background(), wh.ID, webhookmodel.Update{IsActive: &inactive})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated.IsActive || updated.URL != wh.URL || updated.Secret != wh.Secret {
+		t.Fatalf("unexpected update result: %+v", updated)
+	}
+
+	if _, err := svc.UpdateWebhook(context.Background(), 42, webhookmodel.Update{}); err == nil {
+		t.Fatalf("expected unknown webhook to fail")
+	}
+
+	if len(audit.entries) != 2 || audit.entries[1].Action != auditmodel.ActionWebhookUpdate ||
+		audit.entries[1].EntityID != "1" || !audit.entries[1].Before.(webhookmodel.Webhook).IsActive {
+		t.Fatalf("unexpected audit entries: %+v", audit.entries)
+	}
+}
@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	rec := tracetest.NewSpanRecorder()
+	prev := tracer
+	tracer = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)).Tracer("test")
+	t.Cleanup(func() { tracer = prev })
+	return rec
+}
+
+func runQuery(ctx context.Context, sql string, err error) {
+	var qt QueryTracer
+	ctx = qt.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: sql})
+	qt.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1"), Err: err})
+}
+
+func TestQueryTracer(t *testing.T) {
+	rec := recordSpans(t)
+
+	runQuery(context.Background(), "SELECT 1", nil)
+	if n := len(rec.Ended()); n != 0 {
+		t.Fatalf("expected no spans without a parent, got %d", n)
+	}
+
+	ctx, parent := Start(context.Background(), "PRService.CreatePR")
+	runQuery(ctx, "\n\t\tselect user_id FROM users", nil)
+	runQuery(ctx, "SELECT 1", pgx.ErrNoRows)
+	runQuery(ctx, "INSERT INTO users VALUES ($1)", errors.New("duplicate key"))
+	parent.End()
+
+	spans := rec.Ended()
+	if len(spans) != 4 {
+		t.Fatalf("expected 3 query spans and the parent, got %d", len(spans))
+	}
+	for _, s := range spans[:3] {
+		if s.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Fatalf("span %s is not a child of the operation span", s.Name())
+		}
+	}
+	if spans[0].Name() != "SELECT" || spans[2].Name() != "INSERT" {
+		t.Fatalf("unexpected span names: %s, %s", spans[0].Name(), spans[2].Name())
+	}
+	if spans[1].Status().Code == codes.Error {
+		t.Fatal("ErrNoRows must not mark the span as failed")
+	}
+	if spans[2].Status().Code != codes.Error {
+		t.Fatal("expected the failed statement to be marked as error")
+	}
+}
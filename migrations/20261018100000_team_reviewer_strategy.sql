@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE teams
+ADD COLUMN reviewer_strategy VARCHAR NOT NULL DEFAULT 'LEAST_LOADED';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE teams DROP COLUMN IF EXISTS reviewer_strategy;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE teams
+ADD COLUMN required_reviewers INT NOT NULL DEFAULT 2 CHECK (required_reviewers > 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE teams DROP COLUMN IF EXISTS required_reviewers;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE pr_reviewers
+ADD COLUMN replaced_by TEXT NULL REFERENCES users(user_id) ON DELETE SET NULL;
+
+DROP INDEX IF EXISTS unique_pr_reviewer_idx;
+CREATE UNIQUE INDEX unique_active_pr_reviewer_idx ON pr_reviewers(pull_request_id, user_id) WHERE replaced_at IS NULL;
+CREATE INDEX pr_reviewers_history_idx ON pr_reviewers(pull_request_id, assigned_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM pr_reviewers WHERE replaced_at IS NOT NULL;
+
+DROP INDEX IF EXISTS pr_reviewers_history_idx;
+DROP INDEX IF EXISTS unique_active_pr_reviewer_idx;
+CREATE UNIQUE INDEX unique_pr_reviewer_idx ON pr_reviewers(pull_request_id, user_id);
+
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS replaced_by;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE pr_reviewers
+ADD COLUMN verdict VARCHAR NULL CHECK (verdict IN ('APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+ADD COLUMN reviewed_at TIMESTAMP NULL;
+
+ALTER TABLE teams
+ADD COLUMN required_approvals INT NOT NULL DEFAULT 0 CHECK (required_approvals >= 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE teams DROP COLUMN IF EXISTS required_approvals;
+
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS verdict;
+-- +goose StatementEnd
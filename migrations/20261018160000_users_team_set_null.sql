@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ALTER COLUMN team_name DROP NOT NULL;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_team_name_fkey;
+ALTER TABLE users
+ADD CONSTRAINT users_team_name_fkey
+FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_team_name_fkey;
+ALTER TABLE users
+ADD CONSTRAINT users_team_name_fkey
+FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE RESTRICT;
+
+ALTER TABLE users ALTER COLUMN team_name SET NOT NULL;
+-- +goose StatementEnd
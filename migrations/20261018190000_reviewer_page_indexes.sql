@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS pr_reviewers_active_user_idx
+    ON pr_reviewers (user_id, pull_request_id)
+    WHERE replaced_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS pull_requests_created_at_id_idx
+    ON pull_requests (created_at DESC, pull_request_id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS pull_requests_created_at_id_idx;
+DROP INDEX IF EXISTS pr_reviewers_active_user_idx;
+-- +goose StatementEnd
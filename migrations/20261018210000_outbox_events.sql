@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS outbox_events (
+    event_id TEXT PRIMARY KEY,
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL,
+    published_at TIMESTAMP,
+    dead_at TIMESTAMP
+);
+
+CREATE INDEX outbox_events_due_idx ON outbox_events(next_attempt_at)
+    WHERE published_at IS NULL AND dead_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_event_idx ON webhook_deliveries(event_id) WHERE success;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS webhook_deliveries_event_idx;
+DROP TABLE IF EXISTS outbox_events;
+-- +goose StatementEnd